@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"errors"
+	"strconv"
+
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/service"
+	"mcp-manager/pkg/common"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AuthProfileHandler 鉴权配置的 HTTP 处理器
+type AuthProfileHandler struct {
+	Service service.AuthProfileService
+}
+
+// NewAuthProfileHandler 构造函数
+func NewAuthProfileHandler(s service.AuthProfileService) *AuthProfileHandler {
+	return &AuthProfileHandler{Service: s}
+}
+
+// CreateFromSchemeRequest 为按文档中声明的 securityScheme 创建鉴权配置的请求
+type CreateFromSchemeRequest struct {
+	Scheme string `json:"scheme" binding:"required"` // securitySchemes 中的名称
+	model.AuthProfile
+}
+
+// BindDocumentAuthRequest 为绑定文档鉴权配置的请求
+type BindDocumentAuthRequest struct {
+	AuthProfileID *uint `json:"auth_profile_id"` // 为 null 时解除绑定
+}
+
+// ListProfiles godoc
+// @Summary 查询所有鉴权配置
+// @Tags AuthProfile
+// @Produce json
+// @Success 200 {array} model.AuthProfile
+// @Failure 500 {object} map[string]string
+// @Router /api/auth/profiles [get]
+func (h *AuthProfileHandler) ListProfiles(c *gin.Context) {
+	profiles, err := h.Service.ListProfiles(c.Request.Context())
+	if err != nil {
+		common.Error(c, 500, err.Error())
+		return
+	}
+	common.Success(c, profiles)
+}
+
+// CreateProfile godoc
+// @Summary 创建鉴权配置
+// @Description 支持 api_key（header/query/cookie）、basic、bearer 与 oauth2_client_credentials 四种类型；
+// @Description 明文凭证以 secret 加密保存，只能写入，不会在响应中返回
+// @Tags AuthProfile
+// @Accept json
+// @Produce json
+// @Param data body model.AuthProfile true "鉴权配置"
+// @Success 200 {object} model.AuthProfile
+// @Failure 400 {object} map[string]string
+// @Router /api/auth/profiles [post]
+func (h *AuthProfileHandler) CreateProfile(c *gin.Context) {
+	var profile model.AuthProfile
+	if err := c.ShouldBindJSON(&profile); err != nil {
+		common.Error(c, 400, "invalid body")
+		return
+	}
+	if err := h.Service.CreateProfile(c.Request.Context(), &profile); err != nil {
+		common.Error(c, 400, err.Error())
+		return
+	}
+	common.Success(c, profile)
+}
+
+// GetProfileByID godoc
+// @Summary 根据ID查询鉴权配置
+// @Tags AuthProfile
+// @Produce json
+// @Param id path int true "AuthProfile ID"
+// @Success 200 {object} model.AuthProfile
+// @Failure 404 {object} map[string]string
+// @Router /api/auth/profiles/{id} [get]
+func (h *AuthProfileHandler) GetProfileByID(c *gin.Context) {
+	id, ok := authProfileID(c)
+	if !ok {
+		return
+	}
+	profile, err := h.Service.GetProfileByID(c.Request.Context(), id)
+	if err != nil {
+		authProfileError(c, err)
+		return
+	}
+	common.Success(c, profile)
+}
+
+// UpdateProfile godoc
+// @Summary 更新鉴权配置
+// @Description 以请求中的配置整体替换，已缓存的 OAuth2 token 随之失效
+// @Tags AuthProfile
+// @Accept json
+// @Produce json
+// @Param id path int true "AuthProfile ID"
+// @Param data body model.AuthProfile true "鉴权配置"
+// @Success 200 {object} model.AuthProfile
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/auth/profiles/{id} [put]
+func (h *AuthProfileHandler) UpdateProfile(c *gin.Context) {
+	id, ok := authProfileID(c)
+	if !ok {
+		return
+	}
+	var profile model.AuthProfile
+	if err := c.ShouldBindJSON(&profile); err != nil {
+		common.Error(c, 400, "invalid body")
+		return
+	}
+	profile.ID = id
+	if err := h.Service.UpdateProfile(c.Request.Context(), &profile); err != nil {
+		authProfileError(c, err)
+		return
+	}
+	common.Success(c, profile)
+}
+
+// DeleteProfile godoc
+// @Summary 删除鉴权配置
+// @Description 引用该配置的文档与MCP Server随之解除绑定
+// @Tags AuthProfile
+// @Produce json
+// @Param id path int true "AuthProfile ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/auth/profiles/{id} [delete]
+func (h *AuthProfileHandler) DeleteProfile(c *gin.Context) {
+	id, ok := authProfileID(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.DeleteProfile(c.Request.Context(), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			common.Error(c, 404, "auth profile not found")
+			return
+		}
+		common.Error(c, 500, err.Error())
+		return
+	}
+	common.Success(c, gin.H{"message": "deleted"})
+}
+
+// ListSecuritySchemes godoc
+// @Summary 查询文档中声明的securitySchemes
+// @Description 返回每个声明对应的鉴权配置类型，无法支持的声明附带 unsupported_reason
+// @Tags AuthProfile
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Success 200 {array} model.SecurityScheme
+// @Failure 404 {object} map[string]string
+// @Router /api/swagger/documents/{id}/security-schemes [get]
+func (h *AuthProfileHandler) ListSecuritySchemes(c *gin.Context) {
+	id, ok := authProfileID(c)
+	if !ok {
+		return
+	}
+	schemes, err := h.Service.SecuritySchemes(c.Request.Context(), id)
+	if err != nil {
+		authProfileError(c, err)
+		return
+	}
+	common.Success(c, schemes)
+}
+
+// CreateFromScheme godoc
+// @Summary 按文档中声明的securityScheme创建鉴权配置
+// @Description 类型、位置与token地址取自文档声明，凭证由请求提供；文档尚未绑定鉴权配置时自动绑定
+// @Tags AuthProfile
+// @Accept json
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Param data body CreateFromSchemeRequest true "securityScheme 名称与凭证"
+// @Success 200 {object} model.AuthProfile
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/swagger/documents/{id}/auth-profiles [post]
+func (h *AuthProfileHandler) CreateFromScheme(c *gin.Context) {
+	id, ok := authProfileID(c)
+	if !ok {
+		return
+	}
+	var req CreateFromSchemeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Error(c, 400, "invalid body")
+		return
+	}
+	if err := h.Service.CreateFromScheme(c.Request.Context(), id, req.Scheme, &req.AuthProfile); err != nil {
+		authProfileError(c, err)
+		return
+	}
+	common.Success(c, req.AuthProfile)
+}
+
+// BindDocument godoc
+// @Summary 绑定文档的鉴权配置
+// @Description 文档下的接口测试与MCP工具调用使用该配置，auth_profile_id 为 null 时解除绑定
+// @Tags AuthProfile
+// @Accept json
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Param data body BindDocumentAuthRequest true "鉴权配置 ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/swagger/documents/{id}/auth-profile [put]
+func (h *AuthProfileHandler) BindDocument(c *gin.Context) {
+	id, ok := authProfileID(c)
+	if !ok {
+		return
+	}
+	var req BindDocumentAuthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Error(c, 400, "invalid body")
+		return
+	}
+	if err := h.Service.BindDocument(c.Request.Context(), id, req.AuthProfileID); err != nil {
+		authProfileError(c, err)
+		return
+	}
+	common.Success(c, gin.H{"message": "bound"})
+}
+
+// authProfileID 解析路径中的 id，不合法时返回 400
+func authProfileID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// authProfileError 把错误转换为响应，记录不存在时返回 404，其余视为请求不合法
+func authProfileError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		common.Error(c, 404, "not found")
+		return
+	}
+	common.Error(c, 400, err.Error())
+}
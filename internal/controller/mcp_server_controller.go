@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"strconv"
+
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/service"
+	"mcp-manager/pkg/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MCPServerHandler MCP Server 实例管理的 HTTP 处理器
+type MCPServerHandler struct {
+	Service service.MCPServerService
+}
+
+// NewMCPServerHandler 构造函数
+func NewMCPServerHandler(s service.MCPServerService) *MCPServerHandler {
+	return &MCPServerHandler{Service: s}
+}
+
+// ListServers godoc
+// @Summary 查询所有MCP Server
+// @Tags MCPServer
+// @Produce json
+// @Success 200 {array} model.MCPServer
+// @Failure 500 {object} map[string]string
+// @Router /api/mcp/servers [get]
+func (h *MCPServerHandler) ListServers(c *gin.Context) {
+	servers, err := h.Service.ListServers(c.Request.Context())
+	if err != nil {
+		common.Error(c, 500, err.Error())
+		return
+	}
+	common.Success(c, servers)
+}
+
+// CreateServer godoc
+// @Summary 创建MCP Server
+// @Description 由选定的接口组成一个MCP Server，创建后可通过 /mcp/servers/{name} 接入
+// @Tags MCPServer
+// @Accept json
+// @Produce json
+// @Param data body model.MCPServer true "MCP Server"
+// @Success 200 {object} model.MCPServer
+// @Failure 400 {object} map[string]string
+// @Router /api/mcp/servers [post]
+func (h *MCPServerHandler) CreateServer(c *gin.Context) {
+	var server model.MCPServer
+	if err := c.ShouldBindJSON(&server); err != nil {
+		common.Error(c, 400, "invalid body")
+		return
+	}
+	if err := h.Service.CreateServer(c.Request.Context(), &server); err != nil {
+		common.Error(c, 400, err.Error())
+		return
+	}
+	common.Success(c, server)
+}
+
+// GetServerByID godoc
+// @Summary 根据ID查询MCP Server
+// @Tags MCPServer
+// @Produce json
+// @Param id path int true "MCPServer ID"
+// @Success 200 {object} model.MCPServer
+// @Failure 400 {object} map[string]string
+// @Router /api/mcp/servers/{id} [get]
+func (h *MCPServerHandler) GetServerByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return
+	}
+	server, err := h.Service.GetServerByID(c.Request.Context(), uint(id))
+	if err != nil {
+		common.Error(c, 404, err.Error())
+		return
+	}
+	common.Success(c, server)
+}
+
+// UpdateServer godoc
+// @Summary 更新MCP Server
+// @Description 工具绑定以请求中的 tools 整体替换
+// @Tags MCPServer
+// @Accept json
+// @Produce json
+// @Param id path int true "MCPServer ID"
+// @Param data body model.MCPServer true "MCP Server"
+// @Success 200 {object} model.MCPServer
+// @Failure 400 {object} map[string]string
+// @Router /api/mcp/servers/{id} [put]
+func (h *MCPServerHandler) UpdateServer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return
+	}
+	var server model.MCPServer
+	if err := c.ShouldBindJSON(&server); err != nil {
+		common.Error(c, 400, "invalid body")
+		return
+	}
+	server.ID = uint(id)
+	if err := h.Service.UpdateServer(c.Request.Context(), &server); err != nil {
+		common.Error(c, 400, err.Error())
+		return
+	}
+	common.Success(c, server)
+}
+
+// DeleteServer godoc
+// @Summary 删除MCP Server及其工具绑定
+// @Tags MCPServer
+// @Produce json
+// @Param id path int true "MCPServer ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /api/mcp/servers/{id} [delete]
+func (h *MCPServerHandler) DeleteServer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return
+	}
+	if err := h.Service.DeleteServer(c.Request.Context(), uint(id)); err != nil {
+		common.Error(c, 500, err.Error())
+		return
+	}
+	common.Success(c, gin.H{"message": "deleted"})
+}
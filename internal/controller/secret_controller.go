@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"errors"
+	"strconv"
+
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/service"
+	"mcp-manager/pkg/common"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SecretHandler secret 管理的 HTTP 处理器
+type SecretHandler struct {
+	Service service.SecretService
+}
+
+// NewSecretHandler 构造函数
+func NewSecretHandler(s service.SecretService) *SecretHandler {
+	return &SecretHandler{Service: s}
+}
+
+// SecretRequest 为创建或更新 secret 的请求
+type SecretRequest struct {
+	Name        string  `json:"name"`        // 名称，创建后不可修改，通过 {{secret:NAME}} 引用
+	Description string  `json:"description"` // 描述
+	Value       *string `json:"value"`       // 取值，更新时省略则保持不变
+}
+
+// ListSecrets godoc
+// @Summary 查询所有secret
+// @Description 只返回名称、描述与主密钥ID等元信息，不返回取值
+// @Tags Secret
+// @Produce json
+// @Success 200 {array} model.Secret
+// @Failure 500 {object} map[string]string
+// @Router /api/secrets [get]
+func (h *SecretHandler) ListSecrets(c *gin.Context) {
+	secrets, err := h.Service.ListSecrets(c.Request.Context())
+	if err != nil {
+		common.Error(c, 500, err.Error())
+		return
+	}
+	common.Success(c, secrets)
+}
+
+// CreateSecret godoc
+// @Summary 创建secret
+// @Description 取值以信封加密保存，之后只能通过 {{secret:NAME}} 在接口、MCP Server 与鉴权配置中引用
+// @Tags Secret
+// @Accept json
+// @Produce json
+// @Param data body SecretRequest true "secret"
+// @Success 200 {object} model.Secret
+// @Failure 400 {object} map[string]string
+// @Router /api/secrets [post]
+func (h *SecretHandler) CreateSecret(c *gin.Context) {
+	var req SecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Value == nil {
+		common.Error(c, 400, "invalid body")
+		return
+	}
+	secret := model.Secret{Name: req.Name, Description: req.Description}
+	if err := h.Service.CreateSecret(c.Request.Context(), &secret, *req.Value); err != nil {
+		common.Error(c, 400, err.Error())
+		return
+	}
+	common.Success(c, secret)
+}
+
+// GetSecretByID godoc
+// @Summary 根据ID查询secret
+// @Tags Secret
+// @Produce json
+// @Param id path int true "Secret ID"
+// @Success 200 {object} model.Secret
+// @Failure 404 {object} map[string]string
+// @Router /api/secrets/{id} [get]
+func (h *SecretHandler) GetSecretByID(c *gin.Context) {
+	id, ok := secretID(c)
+	if !ok {
+		return
+	}
+	secret, err := h.Service.GetSecretByID(c.Request.Context(), id)
+	if err != nil {
+		secretError(c, err)
+		return
+	}
+	common.Success(c, secret)
+}
+
+// UpdateSecret godoc
+// @Summary 更新secret
+// @Description 名称不可修改；提供 value 时以当前主密钥重新加密
+// @Tags Secret
+// @Accept json
+// @Produce json
+// @Param id path int true "Secret ID"
+// @Param data body SecretRequest true "secret"
+// @Success 200 {object} model.Secret
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/secrets/{id} [put]
+func (h *SecretHandler) UpdateSecret(c *gin.Context) {
+	id, ok := secretID(c)
+	if !ok {
+		return
+	}
+	var req SecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Error(c, 400, "invalid body")
+		return
+	}
+	secret := model.Secret{ID: id, Description: req.Description}
+	if err := h.Service.UpdateSecret(c.Request.Context(), &secret, req.Value); err != nil {
+		secretError(c, err)
+		return
+	}
+	common.Success(c, secret)
+}
+
+// DeleteSecret godoc
+// @Summary 删除secret
+// @Description 引用该 secret 的请求在发送时返回错误
+// @Tags Secret
+// @Produce json
+// @Param id path int true "Secret ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/secrets/{id} [delete]
+func (h *SecretHandler) DeleteSecret(c *gin.Context) {
+	id, ok := secretID(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.DeleteSecret(c.Request.Context(), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			common.Error(c, 404, "secret not found")
+			return
+		}
+		common.Error(c, 500, err.Error())
+		return
+	}
+	common.Success(c, gin.H{"message": "deleted"})
+}
+
+// RotateKeys godoc
+// @Summary 轮换主密钥
+// @Description 以当前主密钥（secrets.active_key）重新加密所有 secret 的数据密钥，完成后即可从配置中移除旧密钥
+// @Tags Secret
+// @Produce json
+// @Success 200 {object} map[string]int
+// @Failure 400 {object} map[string]string
+// @Router /api/secrets/rotate [post]
+func (h *SecretHandler) RotateKeys(c *gin.Context) {
+	rotated, err := h.Service.RotateKeys(c.Request.Context())
+	if err != nil {
+		secretError(c, err)
+		return
+	}
+	common.Success(c, gin.H{"rotated": rotated})
+}
+
+// secretID 解析路径中的 id，不合法时返回 400
+func secretID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// secretError 把错误转换为响应，记录不存在时返回 404，其余视为请求不合法
+func secretError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		common.Error(c, 404, "secret not found")
+		return
+	}
+	common.Error(c, 400, err.Error())
+}
@@ -1,16 +1,22 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"mcp-manager/internal/dao"
 	"mcp-manager/internal/utils/parser"
 	"mcp-manager/pkg/common"
 	"mime/multipart"
 	"strconv"
+	"strings"
 
 	"mcp-manager/internal/model"
 	"mcp-manager/internal/service"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // SwaggerFileRequest 用于文件上传方式的参数
@@ -58,15 +64,13 @@ func ValidateSwaggerByFile(c *gin.Context) {
 		common.Error(c, 500, "failed to read file")
 		return
 	}
-	parserObj := parser.NewSwaggerParser()
-	loader := parserObj.(*parser.OpenAPI3Parser)
-	openapiLoader := loader
-	doc, err := openapiLoader.ParseFromData(data)
+	swaggerParser := parser.NewSwaggerParser()
+	doc, err := swaggerParser.ParseFromData(data)
 	if err != nil {
 		common.Error(c, 400, err.Error())
 		return
 	}
-	if err := openapiLoader.Validate(doc); err != nil {
+	if err := swaggerParser.Validate(doc); err != nil {
 		common.Error(c, 400, err.Error())
 		return
 	}
@@ -89,21 +93,28 @@ func ValidateSwaggerByText(c *gin.Context) {
 		common.Error(c, 400, "content is required")
 		return
 	}
-	parserObj := parser.NewSwaggerParser()
-	loader := parserObj.(*parser.OpenAPI3Parser)
-	openapiLoader := loader
-	doc, err := openapiLoader.ParseFromData([]byte(req.Content))
+	swaggerParser := parser.NewSwaggerParser()
+	doc, err := swaggerParser.ParseFromData([]byte(req.Content))
 	if err != nil {
 		common.Error(c, 400, err.Error())
 		return
 	}
-	if err := openapiLoader.Validate(doc); err != nil {
+	if err := swaggerParser.Validate(doc); err != nil {
 		common.Error(c, 400, err.Error())
 		return
 	}
 	common.Success(c, gin.H{"message": "swagger validated successfully"})
 }
 
+// SwaggerImportRequest 用于导入Swagger文档的参数
+// swagger:model SwaggerImportRequest
+type SwaggerImportRequest struct {
+	// 粘贴的Swagger内容字符串，必须为有效的OpenAPI内容
+	Content string `json:"content" binding:"required"`
+	// 导入人
+	CreatedBy string `json:"created_by"`
+}
+
 // SwaggerServiceHandler 提供对 SwaggerService 的 HTTP 封装
 type SwaggerServiceHandler struct {
 	Service service.SwaggerService
@@ -116,49 +127,130 @@ func NewSwaggerServiceHandler(s service.SwaggerService) *SwaggerServiceHandler {
 
 // ParseAndSave godoc
 // @Summary 解析并保存Swagger接口
-// @Description 上传Swagger内容并保存所有接口到数据库
+// @Description 上传Swagger内容，保存文档及其所有接口到数据库
 // @Tags Swagger
 // @Accept json
 // @Produce json
-// @Param data body SwaggerTextRequest true "Swagger内容"
+// @Param data body SwaggerImportRequest true "Swagger内容"
 // @Success 200 {array} model.APIEndpoint
 // @Failure 400 {object} map[string]string
 // @Router /api/swagger/parse [post]
 func (h *SwaggerServiceHandler) ParseAndSave(c *gin.Context) {
-	var req SwaggerTextRequest
+	var req SwaggerImportRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		common.Error(c, 400, "content is required")
 		return
 	}
-	endpoints, err := h.Service.ParseAndSave(c.Request.Context(), []byte(req.Content))
+	endpoints, err := h.Service.ParseAndSave(c.Request.Context(), []byte(req.Content), req.CreatedBy)
 	if err != nil {
-		common.Error(c, 400, err.Error())
+		importError(c, err)
 		return
 	}
 	common.Success(c, endpoints)
 }
 
+// EndpointListRequest 为接口列表的查询参数
+// 分页可使用 page/size，也可使用上一页返回的 next_cursor（仅按 id 排序时）
+type EndpointListRequest struct {
+	SwaggerID  uint   `form:"swagger_id"`  // 所属文档，不传时查询所有文档
+	Method     string `form:"method"`      // HTTP 方法
+	Tag        string `form:"tag"`         // 标签
+	PathPrefix string `form:"path_prefix"` // 路径前缀
+	Keyword    string `form:"q"`           // 在摘要、描述、operationId 中搜索
+	Sort       string `form:"sort"`        // id、path、method、summary、operation_id、created_at、updated_at
+	Order      string `form:"order"`       // asc（默认）或 desc
+	Page       int    `form:"page"`        // 页码，从 1 开始
+	Size       int    `form:"size"`        // 每页条数，默认 20，最大 200
+	Cursor     string `form:"cursor"`      // 游标
+}
+
 // ListAPIEndpoints godoc
-// @Summary 查询指定swaggerID下所有APIEndpoint
+// @Summary 分页查询APIEndpoint，支持过滤、搜索与排序
+// @Description 响应的 total 为满足条件的总数，游标分页时 next_cursor 为下一页的游标
 // @Tags Swagger
 // @Produce json
-// @Param swagger_id query int true "SwaggerID"
+// @Param swagger_id query int false "SwaggerID"
+// @Param method query string false "HTTP 方法"
+// @Param tag query string false "标签"
+// @Param path_prefix query string false "路径前缀"
+// @Param q query string false "在摘要、描述、operationId 中搜索"
+// @Param sort query string false "排序字段" Enums(id, path, method, summary, operation_id, created_at, updated_at)
+// @Param order query string false "排序方向" Enums(asc, desc)
+// @Param page query int false "页码"
+// @Param size query int false "每页条数"
+// @Param cursor query string false "上一页返回的 next_cursor"
 // @Success 200 {array} model.APIEndpoint
 // @Failure 400 {object} map[string]string
 // @Router /api/swagger/endpoints [get]
 func (h *SwaggerServiceHandler) ListAPIEndpoints(c *gin.Context) {
-	swaggerIDStr := c.Query("swagger_id")
-	swaggerID, err := strconv.ParseUint(swaggerIDStr, 10, 64)
+	var req EndpointListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		common.Error(c, 400, "invalid query: "+err.Error())
+		return
+	}
+	if req.Order != "" && req.Order != "asc" && req.Order != "desc" {
+		common.Error(c, 400, "order must be asc or desc")
+		return
+	}
+	query := dao.EndpointQuery{
+		SwaggerID:  req.SwaggerID,
+		Method:     req.Method,
+		Tag:        req.Tag,
+		PathPrefix: req.PathPrefix,
+		Keyword:    req.Keyword,
+		Sort:       req.Sort,
+		Desc:       req.Order == "desc",
+		Page:       req.Page,
+		Size:       req.Size,
+		Cursor:     req.Cursor,
+	}
+	if err := query.Validate(); err != nil {
+		common.Error(c, 400, err.Error())
+		return
+	}
+	page, err := h.Service.SearchAPIEndpoints(c.Request.Context(), query)
 	if err != nil {
-		common.Error(c, 400, "invalid swagger_id")
+		common.Error(c, 500, err.Error())
+		return
+	}
+	items := page.Items
+	if items == nil {
+		items = []model.APIEndpoint{}
+	}
+	common.SuccessPage(c, items, page.Total, page.NextCursor)
+}
+
+// BulkEndpoints godoc
+// @Summary 批量操作APIEndpoint
+// @Description 按 ID 或过滤条件选择接口，在同一事务中删除、添加或移除标签、启用或禁用、修改请求头与参数默认值。
+// @Description 未设置 continue_on_error 时，任一接口不存在或失败都会整体回滚，返回 code 400 与逐项结果
+// @Tags Swagger
+// @Accept json
+// @Produce json
+// @Param data body service.BulkEndpointRequest true "批量操作参数"
+// @Success 200 {object} service.BulkReport
+// @Failure 400 {object} service.BulkReport
+// @Router /api/swagger/endpoints/bulk [post]
+func (h *SwaggerServiceHandler) BulkEndpoints(c *gin.Context) {
+	var req service.BulkEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Error(c, 400, "invalid body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		common.Error(c, 400, err.Error())
 		return
 	}
-	endpoints, err := h.Service.ListAPIEndpoints(c.Request.Context(), uint(swaggerID))
+	report, err := h.Service.BulkEndpoints(c.Request.Context(), &req)
 	if err != nil {
 		common.Error(c, 500, err.Error())
 		return
 	}
-	common.Success(c, endpoints)
+	if report.RolledBack {
+		common.ErrorWithData(c, 400, fmt.Sprintf("%d of %d endpoints failed, no changes were applied", report.Failed, report.Matched), report)
+		return
+	}
+	common.Success(c, report)
 }
 
 // GetAPIEndpointByID godoc
@@ -181,6 +273,7 @@ func (h *SwaggerServiceHandler) GetAPIEndpointByID(c *gin.Context) {
 		common.Error(c, 404, err.Error())
 		return
 	}
+	c.Header("ETag", endpointETag(endpoint))
 	common.Success(c, endpoint)
 }
 
@@ -209,6 +302,7 @@ func (h *SwaggerServiceHandler) DeleteAPIEndpoint(c *gin.Context) {
 
 // UpdateAPIEndpoint godoc
 // @Summary 更新APIEndpoint
+// @Description 以请求体替换整个接口；携带 version 时，接口在读取之后被修改过则返回 code 409
 // @Tags Swagger
 // @Accept json
 // @Produce json
@@ -224,21 +318,116 @@ func (h *SwaggerServiceHandler) UpdateAPIEndpoint(c *gin.Context) {
 	}
 	err := h.Service.UpdateAPIEndpoint(c.Request.Context(), &endpoint)
 	if err != nil {
-		common.Error(c, 500, err.Error())
+		h.endpointUpdateError(c, endpoint.ID, err)
+		return
+	}
+	c.Header("ETag", endpointETag(&endpoint))
+	common.Success(c, endpoint)
+}
+
+// PatchAPIEndpoint godoc
+// @Summary 部分更新APIEndpoint
+// @Description 请求体为 JSON Merge Patch（RFC 7386）：未出现的字段保持不变，null 清空字段，数组整体替换。
+// @Description 读取时的版本号通过 If-Match（GET 返回的 ETag）或请求体中的 version 传入，接口在读取之后被修改过时返回 code 409 与当前内容
+// @Tags Swagger
+// @Accept json
+// @Produce json
+// @Param id path int true "APIEndpoint ID"
+// @Param If-Match header string false "读取时的 ETag"
+// @Param data body object true "JSON Merge Patch"
+// @Success 200 {object} model.APIEndpoint
+// @Failure 400 {object} service.EndpointValidationError
+// @Router /api/swagger/endpoint/{id} [patch]
+func (h *SwaggerServiceHandler) PatchAPIEndpoint(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return
+	}
+	switch contentType := c.ContentType(); contentType {
+	case "application/merge-patch+json", "application/json":
+	default:
+		common.Error(c, 415, "unsupported content type: "+contentType)
+		return
+	}
+	var version int64
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		if version, err = parseEndpointETag(ifMatch); err != nil {
+			common.Error(c, 400, "invalid If-Match: "+ifMatch)
+			return
+		}
+	}
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		common.Error(c, 400, "invalid body")
+		return
+	}
+	endpoint, err := h.Service.PatchAPIEndpoint(c.Request.Context(), uint(id), patch, version)
+	if err != nil {
+		h.endpointUpdateError(c, uint(id), err)
 		return
 	}
+	c.Header("ETag", endpointETag(endpoint))
 	common.Success(c, endpoint)
 }
 
+// endpointUpdateError 返回更新接口失败的响应，版本冲突时返回接口的当前内容
+func (h *SwaggerServiceHandler) endpointUpdateError(c *gin.Context, id uint, err error) {
+	var invalid *service.EndpointValidationError
+	switch {
+	case errors.As(err, &invalid):
+		common.ErrorWithData(c, 400, err.Error(), invalid)
+	case errors.Is(err, service.ErrVersionRequired):
+		common.Error(c, 428, err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		common.Error(c, 404, err.Error())
+	case errors.Is(err, dao.ErrVersionConflict):
+		current, getErr := h.Service.GetAPIEndpointByID(c.Request.Context(), id)
+		if getErr != nil {
+			common.Error(c, 409, err.Error())
+			return
+		}
+		c.Header("ETag", endpointETag(current))
+		common.ErrorWithData(c, 409, err.Error(), current)
+	default:
+		common.Error(c, 500, err.Error())
+	}
+}
+
+// endpointETag 返回接口当前版本的 ETag
+func endpointETag(endpoint *model.APIEndpoint) string {
+	return strconv.Quote(strconv.FormatInt(endpoint.Version, 10))
+}
+
+// parseEndpointETag 从 If-Match 中解析版本号
+func parseEndpointETag(etag string) (int64, error) {
+	unquoted, err := strconv.Unquote(strings.TrimSpace(etag))
+	if err != nil {
+		return 0, err
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid etag: %s", etag)
+	}
+	return version, nil
+}
+
 // TestAPIEndpoint godoc
 // @Summary 测试APIEndpoint
+// @Description 返回上游响应的状态码、响应头、响应体、大小、重定向后的最终地址以及 DNS/连接/TLS/首字节各阶段耗时，
+// @Description 上游返回非 2xx 状态码时同样返回 200，由 status_code 区分；
+// @Description violations 列出响应与所属文档中声明的状态码、响应头、响应体 Schema 不一致的地方；
+// @Description 发送前按文档校验参数与请求体的类型、枚举、格式等约束，不符合时返回 400 及各字段的错误，请求不会被发送；
+// @Description 请求体中的 id 指定接口，提交的参数取值、请求头与请求体替换保存的配置，路径与方法须与保存的一致，
+// @Description 提交的取值只能引用保存的配置中已有的 secret，否则返回 400；
+// @Description 接口引用了 secret 时 base_url 必须是文档声明的服务器或 secrets.trusted_base_urls 中的地址
 // @Tags Swagger
 // @Accept json
 // @Produce json
 // @Param data body model.APIEndpoint true "APIEndpoint数据"
 // @Param base_url query string true "服务器基础URL"
-// @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Success 200 {object} service.EndpointTestResult
+// @Failure 400 {object} service.RequestValidationError
 // @Router /api/swagger/endpoint/test [post]
 func (h *SwaggerServiceHandler) TestAPIEndpoint(c *gin.Context) {
 	var endpoint model.APIEndpoint
@@ -246,15 +435,32 @@ func (h *SwaggerServiceHandler) TestAPIEndpoint(c *gin.Context) {
 		common.Error(c, 400, "invalid body")
 		return
 	}
+	if endpoint.ID == 0 {
+		common.Error(c, 400, "id is required")
+		return
+	}
 	baseURL := c.Query("base_url")
 	if baseURL == "" {
 		common.Error(c, 400, "base_url is required")
 		return
 	}
 	resp, err := h.Service.TestAPIEndpoint(c.Request.Context(), &endpoint, baseURL)
+	var invalid *service.RequestValidationError
+	if errors.As(err, &invalid) {
+		common.ErrorWithData(c, 400, err.Error(), invalid)
+		return
+	}
+	if errors.Is(err, service.ErrUntrustedBaseURL) {
+		common.Error(c, 400, err.Error())
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		common.Error(c, 404, "endpoint not found")
+		return
+	}
 	if err != nil {
 		common.Error(c, 500, err.Error())
 		return
 	}
-	common.Success(c, gin.H{"response": resp})
+	common.Success(c, resp)
 }
@@ -0,0 +1,381 @@
+package controller
+
+import (
+	"errors"
+	"strconv"
+
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/service"
+	"mcp-manager/pkg/common"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SwaggerDocumentUpdateRequest 用于更新Swagger文档元信息的参数
+// swagger:model SwaggerDocumentUpdateRequest
+type SwaggerDocumentUpdateRequest struct {
+	Title     string `json:"title" binding:"required"`
+	Version   string `json:"version"`
+	CreatedBy string `json:"created_by"`
+}
+
+// ListDocuments godoc
+// @Summary 查询所有已导入的Swagger文档
+// @Tags SwaggerDocument
+// @Produce json
+// @Success 200 {array} model.SwaggerDocument
+// @Failure 500 {object} map[string]string
+// @Router /api/swagger/documents [get]
+func (h *SwaggerServiceHandler) ListDocuments(c *gin.Context) {
+	docs, err := h.Service.ListDocuments(c.Request.Context())
+	if err != nil {
+		common.Error(c, 500, err.Error())
+		return
+	}
+	common.Success(c, docs)
+}
+
+// CreateDocument godoc
+// @Summary 导入Swagger文档
+// @Description 解析Swagger内容并保存文档及其所有接口，返回新建的文档
+// @Tags SwaggerDocument
+// @Accept json
+// @Produce json
+// @Param data body SwaggerImportRequest true "Swagger内容"
+// @Success 200 {object} model.SwaggerDocument
+// @Failure 400 {object} map[string]string
+// @Router /api/swagger/documents [post]
+func (h *SwaggerServiceHandler) CreateDocument(c *gin.Context) {
+	var req SwaggerImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Error(c, 400, "content is required")
+		return
+	}
+	doc, _, err := h.Service.ImportDocument(c.Request.Context(), []byte(req.Content), req.CreatedBy)
+	if err != nil {
+		importError(c, err)
+		return
+	}
+	common.Success(c, doc)
+}
+
+// SwaggerURLImportRequest 用于通过URL导入Swagger文档的参数
+// swagger:model SwaggerURLImportRequest
+type SwaggerURLImportRequest struct {
+	// 文档地址，如 https://example.com/v3/api-docs
+	URL string `json:"url" binding:"required"`
+	// 拉取文档时携带的 Authorization 请求头，如 Bearer xxx，以 secret 加密保存，不能引用已有的 secret
+	AuthHeader string `json:"auth_header"`
+	// 导入人
+	CreatedBy string `json:"created_by"`
+}
+
+// ImportDocumentByURL godoc
+// @Summary 通过URL导入Swagger文档
+// @Description 拉取URL上的Swagger内容并保存文档及其所有接口，文档会按配置的间隔定时重新同步
+// @Tags SwaggerDocument
+// @Accept json
+// @Produce json
+// @Param data body SwaggerURLImportRequest true "文档地址"
+// @Success 200 {object} model.SwaggerDocument
+// @Failure 400 {object} map[string]string
+// @Router /api/swagger/documents/import-url [post]
+func (h *SwaggerServiceHandler) ImportDocumentByURL(c *gin.Context) {
+	var req SwaggerURLImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Error(c, 400, "url is required")
+		return
+	}
+	doc, _, err := h.Service.ImportFromURL(c.Request.Context(), req.URL, req.AuthHeader, req.CreatedBy)
+	if err != nil {
+		importError(c, err)
+		return
+	}
+	common.Success(c, doc)
+}
+
+// SwaggerReimportRequest 用于重新导入Swagger文档的参数
+// swagger:model SwaggerReimportRequest
+type SwaggerReimportRequest struct {
+	// 新的Swagger内容字符串
+	Content string `json:"content" binding:"required"`
+	// 为 true 时手动修改过的接口字段也以文档内容为准
+	Overwrite bool `json:"overwrite"`
+	// 为 true 时即使存在影响已发布工具的不兼容变更也导入
+	Force bool `json:"force"`
+}
+
+// ReimportDocument godoc
+// @Summary 重新导入Swagger文档
+// @Description 按 operationId 或 method+path 匹配已有接口，在同一事务中新增、更新、删除接口，默认保留手动修改过的字段，返回变更报告
+// @Tags SwaggerDocument
+// @Accept json
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Param data body SwaggerReimportRequest true "Swagger内容"
+// @Success 200 {object} service.ImportResult
+// @Failure 400 {object} map[string]string
+// @Router /api/swagger/documents/{id}/reimport [post]
+func (h *SwaggerServiceHandler) ReimportDocument(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return
+	}
+	var req SwaggerReimportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Error(c, 400, "content is required")
+		return
+	}
+	result, err := h.Service.ReimportDocument(c.Request.Context(), uint(id), []byte(req.Content), req.Overwrite, req.Force)
+	if err != nil {
+		importError(c, err)
+		return
+	}
+	common.Success(c, result)
+}
+
+// SyncDocument godoc
+// @Summary 立即同步通过URL导入的Swagger文档
+// @Description 重新拉取文档的来源地址，内容变化时重新解析并更新接口
+// @Tags SwaggerDocument
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Success 200 {object} map[string]bool
+// @Failure 400 {object} map[string]string
+// @Router /api/swagger/documents/{id}/sync [post]
+func (h *SwaggerServiceHandler) SyncDocument(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return
+	}
+	changed, err := h.Service.SyncDocument(c.Request.Context(), uint(id))
+	if err != nil {
+		importError(c, err)
+		return
+	}
+	common.Success(c, gin.H{"changed": changed})
+}
+
+// SwaggerRollbackRequest 回滚Swagger文档的请求体
+type SwaggerRollbackRequest struct {
+	// 要恢复的修订号
+	Revision int `json:"revision" binding:"required"`
+	// 为 true 时手动修改过的接口字段也恢复为该修订的内容
+	Overwrite bool `json:"overwrite"`
+	// 为 true 时即使存在影响已发布工具的不兼容变更也回滚
+	Force bool `json:"force"`
+}
+
+// ListRevisions godoc
+// @Summary 查询Swagger文档的修订历史
+// @Description 按修订号倒序返回文档每次导入的修订，不包含原始内容
+// @Tags SwaggerDocument
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Success 200 {array} model.SwaggerDocumentRevision
+// @Failure 400 {object} map[string]string
+// @Router /api/swagger/documents/{id}/revisions [get]
+func (h *SwaggerServiceHandler) ListRevisions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return
+	}
+	revisions, err := h.Service.ListRevisions(c.Request.Context(), uint(id))
+	if err != nil {
+		common.Error(c, 500, err.Error())
+		return
+	}
+	common.Success(c, revisions)
+}
+
+// GetRevision godoc
+// @Summary 查询Swagger文档的指定修订
+// @Description 返回修订的原始内容与校验和
+// @Tags SwaggerDocument
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Param revision path int true "修订号"
+// @Success 200 {object} model.SwaggerDocumentRevision
+// @Failure 400 {object} map[string]string
+// @Router /api/swagger/documents/{id}/revisions/{revision} [get]
+func (h *SwaggerServiceHandler) GetRevision(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		common.Error(c, 400, "invalid revision")
+		return
+	}
+	rev, err := h.Service.GetRevision(c.Request.Context(), uint(id), revision)
+	if err != nil {
+		common.Error(c, 404, err.Error())
+		return
+	}
+	common.Success(c, rev)
+}
+
+// DiffRevisions godoc
+// @Summary 比较Swagger文档的两个修订
+// @Description 以 from 为基准返回新增、删除的接口，参数、请求体、响应有变化的接口，以及新增、删除、变化的 Schema
+// @Tags SwaggerDocument
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Param from query int true "基准修订号"
+// @Param to query int true "目标修订号"
+// @Success 200 {object} diff.Report
+// @Failure 400 {object} map[string]string
+// @Router /api/swagger/documents/{id}/diff [get]
+func (h *SwaggerServiceHandler) DiffRevisions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		common.Error(c, 400, "invalid from")
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		common.Error(c, 400, "invalid to")
+		return
+	}
+	report, err := h.Service.DiffRevisions(c.Request.Context(), uint(id), from, to)
+	if err != nil {
+		common.Error(c, 400, err.Error())
+		return
+	}
+	common.Success(c, report)
+}
+
+// RollbackDocument godoc
+// @Summary 将Swagger文档回滚到指定修订
+// @Description 以该修订的内容重新导入文档，恢复其接口集合，回滚记录为新的修订，返回变更报告
+// @Description 与重新导入相同，存在影响已发布工具的不兼容变更时可能返回 409
+// @Tags SwaggerDocument
+// @Accept json
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Param data body SwaggerRollbackRequest true "修订号"
+// @Success 200 {object} service.ImportResult
+// @Failure 400 {object} map[string]string
+// @Router /api/swagger/documents/{id}/rollback [post]
+func (h *SwaggerServiceHandler) RollbackDocument(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return
+	}
+	var req SwaggerRollbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Error(c, 400, "revision is required")
+		return
+	}
+	result, err := h.Service.RollbackDocument(c.Request.Context(), uint(id), req.Revision, req.Overwrite, req.Force)
+	if err != nil {
+		importError(c, err)
+		return
+	}
+	common.Success(c, result)
+}
+
+// importError 返回导入失败的响应，因不兼容变更被拒绝时返回 409 及变更详情
+func importError(c *gin.Context, err error) {
+	var breaking *service.BreakingChangeError
+	if errors.As(err, &breaking) {
+		common.ErrorWithData(c, 409, err.Error(), breaking)
+		return
+	}
+	common.Error(c, 400, err.Error())
+}
+
+// GetDocumentByID godoc
+// @Summary 根据ID查询Swagger文档
+// @Tags SwaggerDocument
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Success 200 {object} model.SwaggerDocument
+// @Failure 400 {object} map[string]string
+// @Router /api/swagger/documents/{id} [get]
+func (h *SwaggerServiceHandler) GetDocumentByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return
+	}
+	doc, err := h.Service.GetDocumentByID(c.Request.Context(), uint(id))
+	if err != nil {
+		common.Error(c, 404, err.Error())
+		return
+	}
+	common.Success(c, doc)
+}
+
+// UpdateDocument godoc
+// @Summary 更新Swagger文档元信息
+// @Tags SwaggerDocument
+// @Accept json
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Param data body SwaggerDocumentUpdateRequest true "文档元信息"
+// @Success 200 {object} model.SwaggerDocument
+// @Failure 400 {object} map[string]string
+// @Router /api/swagger/documents/{id} [put]
+func (h *SwaggerServiceHandler) UpdateDocument(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return
+	}
+	var req SwaggerDocumentUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		common.Error(c, 400, "invalid body")
+		return
+	}
+	doc := &model.SwaggerDocument{
+		ID:        uint(id),
+		Title:     req.Title,
+		Version:   req.Version,
+		CreatedBy: req.CreatedBy,
+	}
+	if err := h.Service.UpdateDocument(c.Request.Context(), doc); err != nil {
+		common.Error(c, 500, err.Error())
+		return
+	}
+	common.Success(c, doc)
+}
+
+// DeleteDocument godoc
+// @Summary 删除Swagger文档及其下所有接口
+// @Description 文档、接口及引用这些接口的MCP工具绑定移入回收站，可在保留时长内恢复
+// @Tags SwaggerDocument
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/swagger/documents/{id} [delete]
+func (h *SwaggerServiceHandler) DeleteDocument(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return
+	}
+	if err := h.Service.DeleteDocument(c.Request.Context(), uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			common.Error(c, 404, "document not found")
+			return
+		}
+		common.Error(c, 500, err.Error())
+		return
+	}
+	common.Success(c, gin.H{"message": "deleted"})
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"errors"
+	"strconv"
+
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/service"
+	"mcp-manager/pkg/common"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TrashHandler 回收站的 HTTP 处理器
+type TrashHandler struct {
+	Service service.TrashService
+}
+
+// NewTrashHandler 构造函数
+func NewTrashHandler(s service.TrashService) *TrashHandler {
+	return &TrashHandler{Service: s}
+}
+
+// ListTrash godoc
+// @Summary 查询回收站
+// @Description 返回回收站中的文档与单独删除的接口，随文档删除的接口只计入文档的 endpoint_count
+// @Tags Trash
+// @Produce json
+// @Success 200 {object} service.TrashListing
+// @Failure 500 {object} map[string]string
+// @Router /api/swagger/trash [get]
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	listing, err := h.Service.List(c.Request.Context())
+	if err != nil {
+		common.Error(c, 500, err.Error())
+		return
+	}
+	common.Success(c, listing)
+}
+
+// RestoreDocument godoc
+// @Summary 恢复回收站中的文档
+// @Description 恢复文档及随其删除的接口与工具绑定
+// @Tags Trash
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/swagger/trash/documents/{id}/restore [post]
+func (h *TrashHandler) RestoreDocument(c *gin.Context) {
+	id, ok := trashID(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.RestoreDocument(c.Request.Context(), id); err != nil {
+		trashError(c, err)
+		return
+	}
+	common.Success(c, gin.H{"message": "restored"})
+}
+
+// RestoreEndpoint godoc
+// @Summary 恢复回收站中的接口
+// @Description 恢复接口及随其删除的工具绑定，所属文档仍在回收站中时需先恢复文档
+// @Tags Trash
+// @Produce json
+// @Param id path int true "APIEndpoint ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/swagger/trash/endpoints/{id}/restore [post]
+func (h *TrashHandler) RestoreEndpoint(c *gin.Context) {
+	id, ok := trashID(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.RestoreEndpoint(c.Request.Context(), id); err != nil {
+		trashError(c, err)
+		return
+	}
+	common.Success(c, gin.H{"message": "restored"})
+}
+
+// PurgeDocument godoc
+// @Summary 彻底删除回收站中的文档
+// @Description 彻底删除文档及其接口、工具绑定与修订，不可恢复
+// @Tags Trash
+// @Produce json
+// @Param id path int true "SwaggerDocument ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/swagger/trash/documents/{id} [delete]
+func (h *TrashHandler) PurgeDocument(c *gin.Context) {
+	id, ok := trashID(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.PurgeDocument(c.Request.Context(), id); err != nil {
+		trashError(c, err)
+		return
+	}
+	common.Success(c, gin.H{"message": "purged"})
+}
+
+// PurgeEndpoint godoc
+// @Summary 彻底删除回收站中的接口
+// @Description 彻底删除接口及其工具绑定，不可恢复
+// @Tags Trash
+// @Produce json
+// @Param id path int true "APIEndpoint ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/swagger/trash/endpoints/{id} [delete]
+func (h *TrashHandler) PurgeEndpoint(c *gin.Context) {
+	id, ok := trashID(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.PurgeEndpoint(c.Request.Context(), id); err != nil {
+		trashError(c, err)
+		return
+	}
+	common.Success(c, gin.H{"message": "purged"})
+}
+
+// trashID 解析路径中的 id，不合法时返回 400
+func trashID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		common.Error(c, 400, "invalid id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// trashError 把回收站操作的错误转换为响应，记录不在回收站中时返回 404
+func trashError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		common.Error(c, 404, "not found in the trash")
+	case errors.Is(err, dao.ErrDocumentInTrash):
+		common.Error(c, 409, err.Error())
+	default:
+		common.Error(c, 500, err.Error())
+	}
+}
@@ -2,7 +2,12 @@ package dao
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"mcp-manager/internal/model"
+	"strconv"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -13,9 +18,144 @@ import (
 type APIEndpointDAO interface {
 	Create(ctx context.Context, endpoint *model.APIEndpoint) error
 	Delete(ctx context.Context, id uint) error
+	// Update 保存整个接口并把版本号加 1；endpoint.Version 非 0 时只在数据库中的版本号与之相同时更新，否则返回 ErrVersionConflict
 	Update(ctx context.Context, endpoint *model.APIEndpoint) error
 	GetByID(ctx context.Context, id uint) (*model.APIEndpoint, error)
 	List(ctx context.Context, swaggerID uint) ([]model.APIEndpoint, error)
+	DeleteBySwaggerID(ctx context.Context, swaggerID uint) error
+	ListByIDs(ctx context.Context, ids []uint) ([]model.APIEndpoint, error)
+	// Search 按条件分页查询接口，返回当前页与满足条件的总数
+	Search(ctx context.Context, query EndpointQuery) (*EndpointPage, error)
+	// ListIDs 返回满足 query 过滤条件的所有接口 ID，忽略排序与分页
+	ListIDs(ctx context.Context, query EndpointQuery) ([]uint, error)
+	// Bulk 在同一事务中对 ids 中的每个接口调用 apply，并按返回的操作更新或删除接口，返回逐项结果
+	// 有接口不存在或 apply 返回错误时，continueOnError 为 false 则整体回滚，为 true 则只跳过这些接口
+	Bulk(ctx context.Context, ids []uint, continueOnError bool, apply BulkFunc) ([]BulkItemResult, error)
+}
+
+// BulkOp 为批量操作中对单个接口执行的操作
+type BulkOp int
+
+const (
+	BulkSkip   BulkOp = iota // 接口无变化
+	BulkUpdate               // 保存修改后的接口
+	BulkDelete               // 将接口移入回收站
+)
+
+// BulkFunc 修改 endpoint 并返回需要执行的操作，返回错误时该接口记为失败
+type BulkFunc func(endpoint *model.APIEndpoint) (BulkOp, error)
+
+// 批量操作中单个接口的结果
+const (
+	BulkStatusUpdated    = "updated"
+	BulkStatusDeleted    = "deleted"
+	BulkStatusUnchanged  = "unchanged"
+	BulkStatusNotFound   = "not_found"
+	BulkStatusFailed     = "failed"
+	BulkStatusRolledBack = "rolled_back" // 本可成功，但因其他接口失败而回滚
+)
+
+// BulkItemResult 为批量操作中单个接口的结果
+type BulkItemResult struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// errBulkRollback 用于在有接口失败时回滚批量操作的事务
+var errBulkRollback = errors.New("bulk operation rolled back")
+
+const (
+	// DefaultEndpointPageSize 未指定 size 时的每页条数
+	DefaultEndpointPageSize = 20
+	// MaxEndpointPageSize 每页条数上限
+	MaxEndpointPageSize = 200
+)
+
+// endpointSortColumns 为允许排序的字段与对应的列
+var endpointSortColumns = map[string]string{
+	"id":           "id",
+	"path":         "path",
+	"method":       "method",
+	"summary":      "summary",
+	"operation_id": "operation_id",
+	"created_at":   "created_at",
+	"updated_at":   "updated_at",
+}
+
+// ErrVersionConflict 表示接口在读取之后已被修改
+var ErrVersionConflict = errors.New("endpoint has been modified since it was read")
+
+// ErrInvalidCursor 表示游标无法解析
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EndpointQuery 为接口列表的查询条件，零值字段不参与过滤
+type EndpointQuery struct {
+	SwaggerID  uint   // 所属文档
+	Method     string // HTTP 方法，不区分大小写
+	Tag        string // 接口标签之一
+	PathPrefix string // 路径前缀
+	Keyword    string // 在摘要、描述、operationId 中不区分大小写地搜索
+	Sort       string // 排序字段，见 endpointSortColumns，默认 id
+	Desc       bool   // 是否倒序
+	Page       int    // 页码，从 1 开始，与 Cursor 二选一
+	Size       int    // 每页条数，默认 DefaultEndpointPageSize
+	Cursor     string // 上一页返回的 NextCursor，只能在按 id 排序时使用
+}
+
+// EndpointPage 为一页查询结果
+type EndpointPage struct {
+	Items      []model.APIEndpoint
+	Total      int64  // 满足过滤条件的总数，不受分页影响
+	NextCursor string // 游标分页时下一页的游标，没有下一页时为空
+}
+
+// Validate 检查排序字段与分页参数
+func (q EndpointQuery) Validate() error {
+	if q.Sort != "" {
+		if _, ok := endpointSortColumns[q.Sort]; !ok {
+			return fmt.Errorf("unsupported sort field: %s", q.Sort)
+		}
+	}
+	if q.Page < 0 || q.Size < 0 {
+		return fmt.Errorf("page and size must not be negative")
+	}
+	if q.Cursor != "" {
+		if q.Sort != "" && q.Sort != "id" {
+			return fmt.Errorf("cursor pagination only supports sorting by id")
+		}
+		if q.Page > 0 {
+			return fmt.Errorf("page and cursor are mutually exclusive")
+		}
+		if _, err := decodeEndpointCursor(q.Cursor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeEndpointCursor 把最后一条记录的 id 编码为不透明的游标
+func encodeEndpointCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeEndpointCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	return uint(id), nil
+}
+
+// likeEscaper 转义 LIKE 的通配符，以 ! 作为转义符，MySQL、PostgreSQL、SQLite 的写法相同
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
 }
 
 type apiEndpointDAO struct {
@@ -34,15 +174,51 @@ func NewAPIEndpointDAO(db *gorm.DB) APIEndpointDAO {
 }
 
 func (d *apiEndpointDAO) Create(ctx context.Context, endpoint *model.APIEndpoint) error {
+	endpoint.Version = 1
 	return d.db.WithContext(ctx).Create(endpoint).Error
 }
 
+// Delete 将接口及引用它的工具绑定移入回收站
 func (d *apiEndpointDAO) Delete(ctx context.Context, id uint) error {
-	return d.db.WithContext(ctx).Delete(&model.APIEndpoint{}, id).Error
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return trashEndpoints(tx, deletedNow(), func(db *gorm.DB) *gorm.DB { return db.Where("id = ?", id) })
+	})
 }
 
 func (d *apiEndpointDAO) Update(ctx context.Context, endpoint *model.APIEndpoint) error {
-	return d.db.WithContext(ctx).Save(endpoint).Error
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if endpoint.Version == 0 {
+			// 未携带版本号时以数据库中的当前版本为准，不做并发检查
+			var current model.APIEndpoint
+			if err := tx.Select("version").First(&current, endpoint.ID).Error; err != nil {
+				return err
+			}
+			endpoint.Version = current.Version
+		}
+		return updateEndpoint(tx, endpoint)
+	})
+}
+
+// updateEndpoint 在版本号仍为 endpoint.Version 时保存整个接口并把版本号加 1
+// 接口不存在时返回 gorm.ErrRecordNotFound，版本号已变化时返回 ErrVersionConflict
+func updateEndpoint(tx *gorm.DB, endpoint *model.APIEndpoint) error {
+	expected := endpoint.Version
+	endpoint.Version = expected + 1
+	result := tx.Model(endpoint).Where("version = ?", expected).Select("*").Omit("created_at", "deleted_at").Updates(endpoint)
+	if result.Error == nil && result.RowsAffected == 0 {
+		var count int64
+		if result.Error = tx.Model(&model.APIEndpoint{}).Where("id = ?", endpoint.ID).Count(&count).Error; result.Error == nil {
+			result.Error = ErrVersionConflict
+			if count == 0 {
+				result.Error = gorm.ErrRecordNotFound
+			}
+		}
+	}
+	if result.Error != nil {
+		endpoint.Version = expected
+		return result.Error
+	}
+	return nil
 }
 
 func (d *apiEndpointDAO) GetByID(ctx context.Context, id uint) (*model.APIEndpoint, error) {
@@ -59,3 +235,178 @@ func (d *apiEndpointDAO) List(ctx context.Context, swaggerID uint) ([]model.APIE
 	err := d.db.WithContext(ctx).Where("swagger_id = ?", swaggerID).Find(&endpoints).Error
 	return endpoints, err
 }
+
+// DeleteBySwaggerID 将文档下的所有接口及引用它们的工具绑定移入回收站
+func (d *apiEndpointDAO) DeleteBySwaggerID(ctx context.Context, swaggerID uint) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return trashEndpoints(tx, deletedNow(), func(db *gorm.DB) *gorm.DB { return db.Where("swagger_id = ?", swaggerID) })
+	})
+}
+
+func (d *apiEndpointDAO) ListByIDs(ctx context.Context, ids []uint) ([]model.APIEndpoint, error) {
+	var endpoints []model.APIEndpoint
+	if len(ids) == 0 {
+		return endpoints, nil
+	}
+	err := d.db.WithContext(ctx).Where("id IN ?", ids).Find(&endpoints).Error
+	return endpoints, err
+}
+
+func (d *apiEndpointDAO) Search(ctx context.Context, query EndpointQuery) (*EndpointPage, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	db := filterEndpoints(d.db.WithContext(ctx).Model(&model.APIEndpoint{}), query)
+
+	page := &EndpointPage{}
+	if err := db.Count(&page.Total).Error; err != nil {
+		return nil, err
+	}
+
+	size := query.Size
+	if size == 0 {
+		size = DefaultEndpointPageSize
+	}
+	if size > MaxEndpointPageSize {
+		size = MaxEndpointPageSize
+	}
+	order := "ASC"
+	if query.Desc {
+		order = "DESC"
+	}
+	column := "id"
+	if query.Sort != "" {
+		column = endpointSortColumns[query.Sort]
+	}
+	db = db.Order(column + " " + order)
+	if column != "id" {
+		// 排序字段相同时按 id 排序，保证分页稳定
+		db = db.Order("id " + order)
+	}
+
+	if query.Cursor != "" || query.Page == 0 {
+		// 游标分页多查一条以判断是否还有下一页
+		if query.Cursor != "" {
+			afterID, _ := decodeEndpointCursor(query.Cursor)
+			if query.Desc {
+				db = db.Where("id < ?", afterID)
+			} else {
+				db = db.Where("id > ?", afterID)
+			}
+		}
+		if err := db.Limit(size + 1).Find(&page.Items).Error; err != nil {
+			return nil, err
+		}
+		if len(page.Items) > size {
+			page.Items = page.Items[:size]
+			if column == "id" {
+				page.NextCursor = encodeEndpointCursor(page.Items[size-1].ID)
+			}
+		}
+		return page, nil
+	}
+
+	if err := db.Offset((query.Page - 1) * size).Limit(size).Find(&page.Items).Error; err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// filterEndpoints 在 db 上追加 query 中的过滤条件
+func filterEndpoints(db *gorm.DB, query EndpointQuery) *gorm.DB {
+	if query.SwaggerID != 0 {
+		db = db.Where("swagger_id = ?", query.SwaggerID)
+	}
+	if query.Method != "" {
+		db = db.Where("method = ?", strings.ToUpper(query.Method))
+	}
+	if query.Tag != "" {
+		// tags 以逗号分隔存储
+		tag := escapeLike(query.Tag)
+		db = db.Where("(tags = ? OR tags LIKE ? ESCAPE '!' OR tags LIKE ? ESCAPE '!' OR tags LIKE ? ESCAPE '!')",
+			query.Tag, tag+",%", "%,"+tag, "%,"+tag+",%")
+	}
+	if query.PathPrefix != "" {
+		db = db.Where("path LIKE ? ESCAPE '!'", escapeLike(query.PathPrefix)+"%")
+	}
+	if query.Keyword != "" {
+		keyword := "%" + escapeLike(strings.ToLower(query.Keyword)) + "%"
+		db = db.Where("(LOWER(summary) LIKE ? ESCAPE '!' OR LOWER(description) LIKE ? ESCAPE '!' OR LOWER(operation_id) LIKE ? ESCAPE '!')",
+			keyword, keyword, keyword)
+	}
+	return db
+}
+
+func (d *apiEndpointDAO) ListIDs(ctx context.Context, query EndpointQuery) ([]uint, error) {
+	var ids []uint
+	err := filterEndpoints(d.db.WithContext(ctx).Model(&model.APIEndpoint{}), query).Order("id").Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (d *apiEndpointDAO) Bulk(ctx context.Context, ids []uint, continueOnError bool, apply BulkFunc) ([]BulkItemResult, error) {
+	results := make([]BulkItemResult, len(ids))
+	deletedAt := deletedNow()
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var endpoints []model.APIEndpoint
+		if len(ids) > 0 {
+			if err := tx.Where("id IN ?", ids).Find(&endpoints).Error; err != nil {
+				return err
+			}
+		}
+		byID := make(map[uint]*model.APIEndpoint, len(endpoints))
+		for i := range endpoints {
+			byID[endpoints[i].ID] = &endpoints[i]
+		}
+
+		failed := false
+		for i, id := range ids {
+			result := &results[i]
+			result.ID = id
+			endpoint, ok := byID[id]
+			if !ok {
+				result.Status = BulkStatusNotFound
+				result.Error = "endpoint not found"
+				failed = true
+				continue
+			}
+			op, err := apply(endpoint)
+			if err != nil {
+				result.Status = BulkStatusFailed
+				result.Error = err.Error()
+				failed = true
+				continue
+			}
+			// 数据库错误使整个事务失败
+			switch op {
+			case BulkDelete:
+				if err := trashEndpoints(tx, deletedAt, func(db *gorm.DB) *gorm.DB { return db.Where("id = ?", id) }); err != nil {
+					return err
+				}
+				result.Status = BulkStatusDeleted
+			case BulkUpdate:
+				if err := updateEndpoint(tx, endpoint); err != nil {
+					return err
+				}
+				result.Status = BulkStatusUpdated
+			default:
+				result.Status = BulkStatusUnchanged
+			}
+		}
+		if failed && !continueOnError {
+			return errBulkRollback
+		}
+		return nil
+	})
+	if errors.Is(err, errBulkRollback) {
+		for i := range results {
+			if results[i].Status == BulkStatusUpdated || results[i].Status == BulkStatusDeleted {
+				results[i].Status = BulkStatusRolledBack
+			}
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -6,8 +6,10 @@ import (
 	"mcp-manager/internal/model"
 	_ "mcp-manager/internal/testutil"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 func TestAPIEndpointDAO_Create_GetByID_Update_Delete_List(t *testing.T) {
@@ -45,3 +47,174 @@ func TestAPIEndpointDAO_Create_GetByID_Update_Delete_List(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, got)
 }
+
+func TestAPIEndpointDAO_Search(t *testing.T) {
+	d := dao.NewAPIEndpointDAO(nil)
+	ctx := context.Background()
+
+	const swaggerID = 1701
+	fixtures := []*model.APIEndpoint{
+		{SwaggerID: swaggerID, Path: "/pets", Method: "GET", OperationID: "listPets", Summary: "List pets", Tags: "pets"},
+		{SwaggerID: swaggerID, Path: "/pets", Method: "POST", OperationID: "createPet", Summary: "Create a pet", Tags: "pets,admin"},
+		{SwaggerID: swaggerID, Path: "/pets/{id}", Method: "DELETE", OperationID: "deletePet", Description: "Removes a PET for good", Tags: "admin"},
+		{SwaggerID: swaggerID, Path: "/stores", Method: "GET", OperationID: "listStores", Summary: "100% of stores", Tags: "petstore"},
+	}
+	for _, endpoint := range fixtures {
+		assert.NoError(t, d.Create(ctx, endpoint))
+	}
+	t.Cleanup(func() { _ = d.DeleteBySwaggerID(ctx, swaggerID) })
+
+	search := func(query dao.EndpointQuery) []string {
+		query.SwaggerID = swaggerID
+		page, err := d.Search(ctx, query)
+		assert.NoError(t, err)
+		var ids []string
+		for _, endpoint := range page.Items {
+			ids = append(ids, endpoint.OperationID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"listPets", "listStores"}, search(dao.EndpointQuery{Method: "get"}))
+	// 标签按逗号分隔的整项匹配，pets 不匹配 petstore
+	assert.Equal(t, []string{"listPets", "createPet"}, search(dao.EndpointQuery{Tag: "pets"}))
+	assert.Equal(t, []string{"createPet", "deletePet"}, search(dao.EndpointQuery{Tag: "admin"}))
+	assert.Equal(t, []string{"deletePet"}, search(dao.EndpointQuery{PathPrefix: "/pets/"}))
+	// 关键字不区分大小写，通配符按字面匹配
+	assert.Equal(t, []string{"deletePet"}, search(dao.EndpointQuery{Keyword: "pet for"}))
+	assert.Equal(t, []string{"listStores"}, search(dao.EndpointQuery{Keyword: "100%"}))
+	assert.Equal(t, []string{"createPet", "deletePet", "listPets"}, search(dao.EndpointQuery{Keyword: "pet", Sort: "operation_id"}))
+	assert.Equal(t, []string{"listStores", "deletePet", "createPet", "listPets"}, search(dao.EndpointQuery{Desc: true}))
+
+	// 页码分页
+	page, err := d.Search(ctx, dao.EndpointQuery{SwaggerID: swaggerID, Sort: "path", Page: 2, Size: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), page.Total)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, "listStores", page.Items[0].OperationID)
+	assert.Empty(t, page.NextCursor)
+
+	// 游标分页
+	page, err = d.Search(ctx, dao.EndpointQuery{SwaggerID: swaggerID, Size: 3})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 3)
+	assert.NotEmpty(t, page.NextCursor)
+	page, err = d.Search(ctx, dao.EndpointQuery{SwaggerID: swaggerID, Size: 3, Cursor: page.NextCursor})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), page.Total)
+	assert.Len(t, page.Items, 1)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestEndpointQuery_Validate(t *testing.T) {
+	assert.NoError(t, dao.EndpointQuery{Sort: "path", Page: 1}.Validate())
+	assert.Error(t, dao.EndpointQuery{Sort: "body"}.Validate())
+	assert.Error(t, dao.EndpointQuery{Sort: "path", Cursor: "MQ"}.Validate())
+	assert.Error(t, dao.EndpointQuery{Page: 2, Cursor: "MQ"}.Validate())
+	assert.ErrorIs(t, dao.EndpointQuery{Cursor: "!!"}.Validate(), dao.ErrInvalidCursor)
+}
+
+func TestAPIEndpointDAO_Bulk(t *testing.T) {
+	d := dao.NewAPIEndpointDAO(nil)
+	ctx := context.Background()
+
+	const swaggerID = 1801
+	a := &model.APIEndpoint{SwaggerID: swaggerID, Path: "/a", Method: "GET"}
+	b := &model.APIEndpoint{SwaggerID: swaggerID, Path: "/b", Method: "GET"}
+	assert.NoError(t, d.Create(ctx, a))
+	assert.NoError(t, d.Create(ctx, b))
+	t.Cleanup(func() { _ = d.DeleteBySwaggerID(ctx, swaggerID) })
+
+	disable := func(endpoint *model.APIEndpoint) (dao.BulkOp, error) {
+		endpoint.Disabled = true
+		return dao.BulkUpdate, nil
+	}
+	missing := a.ID + b.ID + 1000
+
+	// 有接口不存在时整体回滚
+	items, err := d.Bulk(ctx, []uint{a.ID, missing}, false, disable)
+	assert.NoError(t, err)
+	assert.Equal(t, []dao.BulkItemResult{
+		{ID: a.ID, Status: dao.BulkStatusRolledBack},
+		{ID: missing, Status: dao.BulkStatusNotFound, Error: "endpoint not found"},
+	}, items)
+	got, err := d.GetByID(ctx, a.ID)
+	assert.NoError(t, err)
+	assert.False(t, got.Disabled)
+
+	// continueOnError 时只跳过失败的接口
+	items, err = d.Bulk(ctx, []uint{a.ID, missing}, true, disable)
+	assert.NoError(t, err)
+	assert.Equal(t, dao.BulkStatusUpdated, items[0].Status)
+	got, err = d.GetByID(ctx, a.ID)
+	assert.NoError(t, err)
+	assert.True(t, got.Disabled)
+
+	ids, err := d.ListIDs(ctx, dao.EndpointQuery{SwaggerID: swaggerID, PathPrefix: "/b"})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{b.ID}, ids)
+	items, err = d.Bulk(ctx, ids, false, func(*model.APIEndpoint) (dao.BulkOp, error) { return dao.BulkDelete, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, dao.BulkStatusDeleted, items[0].Status)
+	_, err = d.GetByID(ctx, b.ID)
+	assert.Error(t, err)
+}
+
+func TestAPIEndpointDAO_Update_VersionConflict(t *testing.T) {
+	d := dao.NewAPIEndpointDAO(nil)
+	ctx := context.Background()
+
+	endpoint := &model.APIEndpoint{SwaggerID: 1901, Path: "/v", Method: "GET"}
+	assert.NoError(t, d.Create(ctx, endpoint))
+	t.Cleanup(func() { _ = d.DeleteBySwaggerID(ctx, 1901) })
+	assert.Equal(t, int64(1), endpoint.Version)
+
+	// 两个编辑者读取了同一版本
+	first, err := d.GetByID(ctx, endpoint.ID)
+	assert.NoError(t, err)
+	second, err := d.GetByID(ctx, endpoint.ID)
+	assert.NoError(t, err)
+
+	first.Summary = "first"
+	assert.NoError(t, d.Update(ctx, first))
+	assert.Equal(t, int64(2), first.Version)
+
+	second.Summary = "second"
+	assert.ErrorIs(t, d.Update(ctx, second), dao.ErrVersionConflict)
+	assert.Equal(t, int64(1), second.Version)
+
+	got, err := d.GetByID(ctx, endpoint.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", got.Summary)
+	assert.Equal(t, int64(2), got.Version)
+
+	// 不携带版本号时不做并发检查
+	second.Version = 0
+	assert.NoError(t, d.Update(ctx, second))
+	assert.Equal(t, int64(3), second.Version)
+
+	missing := &model.APIEndpoint{ID: endpoint.ID + 1000, Version: 1}
+	assert.ErrorIs(t, d.Update(ctx, missing), gorm.ErrRecordNotFound)
+}
+
+func TestAPIEndpointDAO_Update_IgnoresTimestamps(t *testing.T) {
+	d := dao.NewAPIEndpointDAO(nil)
+	ctx := context.Background()
+
+	endpoint := &model.APIEndpoint{SwaggerID: 1902, Path: "/t", Method: "GET"}
+	assert.NoError(t, d.Create(ctx, endpoint))
+	t.Cleanup(func() { _ = d.DeleteBySwaggerID(ctx, 1902) })
+	created := endpoint.CreatedAt
+
+	// 请求体中的 deleted_at 与 created_at 不会写入，移入回收站只能通过删除接口
+	endpoint.Summary = "updated"
+	endpoint.CreatedAt = created.Add(-time.Hour)
+	endpoint.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	assert.NoError(t, d.Update(ctx, endpoint))
+
+	got, err := d.GetByID(ctx, endpoint.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "updated", got.Summary)
+	assert.False(t, got.DeletedAt.Valid)
+	assert.WithinDuration(t, created, got.CreatedAt, time.Second)
+}
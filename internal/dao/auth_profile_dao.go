@@ -0,0 +1,116 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"mcp-manager/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// AuthProfileDAO 定义对 auth_profiles 表的基本操作，以及文档与鉴权配置的绑定
+type AuthProfileDAO interface {
+	Create(ctx context.Context, profile *model.AuthProfile) error
+	// Delete 删除鉴权配置，并解除文档与 server 对它的引用
+	Delete(ctx context.Context, id uint) error
+	Update(ctx context.Context, profile *model.AuthProfile) error
+	GetByID(ctx context.Context, id uint) (*model.AuthProfile, error)
+	GetByName(ctx context.Context, name string) (*model.AuthProfile, error)
+	List(ctx context.Context) ([]model.AuthProfile, error)
+	// GetByDocumentID 返回文档绑定的鉴权配置，文档未绑定时返回 nil
+	GetByDocumentID(ctx context.Context, swaggerID uint) (*model.AuthProfile, error)
+	// BindDocument 把鉴权配置绑定到文档，profileID 为 nil 时解除绑定
+	BindDocument(ctx context.Context, swaggerID uint, profileID *uint) error
+}
+
+type authProfileDAO struct {
+	db *gorm.DB
+}
+
+func NewAuthProfileDAO(db *gorm.DB) AuthProfileDAO {
+	if db == nil {
+		var err error
+		db, err = model.GetMcpManagerDB() // 获取主数据库连接
+		if err != nil {
+			panic("failed to get main DB: " + err.Error())
+		}
+	}
+	return &authProfileDAO{db: db}
+}
+
+func (d *authProfileDAO) Create(ctx context.Context, profile *model.AuthProfile) error {
+	return d.db.WithContext(ctx).Create(profile).Error
+}
+
+func (d *authProfileDAO) Delete(ctx context.Context, id uint) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 回收站中的文档同样解除引用，恢复后不会引用已删除的配置
+		if err := tx.Unscoped().Model(&model.SwaggerDocument{}).Where("auth_profile_id = ?", id).Update("auth_profile_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.MCPServer{}).Where("auth_profile_id = ?", id).Update("auth_profile_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.AuthProfile{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (d *authProfileDAO) Update(ctx context.Context, profile *model.AuthProfile) error {
+	return d.db.WithContext(ctx).Save(profile).Error
+}
+
+func (d *authProfileDAO) GetByID(ctx context.Context, id uint) (*model.AuthProfile, error) {
+	var profile model.AuthProfile
+	err := d.db.WithContext(ctx).First(&profile, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (d *authProfileDAO) GetByName(ctx context.Context, name string) (*model.AuthProfile, error) {
+	var profile model.AuthProfile
+	err := d.db.WithContext(ctx).Where("name = ?", name).First(&profile).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (d *authProfileDAO) List(ctx context.Context) ([]model.AuthProfile, error) {
+	var profiles []model.AuthProfile
+	err := d.db.WithContext(ctx).Order("id").Find(&profiles).Error
+	return profiles, err
+}
+
+func (d *authProfileDAO) GetByDocumentID(ctx context.Context, swaggerID uint) (*model.AuthProfile, error) {
+	var profile model.AuthProfile
+	err := d.db.WithContext(ctx).
+		Where("id = (?)", d.db.Model(&model.SwaggerDocument{}).Select("auth_profile_id").Where("id = ?", swaggerID)).
+		First(&profile).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (d *authProfileDAO) BindDocument(ctx context.Context, swaggerID uint, profileID *uint) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// MySQL 在值未变化时 RowsAffected 为 0，因此先确认文档存在
+		var doc model.SwaggerDocument
+		if err := tx.Select("id").First(&doc, swaggerID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.SwaggerDocument{}).Where("id = ?", swaggerID).Update("auth_profile_id", profileID).Error
+	})
+}
@@ -0,0 +1,62 @@
+package dao_test
+
+import (
+	"context"
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	_ "mcp-manager/internal/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestAuthProfileDAO_BindAndDelete(t *testing.T) {
+	ctx := context.Background()
+	documentDAO := dao.NewSwaggerDocumentDAO(nil)
+	serverDAO := dao.NewMCPServerDAO(nil)
+	profiles := dao.NewAuthProfileDAO(nil)
+
+	doc := &model.SwaggerDocument{Title: "Auth API", SpecFormat: model.SpecFormatOpenAPI3, Servers: model.StringList{}}
+	require.NoError(t, documentDAO.Create(ctx, doc))
+	profile := &model.AuthProfile{Name: "auth-dao-test", Type: model.AuthTypeOAuth2ClientCredentials, TokenURL: "https://auth.example.com/token", ClientID: "id", Scopes: model.StringList{"read"}}
+	require.NoError(t, profiles.Create(ctx, profile))
+	server := &model.MCPServer{Name: "auth-dao-test-server", Transport: model.DefaultTransportConfig(), AuthProfileID: &profile.ID}
+	require.NoError(t, serverDAO.Create(ctx, server))
+	t.Cleanup(func() {
+		_ = serverDAO.Delete(ctx, server.ID)
+		_ = profiles.Delete(ctx, profile.ID)
+		_ = documentDAO.Delete(ctx, doc.ID)
+	})
+
+	// 未绑定时返回 nil
+	got, err := profiles.GetByDocumentID(ctx, doc.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	require.NoError(t, profiles.BindDocument(ctx, doc.ID, &profile.ID))
+	got, err = profiles.GetByDocumentID(ctx, doc.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, profile.ID, got.ID)
+	assert.Equal(t, model.StringList{"read"}, got.Scopes)
+
+	// 重复绑定同一配置不视为文档不存在
+	assert.NoError(t, profiles.BindDocument(ctx, doc.ID, &profile.ID))
+	assert.ErrorIs(t, profiles.BindDocument(ctx, doc.ID+1000, &profile.ID), gorm.ErrRecordNotFound)
+
+	// 删除配置时解除文档与 server 的引用
+	require.NoError(t, profiles.Delete(ctx, profile.ID))
+	got, err = profiles.GetByDocumentID(ctx, doc.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	reloadedDoc, err := documentDAO.GetByID(ctx, doc.ID)
+	require.NoError(t, err)
+	assert.Nil(t, reloadedDoc.AuthProfileID)
+	reloadedServer, err := serverDAO.GetByID(ctx, server.ID)
+	require.NoError(t, err)
+	assert.Nil(t, reloadedServer.AuthProfileID)
+
+	assert.ErrorIs(t, profiles.Delete(ctx, profile.ID), gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,123 @@
+package dao
+
+import (
+	"context"
+	"mcp-manager/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// MCPServerDAO 定义对 mcp_servers 及 mcp_server_tools 表的基本操作
+// 推荐通过依赖注入传递 *gorm.DB
+
+type MCPServerDAO interface {
+	Create(ctx context.Context, server *model.MCPServer) error
+	Delete(ctx context.Context, id uint) error
+	Update(ctx context.Context, server *model.MCPServer) error
+	GetByID(ctx context.Context, id uint) (*model.MCPServer, error)
+	GetByName(ctx context.Context, name string) (*model.MCPServer, error)
+	List(ctx context.Context) ([]model.MCPServer, error)
+	// ListByEndpointIDs 返回把 endpointIDs 中任一接口暴露为工具的 server，只加载这些接口的工具绑定
+	ListByEndpointIDs(ctx context.Context, endpointIDs []uint) ([]model.MCPServer, error)
+}
+
+type mcpServerDAO struct {
+	db *gorm.DB
+}
+
+func NewMCPServerDAO(db *gorm.DB) MCPServerDAO {
+	if db == nil {
+		var err error
+		db, err = model.GetMcpManagerDB() // 获取主数据库连接
+		if err != nil {
+			panic("failed to get main DB: " + err.Error())
+		}
+	}
+	return &mcpServerDAO{db: db}
+}
+
+// Create 创建 server 及其工具绑定
+func (d *mcpServerDAO) Create(ctx context.Context, server *model.MCPServer) error {
+	return d.db.WithContext(ctx).Create(server).Error
+}
+
+// Delete 删除 server 及其工具绑定，包括回收站中的绑定
+func (d *mcpServerDAO) Delete(ctx context.Context, id uint) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("server_id = ?", id).Delete(&model.MCPServerTool{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.MCPServer{}, id).Error
+	})
+}
+
+// Update 更新 server 并以 server.Tools 整体替换原有的工具绑定
+// 随接口移入回收站的绑定保留，以便恢复接口时一并恢复，但 server.Tools 重新绑定了同一接口时以新绑定为准
+func (d *mcpServerDAO) Update(ctx context.Context, server *model.MCPServer) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tools").Save(server).Error; err != nil {
+			return err
+		}
+		endpointIDs := make([]uint, len(server.Tools))
+		for i, tool := range server.Tools {
+			endpointIDs[i] = tool.EndpointID
+		}
+		replaced := tx.Where("deleted_at IS NULL")
+		if len(endpointIDs) > 0 {
+			replaced = replaced.Or("endpoint_id IN ?", endpointIDs)
+		}
+		if err := tx.Unscoped().Where("server_id = ?", server.ID).Where(replaced).Delete(&model.MCPServerTool{}).Error; err != nil {
+			return err
+		}
+		for i := range server.Tools {
+			server.Tools[i].ID = 0
+			server.Tools[i].ServerID = server.ID
+		}
+		if len(server.Tools) == 0 {
+			return nil
+		}
+		return tx.Create(&server.Tools).Error
+	})
+}
+
+func (d *mcpServerDAO) GetByID(ctx context.Context, id uint) (*model.MCPServer, error) {
+	var server model.MCPServer
+	err := d.db.WithContext(ctx).Preload("Tools").First(&server, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+func (d *mcpServerDAO) GetByName(ctx context.Context, name string) (*model.MCPServer, error) {
+	var server model.MCPServer
+	err := d.db.WithContext(ctx).Preload("Tools").Where("name = ?", name).First(&server).Error
+	if err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+func (d *mcpServerDAO) List(ctx context.Context) ([]model.MCPServer, error) {
+	var servers []model.MCPServer
+	err := d.db.WithContext(ctx).Preload("Tools").Order("id DESC").Find(&servers).Error
+	return servers, err
+}
+
+func (d *mcpServerDAO) ListByEndpointIDs(ctx context.Context, endpointIDs []uint) ([]model.MCPServer, error) {
+	return listServersByEndpointIDs(d.db.WithContext(ctx), endpointIDs)
+}
+
+// listServersByEndpointIDs 在 db 上查询绑定了 endpointIDs 中任一接口的 server，只加载这些接口的工具绑定
+func listServersByEndpointIDs(db *gorm.DB, endpointIDs []uint) ([]model.MCPServer, error) {
+	var servers []model.MCPServer
+	if len(endpointIDs) == 0 {
+		return servers, nil
+	}
+	err := db.
+		Preload("Tools", "endpoint_id IN ?", endpointIDs).
+		Where("id IN (?)", db.Session(&gorm.Session{NewDB: true}).Model(&model.MCPServerTool{}).Select("server_id").Where("endpoint_id IN ?", endpointIDs)).
+		Order("id").
+		Find(&servers).Error
+	return servers, err
+}
@@ -0,0 +1,65 @@
+package dao_test
+
+import (
+	"context"
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	_ "mcp-manager/internal/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMCPServerDAO_Create_GetByName_Update_Delete(t *testing.T) {
+	d := dao.NewMCPServerDAO(nil)
+	ctx := context.Background()
+
+	// Create
+	server := &model.MCPServer{
+		Name:      "dao-test-server",
+		BaseURL:   "http://localhost:8080",
+		Headers:   model.StringMap{"Authorization": "Bearer test"},
+		Transport: model.DefaultTransportConfig(),
+		Tools: []model.MCPServerTool{
+			{EndpointID: 1, ToolName: "get_user", HiddenParams: model.StringList{"verbose"}},
+		},
+	}
+	err := d.Create(ctx, server)
+	assert.NoError(t, err)
+	assert.NotZero(t, server.ID)
+
+	// GetByName
+	got, err := d.GetByName(ctx, server.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, server.Headers, got.Headers)
+	assert.Equal(t, server.Transport, got.Transport)
+	assert.Len(t, got.Tools, 1)
+
+	// Update 整体替换工具绑定
+	server.Tools = []model.MCPServerTool{
+		{EndpointID: 2, FixedValues: model.StringMap{"id": "1"}},
+		{EndpointID: 3},
+	}
+	err = d.Update(ctx, server)
+	assert.NoError(t, err)
+	got, err = d.GetByID(ctx, server.ID)
+	assert.NoError(t, err)
+	assert.Len(t, got.Tools, 2)
+	assert.Equal(t, model.StringMap{"id": "1"}, got.Tools[0].FixedValues)
+
+	// ListByEndpointIDs 只加载匹配的工具绑定
+	servers, err := d.ListByEndpointIDs(ctx, []uint{3, 99})
+	assert.NoError(t, err)
+	if assert.Len(t, servers, 1) {
+		assert.Equal(t, server.ID, servers[0].ID)
+		assert.Len(t, servers[0].Tools, 1)
+		assert.Equal(t, uint(3), servers[0].Tools[0].EndpointID)
+	}
+
+	// Delete
+	err = d.Delete(ctx, server.ID)
+	assert.NoError(t, err)
+	got, err = d.GetByID(ctx, server.ID)
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,93 @@
+package dao
+
+import (
+	"context"
+	"mcp-manager/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// SecretDAO 定义对 secrets 表的基本操作
+type SecretDAO interface {
+	Create(ctx context.Context, secret *model.Secret) error
+	Delete(ctx context.Context, id uint) error
+	Update(ctx context.Context, secret *model.Secret) error
+	GetByID(ctx context.Context, id uint) (*model.Secret, error)
+	// ListByNames 返回 names 中存在的 secret
+	ListByNames(ctx context.Context, names []string) ([]model.Secret, error)
+	List(ctx context.Context) ([]model.Secret, error)
+	// UpdateKeys 在一个事务中更新 secrets 的主密钥 ID 与加密后的数据密钥，用于主密钥轮换
+	UpdateKeys(ctx context.Context, secrets []model.Secret) error
+}
+
+type secretDAO struct {
+	db *gorm.DB
+}
+
+func NewSecretDAO(db *gorm.DB) SecretDAO {
+	if db == nil {
+		var err error
+		db, err = model.GetMcpManagerDB() // 获取主数据库连接
+		if err != nil {
+			panic("failed to get main DB: " + err.Error())
+		}
+	}
+	return &secretDAO{db: db}
+}
+
+func (d *secretDAO) Create(ctx context.Context, secret *model.Secret) error {
+	return d.db.WithContext(ctx).Create(secret).Error
+}
+
+func (d *secretDAO) Delete(ctx context.Context, id uint) error {
+	result := d.db.WithContext(ctx).Delete(&model.Secret{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (d *secretDAO) Update(ctx context.Context, secret *model.Secret) error {
+	return d.db.WithContext(ctx).Save(secret).Error
+}
+
+func (d *secretDAO) GetByID(ctx context.Context, id uint) (*model.Secret, error) {
+	var secret model.Secret
+	err := d.db.WithContext(ctx).First(&secret, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+func (d *secretDAO) ListByNames(ctx context.Context, names []string) ([]model.Secret, error) {
+	var secrets []model.Secret
+	if len(names) == 0 {
+		return secrets, nil
+	}
+	err := d.db.WithContext(ctx).Where("name IN ?", names).Find(&secrets).Error
+	return secrets, err
+}
+
+func (d *secretDAO) List(ctx context.Context) ([]model.Secret, error) {
+	var secrets []model.Secret
+	err := d.db.WithContext(ctx).Order("name").Find(&secrets).Error
+	return secrets, err
+}
+
+func (d *secretDAO) UpdateKeys(ctx context.Context, secrets []model.Secret) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, secret := range secrets {
+			// 只更新密钥列，轮换不改变 secret 的取值，updated_at 保持不变
+			err := tx.Model(&model.Secret{}).Where("id = ?", secret.ID).
+				UpdateColumns(map[string]interface{}{"key_id": secret.KeyID, "wrapped_key": secret.WrappedKey}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,51 @@
+package dao_test
+
+import (
+	"context"
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	_ "mcp-manager/internal/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSecretDAO_UpdateKeys(t *testing.T) {
+	ctx := context.Background()
+	secrets := dao.NewSecretDAO(nil)
+
+	a := &model.Secret{Name: "secret-dao-a", KeyID: "k1", WrappedKey: []byte{0, 1, 2}, Ciphertext: []byte{3, 4, 255}}
+	b := &model.Secret{Name: "secret-dao-b", KeyID: "k1", WrappedKey: []byte{5}, Ciphertext: []byte{6}}
+	require.NoError(t, secrets.Create(ctx, a))
+	require.NoError(t, secrets.Create(ctx, b))
+	t.Cleanup(func() {
+		_ = secrets.Delete(ctx, a.ID)
+		_ = secrets.Delete(ctx, b.ID)
+	})
+
+	found, err := secrets.ListByNames(ctx, []string{"secret-dao-a", "missing"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, []byte{3, 4, 255}, found[0].Ciphertext)
+
+	// 轮换只更新密钥列
+	before, err := secrets.GetByID(ctx, a.ID)
+	require.NoError(t, err)
+	rotated := *a
+	rotated.KeyID, rotated.WrappedKey = "k2", []byte{9, 9}
+	require.NoError(t, secrets.UpdateKeys(ctx, []model.Secret{rotated}))
+	got, err := secrets.GetByID(ctx, a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", got.KeyID)
+	assert.Equal(t, []byte{9, 9}, got.WrappedKey)
+	assert.Equal(t, []byte{3, 4, 255}, got.Ciphertext)
+	assert.True(t, got.UpdatedAt.Equal(before.UpdatedAt), "rotation does not change updated_at")
+	got, err = secrets.GetByID(ctx, b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "k1", got.KeyID)
+
+	require.NoError(t, secrets.Delete(ctx, b.ID))
+	assert.ErrorIs(t, secrets.Delete(ctx, b.ID), gorm.ErrRecordNotFound)
+}
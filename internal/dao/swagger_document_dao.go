@@ -0,0 +1,198 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"mcp-manager/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SwaggerDocumentDAO 定义对 swagger_documents 表的基本操作
+// 推荐通过依赖注入传递 *gorm.DB
+
+type SwaggerDocumentDAO interface {
+	Create(ctx context.Context, doc *model.SwaggerDocument) error
+	Delete(ctx context.Context, id uint) error
+	Update(ctx context.Context, doc *model.SwaggerDocument) error
+	GetByID(ctx context.Context, id uint) (*model.SwaggerDocument, error)
+	GetBySourceURL(ctx context.Context, sourceURL string) (*model.SwaggerDocument, error)
+	List(ctx context.Context) ([]model.SwaggerDocument, error)
+	// SaveImport 在同一事务中保存文档、应用接口变更并记录修订
+	SaveImport(ctx context.Context, doc *model.SwaggerDocument, changes *EndpointChangeSet, revision *model.SwaggerDocumentRevision) error
+	// Reimport 在同一事务中读取文档现状、由 plan 计算导入计划并保存
+	Reimport(ctx context.Context, id uint, plan ImportPlanFunc) error
+}
+
+// ImportState 为重新导入时在事务中读取到的文档现状
+type ImportState struct {
+	Document  *model.SwaggerDocument         // 加锁读取的文档，plan 对它的修改随导入一起保存
+	Endpoints []model.APIEndpoint            // 文档现有的接口
+	Servers   []model.MCPServer              // 绑定了现有接口的 MCP Server，只加载这些接口的工具绑定
+	Latest    *model.SwaggerDocumentRevision // 最新修订，文档尚无修订时为 nil
+}
+
+// ImportPlan 为重新导入需要在事务中保存的内容
+type ImportPlan struct {
+	Changes  *EndpointChangeSet
+	Baseline *model.SwaggerDocumentRevision // 在本次修订之前记录的当前内容，不需要时为 nil
+	Revision *model.SwaggerDocumentRevision // 本次导入的修订，内容未变化时为 nil
+}
+
+// ImportPlanFunc 根据事务中读取的现状计算导入计划，返回错误时事务回滚
+type ImportPlanFunc func(state *ImportState) (*ImportPlan, error)
+
+// EndpointChangeSet 为一次导入需要应用到 api_endpoints 的变更
+type EndpointChangeSet struct {
+	Create []model.APIEndpoint // 新增的接口，保存后回填 ID
+	Update []model.APIEndpoint // 需要更新的接口
+	Delete []uint              // 需要删除的接口 ID
+}
+
+type swaggerDocumentDAO struct {
+	db *gorm.DB
+}
+
+func NewSwaggerDocumentDAO(db *gorm.DB) SwaggerDocumentDAO {
+	if db == nil {
+		var err error
+		db, err = model.GetMcpManagerDB() // 获取主数据库连接
+		if err != nil {
+			panic("failed to get main DB: " + err.Error())
+		}
+	}
+	return &swaggerDocumentDAO{db: db}
+}
+
+func (d *swaggerDocumentDAO) Create(ctx context.Context, doc *model.SwaggerDocument) error {
+	return d.db.WithContext(ctx).Create(doc).Error
+}
+
+// Delete 将文档、其下的接口以及引用这些接口的工具绑定移入回收站，修订在文档被彻底删除时才删除
+func (d *swaggerDocumentDAO) Delete(ctx context.Context, id uint) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return trashDocument(tx, id)
+	})
+}
+
+func (d *swaggerDocumentDAO) Update(ctx context.Context, doc *model.SwaggerDocument) error {
+	return d.db.WithContext(ctx).Save(doc).Error
+}
+
+func (d *swaggerDocumentDAO) GetByID(ctx context.Context, id uint) (*model.SwaggerDocument, error) {
+	var doc model.SwaggerDocument
+	err := d.db.WithContext(ctx).First(&doc, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// GetBySourceURL 返回来源地址为 sourceURL 的最新文档
+func (d *swaggerDocumentDAO) GetBySourceURL(ctx context.Context, sourceURL string) (*model.SwaggerDocument, error) {
+	var doc model.SwaggerDocument
+	err := d.db.WithContext(ctx).Where("source_url = ?", sourceURL).Order("id DESC").First(&doc).Error
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// SaveImport 在同一事务中保存文档（ID 为 0 时新建）、应用接口变更，revision 非 nil 时记录为文档的下一个修订，
+// 任一步失败整体回滚
+func (d *swaggerDocumentDAO) SaveImport(ctx context.Context, doc *model.SwaggerDocument, changes *EndpointChangeSet, revision *model.SwaggerDocumentRevision) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return saveImport(tx, doc, changes, revision)
+	})
+}
+
+// Reimport 在同一事务中加锁读取文档及其接口、最新修订和绑定了这些接口的 MCP Server，
+// 交给 plan 计算导入计划后依次记录 Baseline、保存文档与 Revision 并应用接口变更，
+// 读取、比较与写入之间不会插入其他导入
+func (d *swaggerDocumentDAO) Reimport(ctx context.Context, id uint, plan ImportPlanFunc) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var doc model.SwaggerDocument
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&doc, id).Error; err != nil {
+			return err
+		}
+		state := &ImportState{Document: &doc}
+		if err := tx.Where("swagger_id = ?", id).Find(&state.Endpoints).Error; err != nil {
+			return err
+		}
+		ids := make([]uint, len(state.Endpoints))
+		for i := range state.Endpoints {
+			ids[i] = state.Endpoints[i].ID
+		}
+		servers, err := listServersByEndpointIDs(tx, ids)
+		if err != nil {
+			return err
+		}
+		state.Servers = servers
+		var latest model.SwaggerDocumentRevision
+		err = tx.Where("swagger_id = ?", id).Order("revision DESC").First(&latest).Error
+		switch {
+		case err == nil:
+			state.Latest = &latest
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		p, err := plan(state)
+		if err != nil {
+			return err
+		}
+		if p.Baseline != nil {
+			p.Baseline.SwaggerID = doc.ID
+			if err := createRevision(tx, p.Baseline); err != nil {
+				return err
+			}
+		}
+		return saveImport(tx, state.Document, p.Changes, p.Revision)
+	})
+}
+
+// saveImport 在事务 tx 中保存文档（ID 为 0 时新建）、应用接口变更，revision 非 nil 时记录为文档的下一个修订
+func saveImport(tx *gorm.DB, doc *model.SwaggerDocument, changes *EndpointChangeSet, revision *model.SwaggerDocumentRevision) error {
+	if err := tx.Save(doc).Error; err != nil {
+		return err
+	}
+	if revision != nil {
+		revision.SwaggerID = doc.ID
+		if err := createRevision(tx, revision); err != nil {
+			return err
+		}
+	}
+	if changes == nil {
+		return nil
+	}
+	if len(changes.Delete) > 0 {
+		// 文档中已不存在的接口移入回收站
+		scope := func(db *gorm.DB) *gorm.DB { return db.Where("swagger_id = ? AND id IN ?", doc.ID, changes.Delete) }
+		if err := trashEndpoints(tx, deletedNow(), scope); err != nil {
+			return err
+		}
+	}
+	for i := range changes.Update {
+		changes.Update[i].SwaggerID = doc.ID
+		if err := updateEndpoint(tx, &changes.Update[i]); err != nil {
+			return err
+		}
+	}
+	for i := range changes.Create {
+		changes.Create[i].ID = 0
+		changes.Create[i].SwaggerID = doc.ID
+		changes.Create[i].Version = 1
+	}
+	if len(changes.Create) == 0 {
+		return nil
+	}
+	return tx.Create(&changes.Create).Error
+}
+
+// List 返回所有文档，不加载原始内容以减少传输量
+func (d *swaggerDocumentDAO) List(ctx context.Context) ([]model.SwaggerDocument, error) {
+	var docs []model.SwaggerDocument
+	err := d.db.WithContext(ctx).Omit("content").Order("id DESC").Find(&docs).Error
+	return docs, err
+}
@@ -0,0 +1,169 @@
+package dao_test
+
+import (
+	"context"
+	"errors"
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	_ "mcp-manager/internal/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwaggerDocumentDAO_Create_GetByID_Update_Delete_List(t *testing.T) {
+	d := dao.NewSwaggerDocumentDAO(nil)
+	ctx := context.Background()
+
+	// Create
+	doc := &model.SwaggerDocument{
+		Title:      "Test API",
+		Version:    "1.0.0",
+		SpecFormat: model.SpecFormatOpenAPI3,
+		Content:    `{"openapi": "3.0.0"}`,
+		Servers:    model.StringList{"http://localhost:8080/api"},
+	}
+	err := d.Create(ctx, doc)
+	assert.NoError(t, err)
+	assert.NotZero(t, doc.ID)
+
+	// GetByID
+	got, err := d.GetByID(ctx, doc.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, doc.Title, got.Title)
+	assert.Equal(t, doc.Servers, got.Servers)
+
+	// Update
+	doc.Title = "Updated API"
+	err = d.Update(ctx, doc)
+	assert.NoError(t, err)
+	got, err = d.GetByID(ctx, doc.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Updated API", got.Title)
+
+	// List
+	docs, err := d.List(ctx)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, docs)
+	for _, item := range docs {
+		assert.Empty(t, item.Content)
+	}
+
+	// Delete
+	err = d.Delete(ctx, doc.ID)
+	assert.NoError(t, err)
+	got, err = d.GetByID(ctx, doc.ID)
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
+
+func TestSwaggerDocumentDAO_SaveImport(t *testing.T) {
+	d := dao.NewSwaggerDocumentDAO(nil)
+	endpointDAO := dao.NewAPIEndpointDAO(nil)
+	ctx := context.Background()
+
+	doc := &model.SwaggerDocument{Title: "Import API", SpecFormat: model.SpecFormatOpenAPI3, Servers: model.StringList{}}
+	err := d.SaveImport(ctx, doc, &dao.EndpointChangeSet{
+		Create: []model.APIEndpoint{
+			{Path: "/a", Method: "GET", Responses: "{}"},
+			{Path: "/b", Method: "GET", Responses: "{}"},
+		},
+	}, &model.SwaggerDocumentRevision{Source: model.RevisionSourceImport})
+	assert.NoError(t, err)
+	assert.NotZero(t, doc.ID)
+	defer func() {
+		_ = endpointDAO.DeleteBySwaggerID(ctx, doc.ID)
+		_ = d.Delete(ctx, doc.ID)
+	}()
+
+	endpoints, err := endpointDAO.List(ctx, doc.ID)
+	assert.NoError(t, err)
+	assert.Len(t, endpoints, 2)
+
+	// 更新 /a、删除 /b、新增 /c
+	updated := endpoints[0]
+	updated.Summary = "updated"
+	changes := &dao.EndpointChangeSet{
+		Create: []model.APIEndpoint{{Path: "/c", Method: "GET", Responses: "{}"}},
+		Update: []model.APIEndpoint{updated},
+		Delete: []uint{endpoints[1].ID},
+	}
+	err = d.SaveImport(ctx, doc, changes, nil)
+	assert.NoError(t, err)
+	assert.NotZero(t, changes.Create[0].ID)
+
+	endpoints, err = endpointDAO.List(ctx, doc.ID)
+	assert.NoError(t, err)
+	assert.Len(t, endpoints, 2)
+
+	got, err := d.GetByID(ctx, doc.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, doc.ID, got.ID)
+}
+
+func TestSwaggerDocumentDAO_Reimport(t *testing.T) {
+	d := dao.NewSwaggerDocumentDAO(nil)
+	endpointDAO := dao.NewAPIEndpointDAO(nil)
+	serverDAO := dao.NewMCPServerDAO(nil)
+	revisionDAO := dao.NewSwaggerRevisionDAO(nil)
+	ctx := context.Background()
+
+	doc := &model.SwaggerDocument{Title: "Reimport API", SpecFormat: model.SpecFormatOpenAPI3, Content: "v1", Checksum: "c1", Servers: model.StringList{}}
+	changes := &dao.EndpointChangeSet{Create: []model.APIEndpoint{
+		{Path: "/a", Method: "GET", Responses: "{}"},
+		{Path: "/b", Method: "GET", Responses: "{}"},
+	}}
+	assert.NoError(t, d.SaveImport(ctx, doc, changes, nil))
+	server := &model.MCPServer{Name: "reimport-mcp", Tools: []model.MCPServerTool{{EndpointID: changes.Create[0].ID}}}
+	assert.NoError(t, serverDAO.Create(ctx, server))
+	defer func() {
+		_ = serverDAO.Delete(ctx, server.ID)
+		_ = revisionDAO.DeleteBySwaggerID(ctx, doc.ID)
+		_ = endpointDAO.DeleteBySwaggerID(ctx, doc.ID)
+		_ = d.Delete(ctx, doc.ID)
+	}()
+
+	// plan 拿到事务中读取的接口与绑定了它们的 server，文档尚无修订
+	err := d.Reimport(ctx, doc.ID, func(state *dao.ImportState) (*dao.ImportPlan, error) {
+		assert.Equal(t, "v1", state.Document.Content)
+		assert.Len(t, state.Endpoints, 2)
+		if assert.Len(t, state.Servers, 1) {
+			assert.Equal(t, "reimport-mcp", state.Servers[0].Name)
+		}
+		assert.Nil(t, state.Latest)
+
+		state.Document.Content = "v2"
+		return &dao.ImportPlan{
+			Changes:  &dao.EndpointChangeSet{Delete: []uint{changes.Create[1].ID}},
+			Baseline: &model.SwaggerDocumentRevision{Content: "v1", Checksum: "c1"},
+			Revision: &model.SwaggerDocumentRevision{Content: "v2", Checksum: "c2"},
+		}, nil
+	})
+	assert.NoError(t, err)
+
+	// 补录的修订在本次修订之前
+	revisions, err := revisionDAO.List(ctx, doc.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, revisions, 2) {
+		assert.Equal(t, "c2", revisions[0].Checksum)
+		assert.Equal(t, 2, revisions[0].Revision)
+		assert.Equal(t, "c1", revisions[1].Checksum)
+	}
+	endpoints, err := endpointDAO.List(ctx, doc.ID)
+	assert.NoError(t, err)
+	assert.Len(t, endpoints, 1)
+
+	// plan 返回错误时不保存任何变更
+	err = d.Reimport(ctx, doc.ID, func(state *dao.ImportState) (*dao.ImportPlan, error) {
+		assert.Equal(t, "v2", state.Document.Content)
+		if assert.NotNil(t, state.Latest) {
+			assert.Equal(t, 2, state.Latest.Revision)
+		}
+		state.Document.Content = "v3"
+		return nil, errors.New("blocked")
+	})
+	assert.EqualError(t, err, "blocked")
+	got, err := d.GetByID(ctx, doc.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", got.Content)
+}
@@ -0,0 +1,85 @@
+package dao
+
+import (
+	"context"
+	"mcp-manager/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// SwaggerRevisionDAO 定义对 swagger_document_revisions 表的基本操作
+// 推荐通过依赖注入传递 *gorm.DB
+
+type SwaggerRevisionDAO interface {
+	// Create 保存修订，修订号为该文档当前最大修订号加一
+	Create(ctx context.Context, revision *model.SwaggerDocumentRevision) error
+	Get(ctx context.Context, swaggerID uint, revision int) (*model.SwaggerDocumentRevision, error)
+	Latest(ctx context.Context, swaggerID uint) (*model.SwaggerDocumentRevision, error)
+	List(ctx context.Context, swaggerID uint) ([]model.SwaggerDocumentRevision, error)
+	DeleteBySwaggerID(ctx context.Context, swaggerID uint) error
+}
+
+type swaggerRevisionDAO struct {
+	db *gorm.DB
+}
+
+func NewSwaggerRevisionDAO(db *gorm.DB) SwaggerRevisionDAO {
+	if db == nil {
+		var err error
+		db, err = model.GetMcpManagerDB() // 获取主数据库连接
+		if err != nil {
+			panic("failed to get main DB: " + err.Error())
+		}
+	}
+	return &swaggerRevisionDAO{db: db}
+}
+
+func (d *swaggerRevisionDAO) Create(ctx context.Context, revision *model.SwaggerDocumentRevision) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createRevision(tx, revision)
+	})
+}
+
+func (d *swaggerRevisionDAO) Get(ctx context.Context, swaggerID uint, revision int) (*model.SwaggerDocumentRevision, error) {
+	var rev model.SwaggerDocumentRevision
+	err := d.db.WithContext(ctx).Where("swagger_id = ? AND revision = ?", swaggerID, revision).First(&rev).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+func (d *swaggerRevisionDAO) Latest(ctx context.Context, swaggerID uint) (*model.SwaggerDocumentRevision, error) {
+	var rev model.SwaggerDocumentRevision
+	err := d.db.WithContext(ctx).Where("swagger_id = ?", swaggerID).Order("revision DESC").First(&rev).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// List 返回文档的所有修订，新的在前，不加载原始内容以减少传输量
+func (d *swaggerRevisionDAO) List(ctx context.Context, swaggerID uint) ([]model.SwaggerDocumentRevision, error) {
+	var revisions []model.SwaggerDocumentRevision
+	err := d.db.WithContext(ctx).Omit("content").Where("swagger_id = ?", swaggerID).Order("revision DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+func (d *swaggerRevisionDAO) DeleteBySwaggerID(ctx context.Context, swaggerID uint) error {
+	return d.db.WithContext(ctx).Where("swagger_id = ?", swaggerID).Delete(&model.SwaggerDocumentRevision{}).Error
+}
+
+// createRevision 在事务 tx 中以下一个修订号保存修订
+func createRevision(tx *gorm.DB, revision *model.SwaggerDocumentRevision) error {
+	var latest int
+	err := tx.Model(&model.SwaggerDocumentRevision{}).
+		Where("swagger_id = ?", revision.SwaggerID).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+	revision.ID = 0
+	revision.Revision = latest + 1
+	return tx.Create(revision).Error
+}
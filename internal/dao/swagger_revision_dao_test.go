@@ -0,0 +1,46 @@
+package dao_test
+
+import (
+	"context"
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	_ "mcp-manager/internal/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwaggerRevisionDAO_Create_Get_Latest_List(t *testing.T) {
+	d := dao.NewSwaggerRevisionDAO(nil)
+	ctx := context.Background()
+	swaggerID := uint(990001)
+	defer d.DeleteBySwaggerID(ctx, swaggerID)
+
+	// 修订号按文档递增
+	first := &model.SwaggerDocumentRevision{SwaggerID: swaggerID, Content: `{"openapi": "3.0.0"}`, Checksum: "a", Source: model.RevisionSourceImport}
+	assert.NoError(t, d.Create(ctx, first))
+	assert.Equal(t, 1, first.Revision)
+	second := &model.SwaggerDocumentRevision{SwaggerID: swaggerID, Content: `{"openapi": "3.0.1"}`, Checksum: "b", Source: model.RevisionSourceSync}
+	assert.NoError(t, d.Create(ctx, second))
+	assert.Equal(t, 2, second.Revision)
+
+	got, err := d.Get(ctx, swaggerID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, first.Content, got.Content)
+
+	latest, err := d.Latest(ctx, swaggerID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, latest.Revision)
+
+	// List 新的在前，不包含原始内容
+	list, err := d.List(ctx, swaggerID)
+	assert.NoError(t, err)
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, 2, list[0].Revision)
+		assert.Empty(t, list[0].Content)
+	}
+
+	assert.NoError(t, d.DeleteBySwaggerID(ctx, swaggerID))
+	_, err = d.Latest(ctx, swaggerID)
+	assert.Error(t, err)
+}
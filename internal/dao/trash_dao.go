@@ -0,0 +1,266 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"mcp-manager/internal/model"
+	"mcp-manager/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// TrashDAO 定义回收站的操作
+// 删除文档或接口时，文档、其下的接口以及引用这些接口的工具绑定以同一时间移入回收站，恢复时一并恢复
+type TrashDAO interface {
+	// ListDocuments 返回回收站中的文档，不加载原始内容
+	ListDocuments(ctx context.Context) ([]model.SwaggerDocument, error)
+	// ListEndpoints 返回单独删除的接口，随文档一起删除的接口不单独列出
+	ListEndpoints(ctx context.Context) ([]model.APIEndpoint, error)
+	// CountEndpoints 返回回收站中各文档随其删除的接口数
+	CountEndpoints(ctx context.Context, swaggerIDs []uint) (map[uint]int64, error)
+	// RestoreDocument 恢复文档及随其删除的接口与工具绑定
+	RestoreDocument(ctx context.Context, id uint) error
+	// RestoreEndpoint 恢复接口及随其删除的工具绑定，所属文档仍在回收站中时返回 ErrDocumentInTrash
+	RestoreEndpoint(ctx context.Context, id uint) error
+	// PurgeDocument 彻底删除回收站中的文档及其接口、工具绑定与修订
+	PurgeDocument(ctx context.Context, id uint) error
+	// PurgeEndpoint 彻底删除回收站中的接口及其工具绑定
+	PurgeEndpoint(ctx context.Context, id uint) error
+	// PurgeBefore 彻底删除在 before 之前移入回收站的文档与接口，返回删除的文档数与接口数
+	PurgeBefore(ctx context.Context, before time.Time) (documents, endpoints int64, err error)
+}
+
+// ErrDocumentInTrash 表示接口所属的文档仍在回收站中
+var ErrDocumentInTrash = errors.New("the document of the endpoint is in the trash, restore the document first")
+
+type trashDAO struct {
+	db *gorm.DB
+}
+
+func NewTrashDAO(db *gorm.DB) TrashDAO {
+	if db == nil {
+		var err error
+		db, err = model.GetMcpManagerDB() // 获取主数据库连接
+		if err != nil {
+			panic("failed to get main DB: " + err.Error())
+		}
+	}
+	return &trashDAO{db: db}
+}
+
+// deletedNow 返回移入回收站的时间
+// 同一次删除的记录以相同的时间恢复，截断到毫秒并使用 UTC，保证各数据库读回的值与写入的相同
+func deletedNow() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// trashEndpoints 将满足 scope 的接口及引用它们的工具绑定以 at 移入回收站
+func trashEndpoints(tx *gorm.DB, at time.Time, scope func(*gorm.DB) *gorm.DB) error {
+	var ids []uint
+	if err := scope(tx.Model(&model.APIEndpoint{})).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Model(&model.MCPServerTool{}).Where("endpoint_id IN ?", ids).Update("deleted_at", at).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.APIEndpoint{}).Where("id IN ?", ids).Update("deleted_at", at).Error
+}
+
+// trashDocument 将文档、其下的接口以及引用这些接口的工具绑定以同一时间移入回收站
+func trashDocument(tx *gorm.DB, id uint) error {
+	at := deletedNow()
+	result := tx.Model(&model.SwaggerDocument{}).Where("id = ?", id).Update("deleted_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return trashEndpoints(tx, at, func(db *gorm.DB) *gorm.DB { return db.Where("swagger_id = ?", id) })
+}
+
+func (d *trashDAO) ListDocuments(ctx context.Context) ([]model.SwaggerDocument, error) {
+	var docs []model.SwaggerDocument
+	err := d.db.WithContext(ctx).Unscoped().Omit("content").Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&docs).Error
+	return docs, err
+}
+
+func (d *trashDAO) ListEndpoints(ctx context.Context) ([]model.APIEndpoint, error) {
+	var endpoints []model.APIEndpoint
+	err := d.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Where("swagger_id NOT IN (?)", d.db.Unscoped().Model(&model.SwaggerDocument{}).Select("id").Where("deleted_at IS NOT NULL")).
+		Order("deleted_at DESC").
+		Find(&endpoints).Error
+	return endpoints, err
+}
+
+func (d *trashDAO) CountEndpoints(ctx context.Context, swaggerIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(swaggerIDs))
+	if len(swaggerIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		SwaggerID uint
+		Count     int64
+	}
+	err := d.db.WithContext(ctx).Unscoped().Model(&model.APIEndpoint{}).
+		Select("swagger_id, COUNT(*) AS count").
+		Where("swagger_id IN ? AND deleted_at IS NOT NULL", swaggerIDs).
+		Group("swagger_id").
+		Scan(&rows).Error
+	for _, row := range rows {
+		counts[row.SwaggerID] = row.Count
+	}
+	return counts, err
+}
+
+func (d *trashDAO) RestoreDocument(ctx context.Context, id uint) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var doc model.SwaggerDocument
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&doc, id).Error; err != nil {
+			return err
+		}
+		at := doc.DeletedAt.Time
+		var ids []uint
+		if err := tx.Unscoped().Model(&model.APIEndpoint{}).Where("swagger_id = ? AND deleted_at = ?", id, at).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			if err := restore(tx, &model.MCPServerTool{}, tx.Where("endpoint_id IN ? AND deleted_at = ?", ids, at)); err != nil {
+				return err
+			}
+			if err := restore(tx, &model.APIEndpoint{}, tx.Where("id IN ?", ids)); err != nil {
+				return err
+			}
+		}
+		return restore(tx, &model.SwaggerDocument{}, tx.Where("id = ?", id))
+	})
+}
+
+func (d *trashDAO) RestoreEndpoint(ctx context.Context, id uint) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var endpoint model.APIEndpoint
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&endpoint, id).Error; err != nil {
+			return err
+		}
+		var trashed int64
+		if err := tx.Unscoped().Model(&model.SwaggerDocument{}).Where("id = ? AND deleted_at IS NOT NULL", endpoint.SwaggerID).Count(&trashed).Error; err != nil {
+			return err
+		}
+		if trashed > 0 {
+			return ErrDocumentInTrash
+		}
+		if err := restore(tx, &model.MCPServerTool{}, tx.Where("endpoint_id = ? AND deleted_at = ?", id, endpoint.DeletedAt.Time)); err != nil {
+			return err
+		}
+		return restore(tx, &model.APIEndpoint{}, tx.Where("id = ?", id))
+	})
+}
+
+// restore 清除满足 cond 的记录的 deleted_at
+func restore(tx *gorm.DB, value interface{}, cond *gorm.DB) error {
+	return tx.Unscoped().Model(value).Where(cond).Update("deleted_at", nil).Error
+}
+
+func (d *trashDAO) PurgeDocument(ctx context.Context, id uint) error {
+	var secrets []string
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var doc model.SwaggerDocument
+		if err := tx.Unscoped().Omit("content").Where("deleted_at IS NOT NULL").First(&doc, id).Error; err != nil {
+			return err
+		}
+		var err error
+		secrets, err = purgeDocuments(tx, []uint{id})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	logger.DefaultRedactor.Remove(secrets...)
+	return nil
+}
+
+func (d *trashDAO) PurgeEndpoint(ctx context.Context, id uint) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var endpoint model.APIEndpoint
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&endpoint, id).Error; err != nil {
+			return err
+		}
+		return purgeEndpoints(tx, []uint{id})
+	})
+}
+
+func (d *trashDAO) PurgeBefore(ctx context.Context, before time.Time) (documents, endpoints int64, err error) {
+	var secrets []string
+	err = d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var docIDs []uint
+		if err := tx.Unscoped().Model(&model.SwaggerDocument{}).Where("deleted_at < ?", before).Pluck("id", &docIDs).Error; err != nil {
+			return err
+		}
+		var endpointIDs []uint
+		if err := tx.Unscoped().Model(&model.APIEndpoint{}).Where("deleted_at < ?", before).Pluck("id", &endpointIDs).Error; err != nil {
+			return err
+		}
+		if err := purgeEndpoints(tx, endpointIDs); err != nil {
+			return err
+		}
+		documents, endpoints = int64(len(docIDs)), int64(len(endpointIDs))
+		var err error
+		secrets, err = purgeDocuments(tx, docIDs)
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	logger.DefaultRedactor.Remove(secrets...)
+	return documents, endpoints, nil
+}
+
+// purgeEndpoints 彻底删除接口及引用它们的工具绑定
+func purgeEndpoints(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Unscoped().Where("endpoint_id IN ?", ids).Delete(&model.MCPServerTool{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("id IN ?", ids).Delete(&model.APIEndpoint{}).Error
+}
+
+// purgeDocuments 彻底删除文档及其接口、工具绑定与修订，返回随文档删除的 secret 名称
+func purgeDocuments(tx *gorm.DB, ids []uint) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var endpointIDs []uint
+	if err := tx.Unscoped().Model(&model.APIEndpoint{}).Where("swagger_id IN ?", ids).Pluck("id", &endpointIDs).Error; err != nil {
+		return nil, err
+	}
+	if err := purgeEndpoints(tx, endpointIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Where("swagger_id IN ?", ids).Delete(&model.SwaggerDocumentRevision{}).Error; err != nil {
+		return nil, err
+	}
+	// 来源地址的请求头保存在自动创建的 secret 中，随文档一起删除
+	var headers []string
+	if err := tx.Unscoped().Model(&model.SwaggerDocument{}).Where("id IN ? AND source_auth_header <> ?", ids, "").Pluck("source_auth_header", &headers).Error; err != nil {
+		return nil, err
+	}
+	names := model.ManagedSecretRefs(headers...)
+	if len(names) > 0 {
+		if err := tx.Where("name IN ?", names).Delete(&model.Secret{}).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Unscoped().Where("id IN ?", ids).Delete(&model.SwaggerDocument{}).Error; err != nil {
+		return nil, err
+	}
+	return names, nil
+}
@@ -0,0 +1,160 @@
+package dao_test
+
+import (
+	"context"
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	_ "mcp-manager/internal/testutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTrashDAO_DeleteRestorePurge(t *testing.T) {
+	ctx := context.Background()
+	documentDAO := dao.NewSwaggerDocumentDAO(nil)
+	endpointDAO := dao.NewAPIEndpointDAO(nil)
+	serverDAO := dao.NewMCPServerDAO(nil)
+	trash := dao.NewTrashDAO(nil)
+
+	doc := &model.SwaggerDocument{Title: "Trash API", SpecFormat: model.SpecFormatOpenAPI3, Servers: model.StringList{}}
+	require.NoError(t, documentDAO.SaveImport(ctx, doc, &dao.EndpointChangeSet{
+		Create: []model.APIEndpoint{
+			{Path: "/a", Method: "GET", Responses: "{}"},
+			{Path: "/b", Method: "GET", Responses: "{}"},
+		},
+	}, &model.SwaggerDocumentRevision{Source: model.RevisionSourceImport}))
+	endpoints, err := endpointDAO.List(ctx, doc.ID)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	a, b := endpoints[0].ID, endpoints[1].ID
+
+	server := &model.MCPServer{
+		Name:      "trash-test-server",
+		Transport: model.DefaultTransportConfig(),
+		Tools:     []model.MCPServerTool{{EndpointID: a}, {EndpointID: b}},
+	}
+	require.NoError(t, serverDAO.Create(ctx, server))
+	t.Cleanup(func() {
+		_ = serverDAO.Delete(ctx, server.ID)
+		_ = trash.PurgeDocument(ctx, doc.ID)
+	})
+	tools := func() int {
+		got, err := serverDAO.GetByID(ctx, server.ID)
+		require.NoError(t, err)
+		return len(got.Tools)
+	}
+
+	// 删除单个接口时其工具绑定一并移入回收站
+	require.NoError(t, endpointDAO.Delete(ctx, a))
+	_, err = endpointDAO.GetByID(ctx, a)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Equal(t, 1, tools())
+	servers, err := serverDAO.ListByEndpointIDs(ctx, []uint{a})
+	assert.NoError(t, err)
+	assert.Empty(t, servers)
+	trashed, err := trash.ListEndpoints(ctx)
+	assert.NoError(t, err)
+	assert.Contains(t, endpointIDs(trashed), a)
+
+	// 更新 server 不影响回收站中的绑定
+	require.NoError(t, serverDAO.Update(ctx, server))
+
+	// 恢复接口时恢复其工具绑定
+	require.NoError(t, trash.RestoreEndpoint(ctx, a))
+	assert.Equal(t, 2, tools())
+	assert.ErrorIs(t, trash.RestoreEndpoint(ctx, a), gorm.ErrRecordNotFound)
+
+	// 先单独删除 b，再删除文档；恢复文档只恢复随文档删除的接口
+	require.NoError(t, endpointDAO.Delete(ctx, b))
+	time.Sleep(2 * time.Millisecond)
+	require.NoError(t, documentDAO.Delete(ctx, doc.ID))
+	_, err = documentDAO.GetByID(ctx, doc.ID)
+	assert.Error(t, err)
+	assert.Equal(t, 0, tools())
+
+	docs, err := trash.ListDocuments(ctx)
+	assert.NoError(t, err)
+	found := false
+	for _, d := range docs {
+		found = found || d.ID == doc.ID
+	}
+	assert.True(t, found)
+	counts, err := trash.CountEndpoints(ctx, []uint{doc.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), counts[doc.ID])
+	trashed, err = trash.ListEndpoints(ctx)
+	assert.NoError(t, err)
+	assert.NotContains(t, endpointIDs(trashed), b)
+
+	assert.ErrorIs(t, trash.RestoreEndpoint(ctx, b), dao.ErrDocumentInTrash)
+	require.NoError(t, trash.RestoreDocument(ctx, doc.ID))
+	endpoints, err = endpointDAO.List(ctx, doc.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{a}, endpointIDs(endpoints))
+	assert.Equal(t, 1, tools())
+
+	// 彻底删除接口后不可恢复
+	require.NoError(t, trash.PurgeEndpoint(ctx, b))
+	assert.ErrorIs(t, trash.RestoreEndpoint(ctx, b), gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, trash.PurgeEndpoint(ctx, a), gorm.ErrRecordNotFound)
+
+	// 超过保留时长的文档连同接口与修订一起彻底删除
+	require.NoError(t, documentDAO.Delete(ctx, doc.ID))
+	documents, purged, err := trash.PurgeBefore(ctx, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, documents, int64(1))
+	assert.GreaterOrEqual(t, purged, int64(1))
+	assert.ErrorIs(t, trash.RestoreDocument(ctx, doc.ID), gorm.ErrRecordNotFound)
+	revisions, err := dao.NewSwaggerRevisionDAO(nil).List(ctx, doc.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, revisions)
+	assert.Equal(t, 0, tools())
+}
+
+func TestTrashDAO_PurgeDocument_SourceAuthSecret(t *testing.T) {
+	ctx := context.Background()
+	documentDAO := dao.NewSwaggerDocumentDAO(nil)
+	secrets := dao.NewSecretDAO(nil)
+	trash := dao.NewTrashDAO(nil)
+
+	header := &model.Secret{Name: "auto.swagger_document.source_auth_header.trash", KeyID: "k1", WrappedKey: []byte{1}, Ciphertext: []byte{2}}
+	shared := &model.Secret{Name: "trash-dao-shared", KeyID: "k1", WrappedKey: []byte{1}, Ciphertext: []byte{2}}
+	require.NoError(t, secrets.Create(ctx, header))
+	require.NoError(t, secrets.Create(ctx, shared))
+	t.Cleanup(func() {
+		_ = secrets.Delete(ctx, header.ID)
+		_ = secrets.Delete(ctx, shared.ID)
+	})
+	managed := &model.SwaggerDocument{Title: "Managed", SpecFormat: model.SpecFormatOpenAPI3, Servers: model.StringList{},
+		SourceURL: "http://trash.test/a", SourceAuthHeader: "{{secret:" + header.Name + "}}"}
+	user := &model.SwaggerDocument{Title: "User", SpecFormat: model.SpecFormatOpenAPI3, Servers: model.StringList{},
+		SourceURL: "http://trash.test/b", SourceAuthHeader: "Bearer {{secret:" + shared.Name + "}}"}
+	for _, doc := range []*model.SwaggerDocument{managed, user} {
+		require.NoError(t, documentDAO.SaveImport(ctx, doc, &dao.EndpointChangeSet{}, &model.SwaggerDocumentRevision{Source: model.RevisionSourceURL}))
+		require.NoError(t, documentDAO.Delete(ctx, doc.ID))
+	}
+
+	// 移入回收站的文档仍可恢复，请求头的 secret 保留
+	_, err := secrets.GetByID(ctx, header.ID)
+	require.NoError(t, err)
+
+	// 彻底删除时只删除自动保存的 secret
+	require.NoError(t, trash.PurgeDocument(ctx, managed.ID))
+	require.NoError(t, trash.PurgeDocument(ctx, user.ID))
+	_, err = secrets.GetByID(ctx, header.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = secrets.GetByID(ctx, shared.ID)
+	assert.NoError(t, err)
+}
+
+func endpointIDs(endpoints []model.APIEndpoint) []uint {
+	ids := make([]uint, len(endpoints))
+	for i, e := range endpoints {
+		ids[i] = e.ID
+	}
+	return ids
+}
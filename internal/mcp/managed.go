@@ -0,0 +1,257 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Transports that can be enabled per managed server.
+const (
+	TransportStreamableHTTP = "streamable_http"
+	TransportSSE            = "sse"
+)
+
+// serverToolProvider exposes the endpoints bound to a managed MCPServer, applying its per-tool overrides.
+type serverToolProvider struct {
+	serverDAO   dao.MCPServerDAO
+	endpointDAO dao.APIEndpointDAO
+	documentDAO dao.SwaggerDocumentDAO
+	profileDAO  dao.AuthProfileDAO
+	serverID    uint
+}
+
+// NewServerToolProvider creates a ToolProvider over the tools of the managed server with the given ID.
+// The server record is reloaded on every call so that edits take effect on open sessions.
+func NewServerToolProvider(serverDAO dao.MCPServerDAO, endpointDAO dao.APIEndpointDAO, documentDAO dao.SwaggerDocumentDAO, profileDAO dao.AuthProfileDAO, serverID uint) ToolProvider {
+	return &serverToolProvider{serverDAO: serverDAO, endpointDAO: endpointDAO, documentDAO: documentDAO, profileDAO: profileDAO, serverID: serverID}
+}
+
+func (p *serverToolProvider) Tools(ctx context.Context) ([]ToolHandle, error) {
+	server, err := p.serverDAO.GetByID(ctx, p.serverID)
+	if err != nil {
+		return nil, err
+	}
+	return ServerTools(ctx, server, p.endpointDAO, p.documentDAO, p.profileDAO)
+}
+
+// ServerTools resolves the tools of a managed server.
+// Bindings whose endpoint no longer exists or is disabled are skipped.
+// The AuthProfile of the server applies to every tool; without one each tool uses the profile of its document.
+func ServerTools(ctx context.Context, server *model.MCPServer, endpointDAO dao.APIEndpointDAO, documentDAO dao.SwaggerDocumentDAO, profileDAO dao.AuthProfileDAO) ([]ToolHandle, error) {
+	ids := make([]uint, 0, len(server.Tools))
+	for _, t := range server.Tools {
+		ids = append(ids, t.EndpointID)
+	}
+	endpoints, err := endpointDAO.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*model.APIEndpoint, len(endpoints))
+	for i := range endpoints {
+		byID[endpoints[i].ID] = &endpoints[i]
+	}
+
+	baseURLs := make(map[uint]string)
+	baseURL := func(swaggerID uint) (string, error) {
+		if server.BaseURL != "" || swaggerID == 0 {
+			return server.BaseURL, nil
+		}
+		if u, ok := baseURLs[swaggerID]; ok {
+			return u, nil
+		}
+		doc, err := documentDAO.GetByID(ctx, swaggerID)
+		if err != nil {
+			return "", err
+		}
+		u := ""
+		if len(doc.Servers) > 0 {
+			u = doc.Servers[0]
+		}
+		baseURLs[swaggerID] = u
+		return u, nil
+	}
+
+	var serverAuth *model.AuthProfile
+	if server.AuthProfileID != nil {
+		if serverAuth, err = profileDAO.GetByID(ctx, *server.AuthProfileID); err != nil {
+			return nil, fmt.Errorf("resolve auth profile of server %d: %w", server.ID, err)
+		}
+	}
+	auths := make(map[uint]*model.AuthProfile)
+	auth := func(swaggerID uint) (*model.AuthProfile, error) {
+		if server.AuthProfileID != nil || swaggerID == 0 {
+			return serverAuth, nil
+		}
+		if a, ok := auths[swaggerID]; ok {
+			return a, nil
+		}
+		a, err := profileDAO.GetByDocumentID(ctx, swaggerID)
+		if err != nil {
+			return nil, err
+		}
+		auths[swaggerID] = a
+		return a, nil
+	}
+
+	names := make(map[string]int)
+	handles := make([]ToolHandle, 0, len(server.Tools))
+	for _, binding := range server.Tools {
+		source, ok := byID[binding.EndpointID]
+		if !ok || source.Disabled {
+			continue
+		}
+		endpoint := *source
+		endpoint.Headers = mergeHeaders(server.Headers, source.Headers)
+		u, err := baseURL(endpoint.SwaggerID)
+		if err != nil {
+			return nil, fmt.Errorf("resolve base url of endpoint %d: %w", endpoint.ID, err)
+		}
+		a, err := auth(endpoint.SwaggerID)
+		if err != nil {
+			return nil, fmt.Errorf("resolve auth profile of endpoint %d: %w", endpoint.ID, err)
+		}
+
+		name := binding.ToolName
+		if name == "" {
+			name = ToolName(&endpoint)
+		}
+		tool := NewEndpointTool(&endpoint, uniqueToolName(names, name))
+		if binding.Description != "" {
+			tool.Description = binding.Description
+		}
+		hidden := append([]string(nil), binding.HiddenParams...)
+		for k := range binding.FixedValues {
+			hidden = append(hidden, k)
+		}
+		tool.InputSchema = hideProperties(tool.InputSchema, hidden)
+
+		handles = append(handles, ToolHandle{
+			Tool:            tool,
+			Endpoint:        &endpoint,
+			BaseURL:         u,
+			Auth:            a,
+			FixedArguments:  binding.FixedValues,
+			HiddenArguments: binding.HiddenParams,
+		})
+	}
+	return handles, nil
+}
+
+// mergeHeaders returns the server default headers overridden by the endpoint headers.
+func mergeHeaders(defaults, headers model.StringMap) model.StringMap {
+	if len(defaults) == 0 {
+		return headers
+	}
+	merged := make(model.StringMap, len(defaults)+len(headers))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range headers {
+		merged[k] = v
+	}
+	return merged
+}
+
+// hideProperties removes the named properties from a JSON Schema object and from its required list.
+func hideProperties(schema json.RawMessage, names []string) json.RawMessage {
+	if len(names) == 0 {
+		return schema
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(schema, &decoded); err != nil {
+		return schema
+	}
+	hidden := make(map[string]bool, len(names))
+	for _, name := range names {
+		hidden[name] = true
+	}
+	if props, ok := decoded["properties"].(map[string]interface{}); ok {
+		for name := range hidden {
+			delete(props, name)
+		}
+	}
+	if required, ok := decoded["required"].([]interface{}); ok {
+		kept := make([]interface{}, 0, len(required))
+		for _, r := range required {
+			if name, _ := r.(string); !hidden[name] {
+				kept = append(kept, r)
+			}
+		}
+		if len(kept) > 0 {
+			decoded["required"] = kept
+		} else {
+			delete(decoded, "required")
+		}
+	}
+	b, err := json.Marshal(decoded)
+	if err != nil {
+		return schema
+	}
+	return b
+}
+
+// ServerFactory builds MCP servers backed by the main database.
+type ServerFactory struct {
+	serverDAO   dao.MCPServerDAO
+	endpointDAO dao.APIEndpointDAO
+	documentDAO dao.SwaggerDocumentDAO
+	profileDAO  dao.AuthProfileDAO
+	executor    service.APIExecutor
+}
+
+// NewServerFactory creates a ServerFactory over the given DAOs. Its servers share one executor,
+// so OAuth2 tokens are cached across servers. Secrets are decrypted with the configured master keys.
+func NewServerFactory(serverDAO dao.MCPServerDAO, endpointDAO dao.APIEndpointDAO, documentDAO dao.SwaggerDocumentDAO, profileDAO dao.AuthProfileDAO, secretDAO dao.SecretDAO) *ServerFactory {
+	executor := NewExecutor(service.NewSecretResolver(secretDAO, service.DefaultKeyring()))
+	return &ServerFactory{serverDAO: serverDAO, endpointDAO: endpointDAO, documentDAO: documentDAO, profileDAO: profileDAO, executor: executor}
+}
+
+// DocumentServer creates a server exposing the endpoints of the given swagger document (0 for every document).
+func (f *ServerFactory) DocumentServer(swaggerID uint) *Server {
+	provider := NewDocumentToolProvider(f.endpointDAO, f.documentDAO, f.profileDAO, swaggerID)
+	return NewServer(Implementation{Name: DefaultServerName, Version: DefaultServerVersion}, provider, f.executor)
+}
+
+// ManagedServer creates a server exposing the tools of a managed MCPServer.
+func (f *ServerFactory) ManagedServer(record *model.MCPServer) *Server {
+	provider := NewServerToolProvider(f.serverDAO, f.endpointDAO, f.documentDAO, f.profileDAO, record.ID)
+	server := NewServer(Implementation{Name: record.Name, Version: DefaultServerVersion}, provider, f.executor)
+	server.SetInstructions(record.Description)
+	server.SetSSEResponses(record.Transport.SSEResponses)
+	return server
+}
+
+// ManagedServerByID loads a managed MCPServer and creates its server.
+func (f *ServerFactory) ManagedServerByID(ctx context.Context, id uint) (*Server, error) {
+	record, err := f.serverDAO.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return f.ManagedServer(record), nil
+}
+
+// Resolver returns a ServerResolver selecting the managed server named by the ":name" path parameter.
+// Servers that have not enabled transport are reported as not found.
+func (f *ServerFactory) Resolver(transport string) ServerResolver {
+	return func(c *gin.Context) (*Server, error) {
+		name := c.Param("name")
+		record, err := f.serverDAO.GetByName(c.Request.Context(), name)
+		if err != nil {
+			return nil, fmt.Errorf("mcp server %q not found", name)
+		}
+		enabled := record.Transport.StreamableHTTP
+		if transport == TransportSSE {
+			enabled = record.Transport.SSE
+		}
+		if !enabled {
+			return nil, fmt.Errorf("mcp server %q does not enable the %s transport", name, transport)
+		}
+		return f.ManagedServer(record), nil
+	}
+}
@@ -0,0 +1,173 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	httpclient "mcp-manager/internal/utils/http"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAPIEndpointDAO 模拟 dao.APIEndpointDAO，仅实现用到的方法
+type MockAPIEndpointDAO struct {
+	mock.Mock
+	dao.APIEndpointDAO
+}
+
+func (m *MockAPIEndpointDAO) ListByIDs(ctx context.Context, ids []uint) ([]model.APIEndpoint, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]model.APIEndpoint), args.Error(1)
+}
+
+// MockSwaggerDocumentDAO 模拟 dao.SwaggerDocumentDAO，仅实现用到的方法
+type MockSwaggerDocumentDAO struct {
+	mock.Mock
+	dao.SwaggerDocumentDAO
+}
+
+func (m *MockSwaggerDocumentDAO) GetByID(ctx context.Context, id uint) (*model.SwaggerDocument, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*model.SwaggerDocument), args.Error(1)
+}
+
+// MockAuthProfileDAO 模拟 dao.AuthProfileDAO，仅实现用到的方法
+type MockAuthProfileDAO struct {
+	mock.Mock
+	dao.AuthProfileDAO
+}
+
+func (m *MockAuthProfileDAO) GetByID(ctx context.Context, id uint) (*model.AuthProfile, error) {
+	args := m.Called(ctx, id)
+	profile, _ := args.Get(0).(*model.AuthProfile)
+	return profile, args.Error(1)
+}
+
+func (m *MockAuthProfileDAO) GetByDocumentID(ctx context.Context, swaggerID uint) (*model.AuthProfile, error) {
+	args := m.Called(ctx, swaggerID)
+	profile, _ := args.Get(0).(*model.AuthProfile)
+	return profile, args.Error(1)
+}
+
+func TestServerTools_Overrides(t *testing.T) {
+	endpointDAO := new(MockAPIEndpointDAO)
+	documentDAO := new(MockSwaggerDocumentDAO)
+	endpoint := *userEndpoint
+	endpoint.Headers = model.StringMap{"X-Trace": "endpoint"}
+	endpointDAO.On("ListByIDs", mock.Anything, []uint{1, 99}).Return([]model.APIEndpoint{endpoint}, nil)
+	documentDAO.On("GetByID", mock.Anything, uint(1)).Return(&model.SwaggerDocument{ID: 1, Servers: model.StringList{"http://doc"}}, nil)
+	profileDAO := new(MockAuthProfileDAO)
+	profileDAO.On("GetByDocumentID", mock.Anything, uint(1)).Return(nil, nil)
+
+	server := &model.MCPServer{
+		ID:      7,
+		Name:    "users",
+		Headers: model.StringMap{"Authorization": "Bearer t", "X-Trace": "server"},
+		Tools: []model.MCPServerTool{
+			{EndpointID: 1, ToolName: "fetch_user", Description: "Fetch a user", FixedValues: model.StringMap{"id": "42"}, HiddenParams: model.StringList{"verbose"}},
+			{EndpointID: 99},
+		},
+	}
+
+	handles, err := ServerTools(context.Background(), server, endpointDAO, documentDAO, profileDAO)
+	require.NoError(t, err)
+	require.Len(t, handles, 1, "bindings of missing endpoints are skipped")
+
+	h := handles[0]
+	assert.Equal(t, "fetch_user", h.Tool.Name)
+	assert.Equal(t, "Fetch a user", h.Tool.Description)
+	assert.Equal(t, "http://doc", h.BaseURL)
+	assert.Equal(t, model.StringMap{"Authorization": "Bearer t", "X-Trace": "endpoint"}, h.Endpoint.Headers)
+	assert.Equal(t, map[string]string{"id": "42"}, h.FixedArguments)
+	assert.Nil(t, h.Auth)
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(h.Tool.InputSchema, &schema))
+	assert.Empty(t, schema["properties"])
+	assert.NotContains(t, schema, "required")
+}
+
+func TestServerTools_BaseURLOverride(t *testing.T) {
+	endpointDAO := new(MockAPIEndpointDAO)
+	documentDAO := new(MockSwaggerDocumentDAO)
+	profileDAO := new(MockAuthProfileDAO)
+	endpointDAO.On("ListByIDs", mock.Anything, []uint{1}).Return([]model.APIEndpoint{*userEndpoint}, nil)
+	profileDAO.On("GetByDocumentID", mock.Anything, uint(1)).Return(&model.AuthProfile{ID: 3, Type: model.AuthTypeBearer}, nil)
+
+	server := &model.MCPServer{BaseURL: "http://override", Tools: []model.MCPServerTool{{EndpointID: 1}}}
+
+	handles, err := ServerTools(context.Background(), server, endpointDAO, documentDAO, profileDAO)
+	require.NoError(t, err)
+	require.Len(t, handles, 1)
+	assert.Equal(t, "getUser", handles[0].Tool.Name)
+	assert.Equal(t, "http://override", handles[0].BaseURL)
+	assert.Equal(t, uint(3), handles[0].Auth.ID, "the document profile applies when the server has none")
+	documentDAO.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestServerTools_ServerAuthOverridesDocument(t *testing.T) {
+	endpointDAO := new(MockAPIEndpointDAO)
+	documentDAO := new(MockSwaggerDocumentDAO)
+	profileDAO := new(MockAuthProfileDAO)
+	other := *userEndpoint
+	other.ID, other.SwaggerID, other.OperationID = 2, 2, "listOrders"
+	endpointDAO.On("ListByIDs", mock.Anything, []uint{1, 2}).Return([]model.APIEndpoint{*userEndpoint, other}, nil)
+	profileDAO.On("GetByID", mock.Anything, uint(5)).Return(&model.AuthProfile{ID: 5, Type: model.AuthTypeBasic}, nil)
+
+	profileID := uint(5)
+	server := &model.MCPServer{ID: 7, BaseURL: "http://override", AuthProfileID: &profileID, Tools: []model.MCPServerTool{{EndpointID: 1}, {EndpointID: 2}}}
+
+	handles, err := ServerTools(context.Background(), server, endpointDAO, documentDAO, profileDAO)
+	require.NoError(t, err)
+	require.Len(t, handles, 2)
+	for _, h := range handles {
+		assert.Equal(t, uint(5), h.Auth.ID)
+	}
+	profileDAO.AssertNumberOfCalls(t, "GetByID", 1)
+	profileDAO.AssertNotCalled(t, "GetByDocumentID", mock.Anything, mock.Anything)
+}
+
+func TestServerTools_SkipsDisabledEndpoints(t *testing.T) {
+	endpointDAO := new(MockAPIEndpointDAO)
+	documentDAO := new(MockSwaggerDocumentDAO)
+	disabled := *userEndpoint
+	disabled.Disabled = true
+	endpointDAO.On("ListByIDs", mock.Anything, []uint{1}).Return([]model.APIEndpoint{disabled}, nil)
+
+	server := &model.MCPServer{BaseURL: "http://override", Tools: []model.MCPServerTool{{EndpointID: 1}}}
+
+	handles, err := ServerTools(context.Background(), server, endpointDAO, documentDAO, new(MockAuthProfileDAO))
+	require.NoError(t, err)
+	assert.Empty(t, handles)
+}
+
+func TestServer_ToolsCall_FixedArguments(t *testing.T) {
+	provider := new(MockToolProvider)
+	executor := new(MockAPIExecutor)
+	provider.On("Tools", mock.Anything).Return([]ToolHandle{{
+		Tool:            NewEndpointTool(userEndpoint, "fetch_user"),
+		Endpoint:        userEndpoint,
+		BaseURL:         "http://localhost:8080",
+		FixedArguments:  map[string]string{"id": "42"},
+		HiddenArguments: []string{"verbose"},
+	}}, nil)
+	// 隐藏参数忽略客户端传入的值，保持默认值
+	executor.On("Execute", mock.Anything, mock.MatchedBy(func(e *model.APIEndpoint) bool {
+		return e.Parameters[0].Value == "42" && e.Parameters[1].Value == "false"
+	}), "http://localhost:8080", (*model.AuthProfile)(nil)).Return(&httpclient.Response{StatusCode: 200, Body: `{"id":42}`}, nil)
+	s := NewServer(Implementation{Name: "test", Version: "1.0.0"}, provider, executor)
+
+	out := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"fetch_user","arguments":{"id":"1","verbose":true}}}`))
+
+	var resp struct {
+		Result CallToolResult `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(out, &resp))
+	assert.False(t, resp.Result.IsError)
+	executor.AssertExpectations(t)
+}
@@ -0,0 +1,150 @@
+// Package mcp implements a Model Context Protocol server that exposes imported API endpoints as tools.
+package mcp
+
+import "encoding/json"
+
+// JSONRPCVersion is the only JSON-RPC version spoken by MCP.
+const JSONRPCVersion = "2.0"
+
+// Protocol versions supported by the server, newest first.
+const (
+	ProtocolVersion20250326 = "2025-03-26"
+	ProtocolVersion20241105 = "2024-11-05"
+	LatestProtocolVersion   = ProtocolVersion20250326
+)
+
+// SupportedProtocolVersions lists the protocol versions the server can negotiate.
+var SupportedProtocolVersions = []string{ProtocolVersion20250326, ProtocolVersion20241105}
+
+// MCP methods handled by the server.
+const (
+	MethodInitialize              = "initialize"
+	MethodPing                    = "ping"
+	MethodToolsList               = "tools/list"
+	MethodToolsCall               = "tools/call"
+	MethodNotificationInitialized = "notifications/initialized"
+	MethodNotificationCancelled   = "notifications/cancelled"
+)
+
+// Standard JSON-RPC error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Request is a JSON-RPC request or notification. Notifications carry no ID.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// IsNotification reports whether the request expects no response.
+func (r *Request) IsNotification() bool {
+	return len(r.ID) == 0
+}
+
+// Response is a JSON-RPC response carrying either a result or an error.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object.
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Implementation describes the name and version of an MCP client or server.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// InitializeParams are sent by the client with the initialize request.
+type InitializeParams struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities"`
+	ClientInfo      Implementation         `json:"clientInfo"`
+}
+
+// ToolsCapability advertises tool support.
+type ToolsCapability struct {
+	ListChanged bool `json:"listChanged"`
+}
+
+// ServerCapabilities describes the features supported by the server.
+type ServerCapabilities struct {
+	Tools *ToolsCapability `json:"tools,omitempty"`
+}
+
+// InitializeResult is returned in response to initialize.
+type InitializeResult struct {
+	ProtocolVersion string             `json:"protocolVersion"`
+	Capabilities    ServerCapabilities `json:"capabilities"`
+	ServerInfo      Implementation     `json:"serverInfo"`
+	Instructions    string             `json:"instructions,omitempty"`
+}
+
+// Tool describes a tool that can be invoked by the client.
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+// ListToolsParams are sent with tools/list.
+type ListToolsParams struct {
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// ListToolsResult is returned in response to tools/list.
+type ListToolsResult struct {
+	Tools      []Tool `json:"tools"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// CallToolParams are sent with tools/call.
+type CallToolParams struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+// Content is a single piece of tool output.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// CallToolResult is returned in response to tools/call.
+// Execution failures are reported with IsError instead of a JSON-RPC error so the model can see them.
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// newResponse builds a successful response.
+func newResponse(id json.RawMessage, result interface{}) *Response {
+	return &Response{JSONRPC: JSONRPCVersion, ID: id, Result: result}
+}
+
+// newErrorResponse builds an error response. A missing id is encoded as null.
+func newErrorResponse(id json.RawMessage, code int, message string) *Response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &Response{JSONRPC: JSONRPCVersion, ID: id, Error: &Error{Code: code, Message: message}}
+}
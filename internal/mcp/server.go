@@ -0,0 +1,322 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/service"
+	"mcp-manager/internal/utils/converter"
+	httpclient "mcp-manager/internal/utils/http"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// bodyArgument is the tool argument that carries the request body.
+const bodyArgument = converter.BodyProperty
+
+// toolCallTimeoutSec bounds the upstream request made by a single tool call.
+const toolCallTimeoutSec = 60
+
+// Default identity advertised by servers created by this package.
+const (
+	DefaultServerName    = "mcp-manager"
+	DefaultServerVersion = "1.0.0"
+)
+
+// Server handles MCP JSON-RPC messages independent of the transport that carries them.
+type Server struct {
+	info         Implementation
+	instructions string
+	sseResponses bool
+	provider     ToolProvider
+	executor     service.APIExecutor
+}
+
+// NewServer creates an MCP server exposing the tools of provider and executing them with executor.
+func NewServer(info Implementation, provider ToolProvider, executor service.APIExecutor) *Server {
+	return &Server{info: info, provider: provider, executor: executor}
+}
+
+// SetInstructions sets the usage hint returned to clients on initialize.
+func (s *Server) SetInstructions(instructions string) {
+	s.instructions = instructions
+}
+
+// SetSSEResponses makes Streamable HTTP requests to this server answer with an event stream when accepted.
+func (s *Server) SetSSEResponses(enabled bool) {
+	s.sseResponses = enabled
+}
+
+// Info returns the server implementation info.
+func (s *Server) Info() Implementation {
+	return s.info
+}
+
+// HandleMessage processes a raw JSON-RPC message, which may be a single request or a batch.
+// It returns the encoded response, or nil when the message only contained notifications.
+func (s *Server) HandleMessage(ctx context.Context, data []byte) []byte {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return encode(newErrorResponse(nil, CodeParseError, "parse error"))
+		}
+		if len(batch) == 0 {
+			return encode(newErrorResponse(nil, CodeInvalidRequest, "empty batch"))
+		}
+		var responses []*Response
+		for _, item := range batch {
+			if resp := s.handleRaw(ctx, item); resp != nil {
+				responses = append(responses, resp)
+			}
+		}
+		if len(responses) == 0 {
+			return nil
+		}
+		return encode(responses)
+	}
+	resp := s.handleRaw(ctx, data)
+	if resp == nil {
+		return nil
+	}
+	return encode(resp)
+}
+
+// handleRaw decodes and dispatches a single JSON-RPC message.
+func (s *Server) handleRaw(ctx context.Context, data []byte) *Response {
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return newErrorResponse(nil, CodeParseError, "parse error")
+	}
+	if req.JSONRPC != JSONRPCVersion || req.Method == "" {
+		return newErrorResponse(req.ID, CodeInvalidRequest, "invalid request")
+	}
+	return s.Handle(ctx, &req)
+}
+
+// Handle dispatches a decoded request. Notifications yield a nil response.
+func (s *Server) Handle(ctx context.Context, req *Request) *Response {
+	result, err := s.dispatch(ctx, req)
+	if req.IsNotification() {
+		if err != nil {
+			log.Warnf("mcp notification %s failed: %v", req.Method, err)
+		}
+		return nil
+	}
+	if err != nil {
+		if rpcErr, ok := err.(*Error); ok {
+			return &Response{JSONRPC: JSONRPCVersion, ID: req.ID, Error: rpcErr}
+		}
+		return newErrorResponse(req.ID, CodeInternalError, err.Error())
+	}
+	return newResponse(req.ID, result)
+}
+
+func (s *Server) dispatch(ctx context.Context, req *Request) (interface{}, error) {
+	switch req.Method {
+	case MethodInitialize:
+		return s.initialize(req.Params)
+	case MethodPing:
+		return struct{}{}, nil
+	case MethodToolsList:
+		return s.listTools(ctx)
+	case MethodToolsCall:
+		return s.callTool(ctx, req.Params)
+	case MethodNotificationInitialized, MethodNotificationCancelled:
+		return nil, nil
+	default:
+		return nil, &Error{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+func (s *Server) initialize(params json.RawMessage) (*InitializeResult, error) {
+	var p InitializeParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: "invalid initialize params"}
+		}
+	}
+	return &InitializeResult{
+		ProtocolVersion: NegotiateProtocolVersion(p.ProtocolVersion),
+		Capabilities:    ServerCapabilities{Tools: &ToolsCapability{}},
+		ServerInfo:      s.info,
+		Instructions:    s.instructions,
+	}, nil
+}
+
+// NegotiateProtocolVersion echoes the client version when supported, otherwise offers the latest one.
+func NegotiateProtocolVersion(requested string) string {
+	for _, v := range SupportedProtocolVersions {
+		if v == requested {
+			return v
+		}
+	}
+	return LatestProtocolVersion
+}
+
+func (s *Server) listTools(ctx context.Context) (*ListToolsResult, error) {
+	handles, err := s.provider.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tools := make([]Tool, 0, len(handles))
+	for _, h := range handles {
+		tools = append(tools, h.Tool)
+	}
+	return &ListToolsResult{Tools: tools}, nil
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (*CallToolResult, error) {
+	var p CallToolParams
+	if err := json.Unmarshal(params, &p); err != nil || p.Name == "" {
+		return nil, &Error{Code: CodeInvalidParams, Message: "invalid tools/call params"}
+	}
+	handles, err := s.provider.Tools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range handles {
+		if h.Tool.Name != p.Name {
+			continue
+		}
+		if err := checkArguments(p.Arguments); err != nil {
+			return errorResult(err), nil
+		}
+		endpoint, err := BindArguments(h.Endpoint, mergeArguments(p.Arguments, h.HiddenArguments, h.FixedArguments))
+		if err != nil {
+			return errorResult(err), nil
+		}
+		resp, err := s.executor.Execute(ctx, endpoint, h.BaseURL, h.Auth)
+		if err != nil {
+			log.Warnf("mcp tool %s call failed: %v", p.Name, err)
+			return errorResult(err), nil
+		}
+		// Upstream error statuses are reported with IsError so the model does not mistake them for results.
+		if resp.StatusCode >= 400 {
+			return &CallToolResult{Content: []Content{{Type: "text", Text: "HTTP " + resp.Status + ": " + resp.Body}}, IsError: true}, nil
+		}
+		return &CallToolResult{Content: []Content{{Type: "text", Text: resp.Body}}}, nil
+	}
+	return nil, &Error{Code: CodeInvalidParams, Message: "unknown tool: " + p.Name}
+}
+
+// BindArguments returns a copy of endpoint whose parameter values are taken from the tool arguments,
+// named as by converter.ArgumentNames. Parameters without a matching argument keep their stored default value.
+// The "body" argument replaces both the stored body and the value of any in=body parameter.
+func BindArguments(endpoint *model.APIEndpoint, args map[string]interface{}) (*model.APIEndpoint, error) {
+	bound := *endpoint
+	bound.Parameters = make(model.APIParameters, len(endpoint.Parameters))
+	copy(bound.Parameters, endpoint.Parameters)
+	names := argumentNames(endpoint.Parameters)
+	for i, param := range bound.Parameters {
+		if param.In == converter.ParameterInBody {
+			continue
+		}
+		v, ok := args[names[i]]
+		if !ok {
+			continue
+		}
+		value, err := argumentString(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %s: %v", names[i], err)
+		}
+		bound.Parameters[i].Value = value
+	}
+	if v, ok := args[bodyArgument]; ok {
+		value, err := argumentString(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %s: %v", bodyArgument, err)
+		}
+		bound.Body = value
+		for i, param := range bound.Parameters {
+			if param.In == "body" {
+				bound.Parameters[i].Value = value
+			}
+		}
+	}
+	return &bound, nil
+}
+
+// argumentNames returns the tool argument name of every parameter.
+func argumentNames(params model.APIParameters) []string {
+	keys := make([]converter.ParameterKey, len(params))
+	for i, param := range params {
+		keys[i] = converter.ParameterKey{In: param.In, Name: param.Name}
+	}
+	return converter.ArgumentNames(keys)
+}
+
+// checkArguments rejects client arguments containing secret references.
+// References are only resolved in stored configuration, otherwise a client could send any secret to the upstream.
+func checkArguments(args map[string]interface{}) error {
+	for k, v := range args {
+		value, err := argumentString(v)
+		if err != nil {
+			return fmt.Errorf("invalid argument %s: %v", k, err)
+		}
+		if model.SecretRefPattern.MatchString(k) || model.SecretRefPattern.MatchString(value) {
+			return fmt.Errorf("invalid argument %s: secret references are not allowed", k)
+		}
+	}
+	return nil
+}
+
+// mergeArguments drops the hidden arguments sent by the client and overlays the fixed arguments of a tool,
+// so that clients cannot set parameters the tool does not expose.
+func mergeArguments(args map[string]interface{}, hidden []string, fixed map[string]string) map[string]interface{} {
+	if len(hidden) == 0 && len(fixed) == 0 {
+		return args
+	}
+	merged := make(map[string]interface{}, len(args)+len(fixed))
+	for k, v := range args {
+		merged[k] = v
+	}
+	for _, k := range hidden {
+		delete(merged, k)
+	}
+	for k, v := range fixed {
+		merged[k] = v
+	}
+	return merged
+}
+
+// argumentString converts a decoded JSON argument to the string form stored on APIParameter.
+// Strings are used verbatim, everything else is re-encoded as JSON.
+func argumentString(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
+
+func errorResult(err error) *CallToolResult {
+	return &CallToolResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true}
+}
+
+func encode(v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Errorf("mcp encode response failed: %v", err)
+		return nil
+	}
+	return b
+}
+
+// NewExecutor creates the executor used for tool calls, resolving secret references through secrets.
+func NewExecutor(secrets service.SecretResolver) service.APIExecutor {
+	client := httpclient.NewHTTPClient(
+		httpclient.WithTimeout(toolCallTimeoutSec),
+		httpclient.WithTransport(httpclient.DefaultTransport()),
+	)
+	return service.NewAPIExecutor(client, secrets)
+}
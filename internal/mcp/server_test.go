@@ -0,0 +1,260 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"mcp-manager/internal/model"
+	httpclient "mcp-manager/internal/utils/http"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockToolProvider 模拟 ToolProvider
+type MockToolProvider struct {
+	mock.Mock
+}
+
+func (m *MockToolProvider) Tools(ctx context.Context) ([]ToolHandle, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]ToolHandle), args.Error(1)
+}
+
+// MockAPIExecutor 模拟 service.APIExecutor
+type MockAPIExecutor struct {
+	mock.Mock
+}
+
+func (m *MockAPIExecutor) Execute(ctx context.Context, endpoint *model.APIEndpoint, baseURL string, auth *model.AuthProfile) (*httpclient.Response, error) {
+	args := m.Called(ctx, endpoint, baseURL, auth)
+	resp, _ := args.Get(0).(*httpclient.Response)
+	return resp, args.Error(1)
+}
+
+var userEndpoint = &model.APIEndpoint{
+	ID:          1,
+	SwaggerID:   1,
+	Path:        "/users/{id}",
+	Method:      "GET",
+	Summary:     "Get user",
+	OperationID: "getUser",
+	Parameters: model.APIParameters{
+		{Name: "id", In: "path", Required: true, Type: "integer"},
+		{Name: "verbose", In: "query", Type: "boolean", Value: "false"},
+	},
+}
+
+func newTestServer() (*Server, *MockToolProvider, *MockAPIExecutor) {
+	provider := new(MockToolProvider)
+	executor := new(MockAPIExecutor)
+	handle := ToolHandle{
+		Tool:     NewEndpointTool(userEndpoint, ToolName(userEndpoint)),
+		Endpoint: userEndpoint,
+		BaseURL:  "http://localhost:8080",
+	}
+	provider.On("Tools", mock.Anything).Return([]ToolHandle{handle}, nil)
+	return NewServer(Implementation{Name: "test", Version: "1.0.0"}, provider, executor), provider, executor
+}
+
+func decodeResponse(t *testing.T, data []byte) map[string]interface{} {
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &resp))
+	return resp
+}
+
+func TestServer_Initialize(t *testing.T) {
+	s, _, _ := newTestServer()
+
+	out := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"c","version":"1"}}}`))
+
+	resp := decodeResponse(t, out)
+	result := resp["result"].(map[string]interface{})
+	assert.Equal(t, ProtocolVersion20241105, result["protocolVersion"])
+	assert.Equal(t, "test", result["serverInfo"].(map[string]interface{})["name"])
+	assert.Contains(t, result["capabilities"], "tools")
+}
+
+func TestServer_Initialize_UnknownVersion(t *testing.T) {
+	s, _, _ := newTestServer()
+
+	out := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`))
+
+	result := decodeResponse(t, out)["result"].(map[string]interface{})
+	assert.Equal(t, LatestProtocolVersion, result["protocolVersion"])
+}
+
+func TestServer_PingAndNotification(t *testing.T) {
+	s, _, _ := newTestServer()
+
+	out := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":"a","method":"ping"}`))
+	resp := decodeResponse(t, out)
+	assert.Equal(t, "a", resp["id"])
+	assert.Equal(t, map[string]interface{}{}, resp["result"])
+
+	out = s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	assert.Nil(t, out)
+}
+
+func TestServer_ToolsList(t *testing.T) {
+	s, _, _ := newTestServer()
+
+	out := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`))
+
+	var resp struct {
+		Result ListToolsResult `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(out, &resp))
+	require.Len(t, resp.Result.Tools, 1)
+	tool := resp.Result.Tools[0]
+	assert.Equal(t, "getUser", tool.Name)
+	assert.Equal(t, "Get user", tool.Description)
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(tool.InputSchema, &schema))
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, []interface{}{"id"}, schema["required"])
+	assert.Contains(t, schema["properties"], "verbose")
+}
+
+func TestServer_ToolsCall(t *testing.T) {
+	s, _, executor := newTestServer()
+	executor.On("Execute", mock.Anything, mock.MatchedBy(func(e *model.APIEndpoint) bool {
+		return e.Parameters[0].Value == "42" && e.Parameters[1].Value == "true"
+	}), "http://localhost:8080", (*model.AuthProfile)(nil)).Return(&httpclient.Response{StatusCode: 200, Status: "200 OK", Body: `{"id":42}`}, nil)
+
+	out := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"getUser","arguments":{"id":42,"verbose":true}}}`))
+
+	var resp struct {
+		Result CallToolResult `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(out, &resp))
+	assert.False(t, resp.Result.IsError)
+	assert.Equal(t, `{"id":42}`, resp.Result.Content[0].Text)
+	// 原始 endpoint 不应被修改
+	assert.Empty(t, userEndpoint.Parameters[0].Value)
+	executor.AssertExpectations(t)
+}
+
+func TestServer_ToolsCall_ExecuteError(t *testing.T) {
+	s, _, executor := newTestServer()
+	executor.On("Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+
+	out := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"getUser","arguments":{"id":1}}}`))
+
+	var resp struct {
+		Result CallToolResult `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(out, &resp))
+	assert.True(t, resp.Result.IsError)
+	assert.Contains(t, resp.Result.Content[0].Text, "connection refused")
+}
+
+func TestServer_ToolsCall_ErrorStatus(t *testing.T) {
+	s, _, executor := newTestServer()
+	executor.On("Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&httpclient.Response{StatusCode: 500, Status: "500 Internal Server Error", Body: "boom"}, nil)
+
+	out := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"getUser","arguments":{"id":1}}}`))
+
+	var resp struct {
+		Result CallToolResult `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(out, &resp))
+	assert.True(t, resp.Result.IsError)
+	assert.Equal(t, "HTTP 500 Internal Server Error: boom", resp.Result.Content[0].Text)
+}
+
+func TestServer_ToolsCall_SecretRefArgument(t *testing.T) {
+	s, _, executor := newTestServer()
+
+	for _, args := range []string{
+		`{"id":"{{secret:DB_PASSWORD}}"}`,
+		`{"id":1,"body":{"note":["{{ secret:DB_PASSWORD }}"]}}`,
+		`{"id":1,"body":{"{{secret:DB_PASSWORD}}":1}}`,
+	} {
+		out := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"getUser","arguments":`+args+`}}`))
+
+		var resp struct {
+			Result CallToolResult `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(out, &resp))
+		assert.True(t, resp.Result.IsError, args)
+		assert.Contains(t, resp.Result.Content[0].Text, "secret references are not allowed", args)
+	}
+	// 请求不会被发送
+	executor.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestServer_Errors(t *testing.T) {
+	s, _, _ := newTestServer()
+
+	resp := decodeResponse(t, s.HandleMessage(context.Background(), []byte(`{bad json`)))
+	assert.Equal(t, float64(CodeParseError), resp["error"].(map[string]interface{})["code"])
+
+	resp = decodeResponse(t, s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":5,"method":"resources/list"}`)))
+	assert.Equal(t, float64(CodeMethodNotFound), resp["error"].(map[string]interface{})["code"])
+
+	resp = decodeResponse(t, s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"missing"}}`)))
+	assert.Equal(t, float64(CodeInvalidParams), resp["error"].(map[string]interface{})["code"])
+}
+
+func TestServer_Batch(t *testing.T) {
+	s, _, _ := newTestServer()
+
+	out := s.HandleMessage(context.Background(), []byte(`[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":2,"method":"ping"}]`))
+
+	var resps []Response
+	require.NoError(t, json.Unmarshal(out, &resps))
+	assert.Len(t, resps, 2)
+}
+
+func TestToolName(t *testing.T) {
+	assert.Equal(t, "getUser", ToolName(&model.APIEndpoint{OperationID: "getUser"}))
+	assert.Equal(t, "get_users_id", ToolName(&model.APIEndpoint{Method: "GET", Path: "/users/{id}"}))
+
+	names := make(map[string]int)
+	assert.Equal(t, "op", uniqueToolName(names, "op"))
+	assert.Equal(t, "op_2", uniqueToolName(names, "op"))
+}
+
+func TestBindArguments_CollidingNames(t *testing.T) {
+	endpoint := &model.APIEndpoint{
+		Path:   "/items/{id}",
+		Method: "PUT",
+		Parameters: model.APIParameters{
+			{Name: "id", In: "path", Required: true},
+			{Name: "id", In: "query"},
+			{Name: "body", In: "query"},
+			{Name: "item", In: "body"},
+		},
+		Body: "{}",
+	}
+
+	schema := decodeResponse(t, parametersSchema(endpoint))
+	assert.ElementsMatch(t, []string{"path_id", "query_id", "query_body", "body"}, mapKeys(schema["properties"].(map[string]interface{})))
+
+	bound, err := BindArguments(endpoint, map[string]interface{}{
+		"path_id":    1,
+		"query_id":   "2",
+		"query_body": "full",
+		"body":       map[string]interface{}{"name": "x"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1", bound.Parameters[0].Value)
+	assert.Equal(t, "2", bound.Parameters[1].Value)
+	assert.Equal(t, "full", bound.Parameters[2].Value)
+	assert.Equal(t, `{"name":"x"}`, bound.Parameters[3].Value)
+	assert.Equal(t, `{"name":"x"}`, bound.Body)
+}
+
+func mapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
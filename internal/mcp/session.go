@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// sessionOutboxSize bounds the number of pending server-to-client messages per session.
+const sessionOutboxSize = 64
+
+// Session is the server-side state of one connected MCP client.
+type Session struct {
+	ID string
+	// Transport is the transport that opened the session, TransportStreamableHTTP or TransportSSE.
+	Transport string
+	// Scope identifies the route the session was opened on; the session is only valid on that route.
+	Scope  string
+	Server *Server
+
+	mu         sync.Mutex
+	lastActive time.Time
+	outbox     chan []byte
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// Touch marks the session as active.
+func (s *Session) Touch() {
+	s.mu.Lock()
+	s.lastActive = time.Now()
+	s.mu.Unlock()
+}
+
+// LastActive returns the time of the last client activity.
+func (s *Session) LastActive() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastActive
+}
+
+// Send queues a message for delivery over the session's event stream.
+// It returns false when the session is closed or the outbox is full.
+func (s *Session) Send(msg []byte) bool {
+	select {
+	case <-s.done:
+		return false
+	default:
+	}
+	select {
+	case s.outbox <- msg:
+		return true
+	case <-s.done:
+		return false
+	default:
+		return false
+	}
+}
+
+// Outbox returns the channel of queued server-to-client messages.
+func (s *Session) Outbox() <-chan []byte {
+	return s.outbox
+}
+
+// Done is closed when the session terminates.
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Session) close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// SessionManager tracks live sessions and expires the idle ones.
+type SessionManager struct {
+	idleTimeout time.Duration
+	sessions    sync.Map
+}
+
+// NewSessionManager creates a SessionManager that closes sessions idle for longer than idleTimeout.
+func NewSessionManager(idleTimeout time.Duration) *SessionManager {
+	return &SessionManager{idleTimeout: idleTimeout}
+}
+
+// Start runs the expiry loop until ctx is cancelled.
+func (m *SessionManager) Start(ctx context.Context) {
+	interval := m.idleTimeout / 2
+	if interval <= 0 || interval > time.Minute {
+		interval = time.Minute
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				m.CloseAll()
+				return
+			case <-ticker.C:
+				m.ExpireIdle()
+			}
+		}
+	}()
+}
+
+// Create registers a new session bound to server, opened by transport on the route identified by scope.
+func (m *SessionManager) Create(server *Server, transport, scope string) *Session {
+	s := &Session{
+		ID:         newSessionID(),
+		Transport:  transport,
+		Scope:      scope,
+		Server:     server,
+		lastActive: time.Now(),
+		outbox:     make(chan []byte, sessionOutboxSize),
+		done:       make(chan struct{}),
+	}
+	m.sessions.Store(s.ID, s)
+	return s
+}
+
+// Get returns the live session with the given ID and marks it active.
+// Sessions opened by another transport or on another route are reported as not found.
+func (m *SessionManager) Get(id, transport, scope string) (*Session, bool) {
+	v, ok := m.sessions.Load(id)
+	if !ok {
+		return nil, false
+	}
+	s := v.(*Session)
+	if s.Transport != transport || s.Scope != scope {
+		return nil, false
+	}
+	s.Touch()
+	return s, true
+}
+
+// Close terminates and forgets the session with the given ID.
+func (m *SessionManager) Close(id string) bool {
+	v, ok := m.sessions.LoadAndDelete(id)
+	if !ok {
+		return false
+	}
+	v.(*Session).close()
+	return true
+}
+
+// CloseAll terminates every session.
+func (m *SessionManager) CloseAll() {
+	m.sessions.Range(func(key, _ interface{}) bool {
+		m.Close(key.(string))
+		return true
+	})
+}
+
+// ExpireIdle closes the sessions that have been idle for longer than the idle timeout.
+func (m *SessionManager) ExpireIdle() int {
+	if m.idleTimeout <= 0 {
+		return 0
+	}
+	expired := 0
+	deadline := time.Now().Add(-m.idleTimeout)
+	m.sessions.Range(func(key, value interface{}) bool {
+		if value.(*Session).LastActive().Before(deadline) {
+			if m.Close(key.(string)) {
+				expired++
+			}
+		}
+		return true
+	})
+	if expired > 0 {
+		log.Infof("mcp expired %d idle sessions", expired)
+	}
+	return expired
+}
+
+// Count returns the number of live sessions.
+func (m *SessionManager) Count() int {
+	n := 0
+	m.sessions.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("mcp: failed to generate session id: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
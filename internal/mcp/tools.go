@@ -0,0 +1,201 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	"regexp"
+	"strings"
+)
+
+// maxToolNameLength is the longest tool name accepted by common MCP clients.
+const maxToolNameLength = 64
+
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// ToolHandle binds an MCP tool to the endpoint that executes it.
+type ToolHandle struct {
+	Tool     Tool
+	Endpoint *model.APIEndpoint
+	BaseURL  string
+	// Auth is applied to every call of the tool when set.
+	Auth *model.AuthProfile
+	// FixedArguments are always sent and override the arguments of the client.
+	FixedArguments map[string]string
+	// HiddenArguments are removed from the input schema and dropped from the arguments of the client.
+	HiddenArguments []string
+}
+
+// ToolProvider supplies the tools exposed by a Server.
+// Tools are resolved on every request so that endpoint edits take effect without a restart.
+type ToolProvider interface {
+	Tools(ctx context.Context) ([]ToolHandle, error)
+}
+
+// documentToolProvider exposes the endpoints of one or all imported swagger documents.
+type documentToolProvider struct {
+	endpointDAO dao.APIEndpointDAO
+	documentDAO dao.SwaggerDocumentDAO
+	profileDAO  dao.AuthProfileDAO
+	swaggerID   uint
+}
+
+// NewDocumentToolProvider creates a ToolProvider over the enabled endpoints of the given document.
+// A swaggerID of 0 exposes the endpoints of every imported document.
+// Tools are called with the AuthProfile bound to their document.
+func NewDocumentToolProvider(endpointDAO dao.APIEndpointDAO, documentDAO dao.SwaggerDocumentDAO, profileDAO dao.AuthProfileDAO, swaggerID uint) ToolProvider {
+	return &documentToolProvider{endpointDAO: endpointDAO, documentDAO: documentDAO, profileDAO: profileDAO, swaggerID: swaggerID}
+}
+
+func (p *documentToolProvider) Tools(ctx context.Context) ([]ToolHandle, error) {
+	var docs []model.SwaggerDocument
+	if p.swaggerID != 0 {
+		doc, err := p.documentDAO.GetByID(ctx, p.swaggerID)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, *doc)
+	} else {
+		all, err := p.documentDAO.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		docs = all
+	}
+
+	names := make(map[string]int)
+	var handles []ToolHandle
+	for _, doc := range docs {
+		endpoints, err := p.endpointDAO.List(ctx, doc.ID)
+		if err != nil {
+			return nil, err
+		}
+		baseURL := ""
+		if len(doc.Servers) > 0 {
+			baseURL = doc.Servers[0]
+		}
+		var auth *model.AuthProfile
+		if doc.AuthProfileID != nil {
+			if auth, err = p.profileDAO.GetByID(ctx, *doc.AuthProfileID); err != nil {
+				return nil, fmt.Errorf("resolve auth profile of document %d: %w", doc.ID, err)
+			}
+		}
+		for i := range endpoints {
+			endpoint := endpoints[i]
+			if endpoint.Disabled {
+				continue
+			}
+			handles = append(handles, ToolHandle{
+				Tool:     NewEndpointTool(&endpoint, uniqueToolName(names, ToolName(&endpoint))),
+				Endpoint: &endpoint,
+				BaseURL:  baseURL,
+				Auth:     auth,
+			})
+		}
+	}
+	return handles, nil
+}
+
+// ToolName derives a tool name from the endpoint's operationId, falling back to method and path.
+func ToolName(endpoint *model.APIEndpoint) string {
+	name := endpoint.OperationID
+	if name == "" {
+		name = strings.ToLower(endpoint.Method) + endpoint.Path
+	}
+	name = strings.Trim(invalidToolNameChars.ReplaceAllString(name, "_"), "_")
+	if name == "" {
+		name = fmt.Sprintf("endpoint_%d", endpoint.ID)
+	}
+	if len(name) > maxToolNameLength {
+		name = name[:maxToolNameLength]
+	}
+	return name
+}
+
+// uniqueToolName appends a numeric suffix when name has already been used.
+func uniqueToolName(names map[string]int, name string) string {
+	names[name]++
+	if names[name] == 1 {
+		return name
+	}
+	suffix := fmt.Sprintf("_%d", names[name])
+	if len(name)+len(suffix) > maxToolNameLength {
+		name = name[:maxToolNameLength-len(suffix)]
+	}
+	return uniqueToolName(names, name+suffix)
+}
+
+// NewEndpointTool builds the MCP tool definition for an endpoint.
+func NewEndpointTool(endpoint *model.APIEndpoint, name string) Tool {
+	description := endpoint.Summary
+	if endpoint.Description != "" && endpoint.Description != endpoint.Summary {
+		if description != "" {
+			description += "\n\n"
+		}
+		description += endpoint.Description
+	}
+	if description == "" {
+		description = strings.ToUpper(endpoint.Method) + " " + endpoint.Path
+	}
+	inputSchema := json.RawMessage(endpoint.InputSchema)
+	if len(inputSchema) == 0 {
+		inputSchema = parametersSchema(endpoint)
+	}
+	return Tool{
+		Name:        name,
+		Description: description,
+		InputSchema: inputSchema,
+	}
+}
+
+// parametersSchema builds a flat JSON Schema object from the endpoint parameters, named as by argumentNames.
+// It is the fallback for endpoints imported without a generated input schema.
+// The request body is exposed as the "body" property.
+func parametersSchema(endpoint *model.APIEndpoint) json.RawMessage {
+	properties := make(map[string]interface{})
+	required := make([]string, 0)
+	names := argumentNames(endpoint.Parameters)
+	for i, param := range endpoint.Parameters {
+		prop := map[string]interface{}{
+			"type":        jsonSchemaType(param),
+			"description": fmt.Sprintf("%s parameter", param.In),
+		}
+		if param.Value != "" {
+			prop["default"] = param.Value
+		}
+		properties[names[i]] = prop
+		if param.Required {
+			required = append(required, names[i])
+		}
+	}
+	if _, ok := properties[bodyArgument]; !ok && endpoint.Body != "" {
+		properties[bodyArgument] = map[string]interface{}{
+			"type":        "object",
+			"description": "request body",
+		}
+	}
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	b, _ := json.Marshal(schema)
+	return b
+}
+
+// jsonSchemaType maps an APIParameter type to a JSON Schema type.
+func jsonSchemaType(param model.APIParameter) string {
+	switch strings.ToLower(param.Type) {
+	case "integer", "number", "boolean", "array", "object", "string":
+		return strings.ToLower(param.Type)
+	case "":
+		if param.In == "body" {
+			return "object"
+		}
+	}
+	return "string"
+}
@@ -0,0 +1,246 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// HeaderSessionID carries the session identifier of the Streamable HTTP transport.
+const HeaderSessionID = "Mcp-Session-Id"
+
+// maxMessageSize bounds the size of a single client message.
+const maxMessageSize = 4 << 20
+
+// sseKeepAliveInterval is the interval between keep-alive comments on open event streams.
+const sseKeepAliveInterval = 30 * time.Second
+
+// ServerResolver selects the MCP server that handles a request.
+type ServerResolver func(c *gin.Context) (*Server, error)
+
+// StaticServer returns a ServerResolver that always selects server.
+func StaticServer(server *Server) ServerResolver {
+	return func(*gin.Context) (*Server, error) {
+		return server, nil
+	}
+}
+
+// HTTPTransport serves MCP over the Streamable HTTP transport and the legacy HTTP+SSE transport.
+type HTTPTransport struct {
+	sessions *SessionManager
+	resolve  ServerResolver
+	// sseResponses answers POST requests with an event stream whenever the client accepts one.
+	sseResponses bool
+}
+
+// HTTPTransportOption customizes an HTTPTransport.
+type HTTPTransportOption func(*HTTPTransport)
+
+// WithSSEResponses makes Streamable HTTP POST requests answer with an event stream when accepted by the client.
+func WithSSEResponses(enabled bool) HTTPTransportOption {
+	return func(t *HTTPTransport) {
+		t.sseResponses = enabled
+	}
+}
+
+// NewHTTPTransport creates an HTTPTransport backed by sessions.
+func NewHTTPTransport(sessions *SessionManager, resolve ServerResolver, opts ...HTTPTransportOption) *HTTPTransport {
+	t := &HTTPTransport{sessions: sessions, resolve: resolve}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// HandlePost serves POST on the Streamable HTTP endpoint.
+// An initialize request opens a session whose ID is returned in the Mcp-Session-Id header;
+// every later request must carry that header.
+func (t *HTTPTransport) HandlePost(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxMessageSize))
+	if err != nil {
+		writeJSONRPCError(c, http.StatusBadRequest, CodeParseError, "failed to read body")
+		return
+	}
+	reqs, err := peekRequests(body)
+	if err != nil {
+		writeJSONRPCError(c, http.StatusBadRequest, CodeParseError, "parse error")
+		return
+	}
+
+	var session *Session
+	if isInitialize(reqs) {
+		server, err := t.resolve(c)
+		if err != nil {
+			writeJSONRPCError(c, http.StatusNotFound, CodeInvalidRequest, err.Error())
+			return
+		}
+		session = t.sessions.Create(server, TransportStreamableHTTP, sessionScope(c))
+		c.Header(HeaderSessionID, session.ID)
+	} else {
+		id := c.GetHeader(HeaderSessionID)
+		if id == "" {
+			writeJSONRPCError(c, http.StatusBadRequest, CodeInvalidRequest, "missing "+HeaderSessionID+" header")
+			return
+		}
+		var ok bool
+		if session, ok = t.sessions.Get(id, TransportStreamableHTTP, sessionScope(c)); !ok {
+			writeJSONRPCError(c, http.StatusNotFound, CodeInvalidRequest, "session not found")
+			return
+		}
+	}
+
+	out := session.Server.HandleMessage(c.Request.Context(), body)
+	if out == nil {
+		c.Status(http.StatusAccepted)
+		return
+	}
+	if (t.sseResponses || session.Server.sseResponses) && acceptsEventStream(c) {
+		startEventStream(c)
+		writeEvent(c, "message", out)
+		return
+	}
+	c.Data(http.StatusOK, "application/json", out)
+}
+
+// HandleGet serves GET on the Streamable HTTP endpoint.
+// The server never initiates messages, so no standalone event stream is offered.
+func (t *HTTPTransport) HandleGet(c *gin.Context) {
+	c.Header("Allow", "POST, DELETE")
+	c.Status(http.StatusMethodNotAllowed)
+}
+
+// HandleDelete serves DELETE on the Streamable HTTP endpoint and terminates the session.
+func (t *HTTPTransport) HandleDelete(c *gin.Context) {
+	id := c.GetHeader(HeaderSessionID)
+	if id == "" {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if _, ok := t.sessions.Get(id, TransportStreamableHTTP, sessionScope(c)); !ok || !t.sessions.Close(id) {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// HandleSSE serves GET on the legacy SSE endpoint.
+// It opens a session, announces the message endpoint and streams responses until the client disconnects
+// or the session expires.
+func (t *HTTPTransport) HandleSSE(c *gin.Context) {
+	server, err := t.resolve(c)
+	if err != nil {
+		c.String(http.StatusNotFound, err.Error())
+		return
+	}
+	session := t.sessions.Create(server, TransportSSE, sessionScope(c))
+	defer t.sessions.Close(session.ID)
+
+	startEventStream(c)
+	endpoint := fmt.Sprintf("%s?sessionId=%s", messagesPath(c.Request.URL.Path), session.ID)
+	writeEvent(c, "endpoint", []byte(endpoint))
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-session.Done():
+			return
+		case msg := <-session.Outbox():
+			writeEvent(c, "message", msg)
+		case <-keepAlive.C:
+			// 连接保持期间视为活跃，避免被空闲回收
+			session.Touch()
+			_, _ = c.Writer.WriteString(": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// HandleMessages serves POST on the legacy message endpoint.
+// Responses are delivered over the session's event stream.
+func (t *HTTPTransport) HandleMessages(c *gin.Context) {
+	session, ok := t.sessions.Get(c.Query("sessionId"), TransportSSE, sessionScope(c))
+	if !ok {
+		c.String(http.StatusNotFound, "session not found")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxMessageSize))
+	if err != nil {
+		c.String(http.StatusBadRequest, "failed to read body")
+		return
+	}
+	if out := session.Server.HandleMessage(c.Request.Context(), body); out != nil {
+		if !session.Send(out) {
+			log.Warnf("mcp session %s dropped response", session.ID)
+			c.String(http.StatusServiceUnavailable, "session unavailable")
+			return
+		}
+	}
+	c.String(http.StatusAccepted, "Accepted")
+}
+
+// sessionScope identifies the server route of a request by its ":name" path parameter,
+// empty for the routes of the default server.
+func sessionScope(c *gin.Context) string {
+	return c.Param("name")
+}
+
+// peekRequests decodes a single message or batch far enough to inspect the methods.
+func peekRequests(body []byte) ([]Request, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var reqs []Request
+		err := json.Unmarshal(body, &reqs)
+		return reqs, err
+	}
+	var req Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	return []Request{req}, nil
+}
+
+func isInitialize(reqs []Request) bool {
+	for _, r := range reqs {
+		if r.Method == MethodInitialize {
+			return true
+		}
+	}
+	return false
+}
+
+// messagesPath derives the legacy message endpoint from the SSE endpoint path.
+func messagesPath(ssePath string) string {
+	return strings.TrimSuffix(ssePath, "/sse") + "/messages"
+}
+
+func acceptsEventStream(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+}
+
+func startEventStream(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+}
+
+func writeEvent(c *gin.Context, event string, data []byte) {
+	_, _ = fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, data)
+	c.Writer.Flush()
+}
+
+func writeJSONRPCError(c *gin.Context, status, code int, message string) {
+	c.Data(status, "application/json", encode(newErrorResponse(nil, code, message)))
+}
@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const initializeMessage = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`
+
+func newTestEngine(sessions *SessionManager, opts ...HTTPTransportOption) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	s, _, _ := newTestServer()
+	transport := NewHTTPTransport(sessions, StaticServer(s), opts...)
+	r := gin.New()
+	r.POST("/mcp", transport.HandlePost)
+	r.GET("/mcp", transport.HandleGet)
+	r.DELETE("/mcp", transport.HandleDelete)
+	r.GET("/sse", transport.HandleSSE)
+	r.POST("/messages", transport.HandleMessages)
+	return r
+}
+
+func doRequest(r http.Handler, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestHTTPTransport_StreamableSession(t *testing.T) {
+	sessions := NewSessionManager(time.Minute)
+	r := newTestEngine(sessions)
+
+	// initialize 建立会话
+	w := doRequest(r, http.MethodPost, "/mcp", initializeMessage, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	sessionID := w.Header().Get(HeaderSessionID)
+	require.NotEmpty(t, sessionID)
+	assert.Equal(t, 1, sessions.Count())
+
+	// 缺少会话头
+	w = doRequest(r, http.MethodPost, "/mcp", `{"jsonrpc":"2.0","id":2,"method":"ping"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 未知会话
+	w = doRequest(r, http.MethodPost, "/mcp", `{"jsonrpc":"2.0","id":2,"method":"ping"}`, map[string]string{HeaderSessionID: "unknown"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 通知返回 202
+	headers := map[string]string{HeaderSessionID: sessionID}
+	w = doRequest(r, http.MethodPost, "/mcp", `{"jsonrpc":"2.0","method":"notifications/initialized"}`, headers)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	// 正常请求
+	w = doRequest(r, http.MethodPost, "/mcp", `{"jsonrpc":"2.0","id":3,"method":"tools/list"}`, headers)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"getUser"`)
+
+	// GET 不提供独立事件流
+	w = doRequest(r, http.MethodGet, "/mcp", "", headers)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	// DELETE 终止会话
+	w = doRequest(r, http.MethodDelete, "/mcp", "", headers)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, 0, sessions.Count())
+	w = doRequest(r, http.MethodPost, "/mcp", `{"jsonrpc":"2.0","id":4,"method":"ping"}`, headers)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHTTPTransport_StreamableSSEResponse(t *testing.T) {
+	r := newTestEngine(NewSessionManager(time.Minute), WithSSEResponses(true))
+
+	w := doRequest(r, http.MethodPost, "/mcp", initializeMessage, map[string]string{"Accept": "application/json, text/event-stream"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "event: message\ndata: {"))
+}
+
+func TestHTTPTransport_LegacySSE(t *testing.T) {
+	sessions := NewSessionManager(time.Minute)
+	srv := httptest.NewServer(newTestEngine(sessions))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/sse")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+
+	event, data := readEvent(t, reader)
+	require.Equal(t, "endpoint", event)
+	require.True(t, strings.HasPrefix(data, "/messages?sessionId="))
+
+	post, err := http.Post(srv.URL+data, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":7,"method":"ping"}`))
+	require.NoError(t, err)
+	post.Body.Close()
+	assert.Equal(t, http.StatusAccepted, post.StatusCode)
+
+	event, data = readEvent(t, reader)
+	assert.Equal(t, "message", event)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":7,"result":{}}`, data)
+
+	post, err = http.Post(srv.URL+"/messages?sessionId=unknown", "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	post.Body.Close()
+	assert.Equal(t, http.StatusNotFound, post.StatusCode)
+}
+
+func TestHTTPTransport_SessionBoundToRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s, _, _ := newTestServer()
+	sessions := NewSessionManager(time.Minute)
+	transport := NewHTTPTransport(sessions, StaticServer(s))
+	r := gin.New()
+	r.POST("/mcp", transport.HandlePost)
+	r.DELETE("/mcp", transport.HandleDelete)
+	r.POST("/messages", transport.HandleMessages)
+	r.POST("/mcp/servers/:name", transport.HandlePost)
+	r.DELETE("/mcp/servers/:name", transport.HandleDelete)
+
+	w := doRequest(r, http.MethodPost, "/mcp/servers/a", initializeMessage, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	sessionID := w.Header().Get(HeaderSessionID)
+	ping := `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+	headers := map[string]string{HeaderSessionID: sessionID}
+
+	// 其他 server 的路由不接受该会话
+	assert.Equal(t, http.StatusNotFound, doRequest(r, http.MethodPost, "/mcp/servers/b", ping, headers).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(r, http.MethodPost, "/mcp", ping, headers).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(r, http.MethodDelete, "/mcp/servers/b", "", headers).Code)
+	// 旧版 SSE 的消息端点不接受 Streamable HTTP 会话
+	assert.Equal(t, http.StatusNotFound, doRequest(r, http.MethodPost, "/messages?sessionId="+sessionID, ping, nil).Code)
+
+	assert.Equal(t, http.StatusOK, doRequest(r, http.MethodPost, "/mcp/servers/a", ping, headers).Code)
+	assert.Equal(t, 1, sessions.Count())
+}
+
+func TestSessionManager_ExpireIdle(t *testing.T) {
+	sessions := NewSessionManager(10 * time.Millisecond)
+	s, _, _ := newTestServer()
+	idle := sessions.Create(s, TransportSSE, "")
+	active := sessions.Create(s, TransportSSE, "")
+
+	time.Sleep(20 * time.Millisecond)
+	active.Touch()
+
+	assert.Equal(t, 1, sessions.ExpireIdle())
+	_, ok := sessions.Get(idle.ID, TransportSSE, "")
+	assert.False(t, ok)
+	_, ok = sessions.Get(active.ID, TransportSSE, "")
+	assert.True(t, ok)
+
+	select {
+	case <-idle.Done():
+	default:
+		t.Fatal("expired session should be closed")
+	}
+	assert.False(t, idle.Send([]byte("x")))
+}
+
+func readEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	var event, data string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && event != "":
+			return event, data
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sync"
+)
+
+// ServeStdio serves newline-delimited JSON-RPC messages read from in and writes one response per line to out.
+// Requests are handled concurrently so that a slow tool call does not block pings or other calls.
+// It returns when in reaches EOF, after all in-flight requests have been answered.
+func ServeStdio(ctx context.Context, server *Server, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+
+	var (
+		wg       sync.WaitGroup
+		writeMu  sync.Mutex
+		writeErr error
+	)
+	write := func(msg []byte) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if writeErr != nil {
+			return
+		}
+		if _, err := out.Write(append(msg, '\n')); err != nil {
+			writeErr = err
+		}
+	}
+
+	for scanner.Scan() {
+		line := append([]byte(nil), scanner.Bytes()...)
+		if len(line) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if resp := server.HandleMessage(ctx, line); resp != nil {
+				write(resp)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return writeErr
+}
@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeStdio(t *testing.T) {
+	s, _, _ := newTestServer()
+	in := strings.NewReader(strings.Join([]string{
+		initializeMessage,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		``,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+	}, "\n"))
+	var out bytes.Buffer
+
+	err := ServeStdio(context.Background(), s, in, &out)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	ids := make(map[string]bool)
+	for _, line := range lines {
+		var resp Response
+		require.NoError(t, json.Unmarshal([]byte(line), &resp))
+		assert.Nil(t, resp.Error)
+		ids[string(resp.ID)] = true
+	}
+	assert.Equal(t, map[string]bool{"1": true, "2": true, "3": true}, ids)
+}
@@ -0,0 +1,120 @@
+package migrate
+
+import (
+	"time"
+
+	"mcp-manager/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// 0001 为初始表结构，取代原先手工执行的 example/api_endpoints.sql
+// 对已按该 SQL 建表的 MySQL 数据库执行时，会把 parameters、responses、headers 等列修正为 json 类型
+// 以下结构体为建表时的快照，后续的表结构变更应新增迁移，而不是修改这些结构体
+
+type swaggerDocument0001 struct {
+	ID               uint             `gorm:"primaryKey;column:id"`
+	Title            string           `gorm:"column:title;type:varchar(255)"`
+	Version          string           `gorm:"column:version;type:varchar(64)"`
+	SpecFormat       string           `gorm:"column:spec_format;type:varchar(16)"`
+	Content          string           `gorm:"column:content;size:16777216"`
+	Servers          model.StringList `gorm:"column:servers;type:json"`
+	Checksum         string           `gorm:"column:checksum;type:varchar(64)"`
+	CreatedBy        string           `gorm:"column:created_by;type:varchar(64)"`
+	SourceURL        string           `gorm:"column:source_url;type:varchar(1024)"`
+	SourceAuthHeader string           `gorm:"column:source_auth_header;type:varchar(1024)"`
+	LastSyncedAt     *time.Time       `gorm:"column:last_synced_at"`
+	SyncError        string           `gorm:"column:sync_error;type:text"`
+	CreatedAt        time.Time        `gorm:"column:created_at"`
+	UpdatedAt        time.Time        `gorm:"column:updated_at"`
+}
+
+func (swaggerDocument0001) TableName() string { return "swagger_documents" }
+
+type swaggerDocumentRevision0001 struct {
+	ID             uint      `gorm:"primaryKey;column:id"`
+	SwaggerID      uint      `gorm:"column:swagger_id;uniqueIndex:idx_swagger_revision"`
+	Revision       int       `gorm:"column:revision;uniqueIndex:idx_swagger_revision"`
+	Title          string    `gorm:"column:title;type:varchar(255)"`
+	Version        string    `gorm:"column:version;type:varchar(64)"`
+	SpecFormat     string    `gorm:"column:spec_format;type:varchar(16)"`
+	Content        string    `gorm:"column:content;size:16777216"`
+	Checksum       string    `gorm:"column:checksum;type:varchar(64)"`
+	Source         string    `gorm:"column:source;type:varchar(16)"`
+	RolledBackFrom int       `gorm:"column:rolled_back_from"`
+	CreatedBy      string    `gorm:"column:created_by;type:varchar(64)"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+}
+
+func (swaggerDocumentRevision0001) TableName() string { return "swagger_document_revisions" }
+
+type apiEndpoint0001 struct {
+	ID          uint                `gorm:"primaryKey;column:id"`
+	SwaggerID   uint                `gorm:"column:swagger_id;index:idx_swagger_id"`
+	Path        string              `gorm:"column:path;type:varchar(255);index:idx_path_method,priority:1"`
+	Method      string              `gorm:"column:method;type:varchar(16);index:idx_path_method,priority:2"`
+	Summary     string              `gorm:"column:summary;type:varchar(255)"`
+	Description string              `gorm:"column:description;type:text"`
+	OperationID string              `gorm:"column:operation_id;type:varchar(64)"`
+	Tags        string              `gorm:"column:tags;type:varchar(255)"`
+	Parameters  model.APIParameters `gorm:"column:parameters;type:json"`
+	Responses   model.JSONText      `gorm:"column:responses;type:json"`
+	Headers     model.StringMap     `gorm:"column:headers;type:json"`
+	Body        string              `gorm:"column:body;type:text"`
+	InputSchema model.JSON          `gorm:"column:input_schema;type:json"`
+	CreatedAt   time.Time           `gorm:"column:created_at"`
+	UpdatedAt   time.Time           `gorm:"column:updated_at"`
+}
+
+func (apiEndpoint0001) TableName() string { return "api_endpoints" }
+
+type mcpServer0001 struct {
+	ID          uint                  `gorm:"primaryKey;column:id"`
+	Name        string                `gorm:"column:name;type:varchar(64);uniqueIndex:idx_mcp_servers_name"`
+	Description string                `gorm:"column:description;type:text"`
+	BaseURL     string                `gorm:"column:base_url;type:varchar(255)"`
+	Headers     model.StringMap       `gorm:"column:headers;type:json"`
+	Transport   model.TransportConfig `gorm:"column:transport;type:json"`
+	Tools       []mcpServerTool0001   `gorm:"foreignKey:ServerID;constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time             `gorm:"column:created_at"`
+	UpdatedAt   time.Time             `gorm:"column:updated_at"`
+}
+
+func (mcpServer0001) TableName() string { return "mcp_servers" }
+
+type mcpServerTool0001 struct {
+	ID           uint             `gorm:"primaryKey;column:id"`
+	ServerID     uint             `gorm:"column:server_id;index:idx_mcp_server_tools_server_id"`
+	EndpointID   uint             `gorm:"column:endpoint_id;index:idx_mcp_server_tools_endpoint_id"`
+	ToolName     string           `gorm:"column:tool_name;type:varchar(64)"`
+	Description  string           `gorm:"column:description;type:text"`
+	HiddenParams model.StringList `gorm:"column:hidden_params;type:json"`
+	FixedValues  model.StringMap  `gorm:"column:fixed_values;type:json"`
+	CreatedAt    time.Time        `gorm:"column:created_at"`
+	UpdatedAt    time.Time        `gorm:"column:updated_at"`
+}
+
+func (mcpServerTool0001) TableName() string { return "mcp_server_tools" }
+
+var initialSchema = Migration{
+	Version: 1,
+	Name:    "initial_schema",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(
+			&swaggerDocument0001{},
+			&swaggerDocumentRevision0001{},
+			&apiEndpoint0001{},
+			&mcpServer0001{},
+			&mcpServerTool0001{},
+		)
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(
+			&mcpServerTool0001{},
+			&mcpServer0001{},
+			&apiEndpoint0001{},
+			&swaggerDocumentRevision0001{},
+			&swaggerDocument0001{},
+		)
+	},
+}
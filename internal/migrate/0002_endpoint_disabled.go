@@ -0,0 +1,22 @@
+package migrate
+
+import "gorm.io/gorm"
+
+// 0002 为 api_endpoints 增加 disabled 列，被禁用的接口不再作为 MCP 工具暴露
+
+type apiEndpoint0002 struct {
+	Disabled bool `gorm:"column:disabled;not null;default:false"`
+}
+
+func (apiEndpoint0002) TableName() string { return "api_endpoints" }
+
+var endpointDisabled = Migration{
+	Version: 2,
+	Name:    "endpoint_disabled",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().AddColumn(&apiEndpoint0002{}, "Disabled")
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropColumn(tx, apiEndpoint0002{}.TableName(), "disabled")
+	},
+}
@@ -0,0 +1,22 @@
+package migrate
+
+import "gorm.io/gorm"
+
+// 0003 为 api_endpoints 增加 version 列，每次更新加 1，用于乐观并发控制，已有的接口从 1 开始
+
+type apiEndpoint0003 struct {
+	Version int64 `gorm:"column:version;not null;default:1"`
+}
+
+func (apiEndpoint0003) TableName() string { return "api_endpoints" }
+
+var endpointVersion = Migration{
+	Version: 3,
+	Name:    "endpoint_version",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().AddColumn(&apiEndpoint0003{}, "Version")
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropColumn(tx, apiEndpoint0003{}.TableName(), "version")
+	},
+}
@@ -0,0 +1,50 @@
+package migrate
+
+import "gorm.io/gorm"
+
+// 0004 为接口、文档与工具绑定增加 deleted_at 列，删除时先移入回收站，超过保留期限后再清除
+
+type apiEndpoint0004 struct {
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
+}
+
+func (apiEndpoint0004) TableName() string { return "api_endpoints" }
+
+type swaggerDocument0004 struct {
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
+}
+
+func (swaggerDocument0004) TableName() string { return "swagger_documents" }
+
+type mcpServerTool0004 struct {
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
+}
+
+func (mcpServerTool0004) TableName() string { return "mcp_server_tools" }
+
+var softDelete = Migration{
+	Version: 4,
+	Name:    "soft_delete",
+	Up: func(tx *gorm.DB) error {
+		for _, table := range []interface{}{&apiEndpoint0004{}, &swaggerDocument0004{}, &mcpServerTool0004{}} {
+			if err := tx.Migrator().AddColumn(table, "DeletedAt"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateIndex(table, "DeletedAt"); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, table := range []interface{ TableName() string }{&mcpServerTool0004{}, &swaggerDocument0004{}, &apiEndpoint0004{}} {
+			if err := tx.Migrator().DropIndex(table, "DeletedAt"); err != nil {
+				return err
+			}
+			if err := dropColumn(tx, table.TableName(), "deleted_at"); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
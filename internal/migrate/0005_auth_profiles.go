@@ -0,0 +1,75 @@
+package migrate
+
+import (
+	"time"
+
+	"mcp-manager/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// 0005 新增 auth_profiles 表保存上游接口的鉴权配置，文档与 MCP server 通过 auth_profile_id 引用
+
+type authProfile0005 struct {
+	ID           uint             `gorm:"primaryKey;column:id"`
+	Name         string           `gorm:"column:name;type:varchar(64);uniqueIndex:idx_auth_profiles_name"`
+	Type         string           `gorm:"column:type;type:varchar(32)"`
+	Description  string           `gorm:"column:description;type:text"`
+	SchemeName   string           `gorm:"column:scheme_name;type:varchar(64)"`
+	In           string           `gorm:"column:param_in;type:varchar(16)"`
+	ParamName    string           `gorm:"column:param_name;type:varchar(128)"`
+	Value        string           `gorm:"column:value;type:text"`
+	Username     string           `gorm:"column:username;type:varchar(255)"`
+	Password     string           `gorm:"column:password;type:text"`
+	Token        string           `gorm:"column:token;type:text"`
+	TokenURL     string           `gorm:"column:token_url;type:varchar(1024)"`
+	ClientID     string           `gorm:"column:client_id;type:varchar(255)"`
+	ClientSecret string           `gorm:"column:client_secret;type:text"`
+	Scopes       model.StringList `gorm:"column:scopes;type:json"`
+	CreatedAt    time.Time        `gorm:"column:created_at"`
+	UpdatedAt    time.Time        `gorm:"column:updated_at"`
+}
+
+func (authProfile0005) TableName() string { return "auth_profiles" }
+
+type swaggerDocument0005 struct {
+	AuthProfileID *uint `gorm:"column:auth_profile_id;index"`
+}
+
+func (swaggerDocument0005) TableName() string { return "swagger_documents" }
+
+type mcpServer0005 struct {
+	AuthProfileID *uint `gorm:"column:auth_profile_id;index"`
+}
+
+func (mcpServer0005) TableName() string { return "mcp_servers" }
+
+var authProfiles = Migration{
+	Version: 5,
+	Name:    "auth_profiles",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.Migrator().CreateTable(&authProfile0005{}); err != nil {
+			return err
+		}
+		for _, table := range []interface{}{&swaggerDocument0005{}, &mcpServer0005{}} {
+			if err := tx.Migrator().AddColumn(table, "AuthProfileID"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateIndex(table, "AuthProfileID"); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, table := range []interface{ TableName() string }{&mcpServer0005{}, &swaggerDocument0005{}} {
+			if err := tx.Migrator().DropIndex(table, "AuthProfileID"); err != nil {
+				return err
+			}
+			if err := dropColumn(tx, table.TableName(), "auth_profile_id"); err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropTable(&authProfile0005{})
+	},
+}
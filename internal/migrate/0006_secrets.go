@@ -0,0 +1,33 @@
+package migrate
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 0006 新增 secrets 表，以信封加密保存上游凭证，接口、MCP server 与鉴权配置通过 {{secret:NAME}} 引用
+
+type secret0006 struct {
+	ID          uint      `gorm:"primaryKey;column:id"`
+	Name        string    `gorm:"column:name;type:varchar(64);uniqueIndex:idx_secrets_name"`
+	Description string    `gorm:"column:description;type:text"`
+	KeyID       string    `gorm:"column:key_id;type:varchar(64)"`
+	WrappedKey  []byte    `gorm:"column:wrapped_key"`
+	Ciphertext  []byte    `gorm:"column:ciphertext"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at"`
+}
+
+func (secret0006) TableName() string { return "secrets" }
+
+var secrets = Migration{
+	Version: 6,
+	Name:    "secrets",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&secret0006{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&secret0006{})
+	},
+}
@@ -0,0 +1,160 @@
+// Package migrate 管理数据库表结构的版本化迁移
+// 每个迁移包含按版本号顺序执行的 Up 与 Down 步骤，已执行的版本记录在 schema_migrations 表中
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Migration 为一次版本化的表结构变更
+// Up 与 Down 只能通过传入的 tx 操作数据库，且不应引用 model 中会继续演进的结构体，以保证历史迁移的结果不变
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// Status 为迁移的执行状态
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"` // 未执行时为 nil
+}
+
+// schemaMigration 为 schema_migrations 表中的一行
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false;column:version"`
+	Name      string    `gorm:"column:name;type:varchar(255)"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator 在一个数据库上执行迁移
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New 创建 Migrator，migrations 按版本号排序，版本号必须唯一
+func New(db *gorm.DB, migrations []Migration) (*Migrator, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i := range sorted {
+		if sorted[i].Up == nil || sorted[i].Down == nil {
+			return nil, fmt.Errorf("migration %d %s must define up and down", sorted[i].Version, sorted[i].Name)
+		}
+		if i > 0 && sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d", sorted[i].Version)
+		}
+	}
+	return &Migrator{db: db, migrations: sorted}, nil
+}
+
+// Up 按版本号顺序执行所有未执行的迁移，返回本次执行的迁移
+// 每个迁移与其版本记录在同一事务中提交；MySQL 的 DDL 会隐式提交，失败时需要人工检查表结构
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migrate up %d %s: %w", migration.Version, migration.Name, err)
+		}
+		log.Infof("migration %d %s applied", migration.Version, migration.Name)
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down 按版本号倒序回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migrate down %d %s: %w", migration.Version, migration.Name, err)
+		}
+		log.Infof("migration %d %s rolled back", migration.Version, migration.Name)
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Status 返回所有迁移的执行状态，按版本号排序
+// 数据库中存在而代码中没有的版本（通常来自更新的程序版本）同样返回
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int64]bool, len(m.migrations))
+	out := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		out = append(out, status)
+	}
+	for version, row := range applied {
+		if !known[version] {
+			appliedAt := row.AppliedAt
+			out = append(out, Status{Version: version, Name: row.Name, AppliedAt: &appliedAt})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// applied 返回已执行的迁移，schema_migrations 表不存在时先创建
+func (m *Migrator) applied(ctx context.Context) (map[int64]schemaMigration, error) {
+	db := m.db.WithContext(ctx)
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[int64]schemaMigration, len(rows))
+	for _, row := range rows {
+		out[row.Version] = row
+	}
+	return out, nil
+}
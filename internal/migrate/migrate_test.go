@@ -0,0 +1,128 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mcp-manager/pkg/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	manager, err := db.NewSQLiteManager(db.Config{Path: ":memory:"})
+	require.NoError(t, err)
+	conn, err := manager.Connect()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := conn.DB()
+		_ = sqlDB.Close()
+	})
+	return conn
+}
+
+func createTable(name string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.Exec("CREATE TABLE " + name + " (id INTEGER PRIMARY KEY)").Error
+	}
+}
+
+func dropTable(name string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.Exec("DROP TABLE " + name).Error
+	}
+}
+
+func TestMigrator_UpDownStatus(t *testing.T) {
+	conn := newTestDB(t)
+	ctx := context.Background()
+	// 注册顺序与版本号无关
+	migrator, err := New(conn, []Migration{
+		{Version: 2, Name: "create_b", Up: createTable("b"), Down: dropTable("b")},
+		{Version: 1, Name: "create_a", Up: createTable("a"), Down: dropTable("a")},
+	})
+	require.NoError(t, err)
+
+	done, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, done, 2)
+	assert.Equal(t, int64(1), done[0].Version)
+	assert.True(t, conn.Migrator().HasTable("a"))
+	assert.True(t, conn.Migrator().HasTable("b"))
+
+	// 再次执行时没有未执行的迁移
+	done, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, done)
+
+	done, err = migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, done, 1)
+	assert.Equal(t, int64(2), done[0].Version)
+	assert.False(t, conn.Migrator().HasTable("b"))
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+}
+
+func TestMigrator_UpRollsBackFailedMigration(t *testing.T) {
+	conn := newTestDB(t)
+	ctx := context.Background()
+	migrator, err := New(conn, []Migration{
+		{Version: 1, Name: "create_a", Up: createTable("a"), Down: dropTable("a")},
+		{Version: 2, Name: "broken", Up: func(tx *gorm.DB) error {
+			if err := createTable("b")(tx); err != nil {
+				return err
+			}
+			return errors.New("boom")
+		}, Down: dropTable("b")},
+	})
+	require.NoError(t, err)
+
+	done, err := migrator.Up(ctx)
+	require.ErrorContains(t, err, "boom")
+	require.Len(t, done, 1)
+	// 失败的迁移不留下表，也不记录版本
+	assert.False(t, conn.Migrator().HasTable("b"))
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, statuses[1].AppliedAt)
+}
+
+func TestNew_RejectsDuplicateVersions(t *testing.T) {
+	_, err := New(nil, []Migration{
+		{Version: 1, Name: "a", Up: createTable("a"), Down: dropTable("a")},
+		{Version: 1, Name: "b", Up: createTable("b"), Down: dropTable("b")},
+	})
+	assert.Error(t, err)
+}
+
+func TestMigrations_InitialSchema(t *testing.T) {
+	conn := newTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, Run(ctx, conn))
+	for _, table := range []string{"swagger_documents", "swagger_document_revisions", "api_endpoints", "mcp_servers", "mcp_server_tools"} {
+		assert.True(t, conn.Migrator().HasTable(table), table)
+	}
+	assert.True(t, conn.Migrator().HasIndex("api_endpoints", "idx_path_method"))
+
+	migrator, err := New(conn, Migrations())
+	require.NoError(t, err)
+	// 回滚增加列的迁移不影响其他列的索引
+	_, err = migrator.Down(ctx, len(Migrations())-1)
+	require.NoError(t, err)
+	assert.True(t, conn.Migrator().HasIndex("api_endpoints", "idx_path_method"))
+	for _, column := range []string{"disabled", "version", "deleted_at"} {
+		assert.False(t, conn.Migrator().HasColumn("api_endpoints", column), column)
+	}
+	_, err = migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, conn.Migrator().HasTable("api_endpoints"))
+	assert.True(t, conn.Migrator().HasTable("schema_migrations"))
+}
@@ -0,0 +1,36 @@
+package migrate
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Migrations 返回所有已注册的迁移，新增迁移时追加到末尾，版本号递增
+func Migrations() []Migration {
+	return []Migration{
+		initialSchema,
+		endpointDisabled,
+		endpointVersion,
+		softDelete,
+		authProfiles,
+		secrets,
+	}
+}
+
+// Run 在 db 上执行所有未执行的迁移
+func Run(ctx context.Context, db *gorm.DB) error {
+	migrator, err := New(db, Migrations())
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up(ctx)
+	return err
+}
+
+// dropColumn 直接删除 table 的 column 列，列上的索引需先删除
+// SQLite 上 Migrator().DropColumn 会重建表并丢失其他列的索引，Down 中删除列统一使用该函数
+func dropColumn(tx *gorm.DB, table, column string) error {
+	return tx.Exec("ALTER TABLE ? DROP COLUMN ?", clause.Table{Name: table}, clause.Column{Name: column}).Error
+}
@@ -5,24 +5,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // APIEndpoint represents an API endpoint in the system.
 type APIEndpoint struct {
-	ID          uint          `gorm:"primaryKey;column:id" json:"id"`                           // Unique identifier for the endpoint
-	SwaggerID   uint          `gorm:"column:swagger_id" json:"swagger_id"`                      // ID from the Swagger/OpenAPI specification
-	Path        string        `gorm:"column:path;type:varchar(255)" json:"path"`                // URL path of the endpoint
-	Method      string        `gorm:"column:method;type:varchar(16)" json:"method"`             // HTTP method (GET, POST, etc.)
-	Summary     string        `gorm:"column:summary;type:varchar(255)" json:"summary"`          // Brief summary of the endpoint
-	Description string        `gorm:"column:description;type:text" json:"description"`          // Detailed description of the endpoint
-	OperationID string        `gorm:"column:operation_id;type:varchar(64)" json:"operation_id"` // Unique operation ID
-	Tags        string        `gorm:"column:tags;type:varchar(255)" json:"tags"`                // Tags associated with the endpoint
-	Parameters  APIParameters `gorm:"type:json;column:parameters" json:"parameters"`            // List of parameters for the endpoint
-	Responses   string        `gorm:"column:responses;type:json" json:"responses"`              // Responses returned by the endpoint
-	Headers     StringMap     `gorm:"type:json;column:headers" json:"headers"`                  // Headers associated with the endpoint
-	Body        string        `gorm:"column:body;type:text" json:"body"`                        // Request body for the endpoint
-	CreatedAt   time.Time     `gorm:"column:created_at;autoCreateTime" json:"created_at"`       // Timestamp when the endpoint was created
-	UpdatedAt   time.Time     `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`       // Timestamp when the endpoint was last updated
+	ID          uint           `gorm:"primaryKey;column:id" json:"id"`                           // Unique identifier for the endpoint
+	SwaggerID   uint           `gorm:"column:swagger_id" json:"swagger_id"`                      // ID from the Swagger/OpenAPI specification
+	Path        string         `gorm:"column:path;type:varchar(255)" json:"path"`                // URL path of the endpoint
+	Method      string         `gorm:"column:method;type:varchar(16)" json:"method"`             // HTTP method (GET, POST, etc.)
+	Summary     string         `gorm:"column:summary;type:varchar(255)" json:"summary"`          // Brief summary of the endpoint
+	Description string         `gorm:"column:description;type:text" json:"description"`          // Detailed description of the endpoint
+	OperationID string         `gorm:"column:operation_id;type:varchar(64)" json:"operation_id"` // Unique operation ID
+	Tags        string         `gorm:"column:tags;type:varchar(255)" json:"tags"`                // Tags associated with the endpoint
+	Parameters  APIParameters  `gorm:"type:json;column:parameters" json:"parameters"`            // List of parameters for the endpoint
+	Responses   JSONText       `gorm:"column:responses;type:json" json:"responses"`              // Responses returned by the endpoint
+	Headers     StringMap      `gorm:"type:json;column:headers" json:"headers"`                  // Headers associated with the endpoint
+	Body        string         `gorm:"column:body;type:text" json:"body"`                        // Request body for the endpoint
+	InputSchema JSON           `gorm:"column:input_schema;type:json" json:"input_schema"`        // JSON Schema of the endpoint input, served as the MCP tool inputSchema
+	Disabled    bool           `gorm:"column:disabled;not null;default:false" json:"disabled"`   // Disabled endpoints are not exposed as MCP tools
+	Version     int64          `gorm:"column:version;not null" json:"version"`                   // Incremented on every update, used for optimistic concurrency and as the ETag
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`       // Timestamp when the endpoint was created
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`       // Timestamp when the endpoint was last updated
+	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at"`                // Timestamp when the endpoint was moved to the trash
 }
 
 // APIParameter represents a single parameter in an API endpoint.
@@ -85,3 +91,47 @@ func (m *StringMap) Scan(value interface{}) error {
 	}
 	return json.Unmarshal(bytes, m)
 }
+
+// JSON is a raw JSON document stored in a JSON column.
+type JSON json.RawMessage
+
+// Value converts JSON to a database-compatible format. An empty document is stored as NULL.
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return string(j), nil
+}
+
+// Scan converts a database value back to JSON.
+func (j *JSON) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		*j = JSON(v)
+	case []byte:
+		*j = append(JSON(nil), v...)
+	case nil:
+		*j = nil
+	default:
+		return fmt.Errorf("unsupported type: %T", value)
+	}
+	return nil
+}
+
+// MarshalJSON returns the raw document, or null when empty.
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+// UnmarshalJSON stores a copy of the raw document.
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*j = nil
+		return nil
+	}
+	*j = append((*j)[0:0], data...)
+	return nil
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Authentication types supported by AuthProfile.Type.
+const (
+	AuthTypeAPIKey                  = "api_key"                   // API key sent in a header, query parameter or cookie
+	AuthTypeBasic                   = "basic"                     // HTTP basic authentication
+	AuthTypeBearer                  = "bearer"                    // HTTP bearer token
+	AuthTypeOAuth2ClientCredentials = "oauth2_client_credentials" // OAuth2 client credentials grant, the token is fetched and cached
+)
+
+// Locations of an API key, AuthProfile.In.
+const (
+	AuthInHeader = "header"
+	AuthInQuery  = "query"
+	AuthInCookie = "cookie"
+)
+
+// AuthProfile holds reusable credentials for an upstream API.
+// A profile bound to a SwaggerDocument is applied to its endpoint tests and tool calls,
+// a profile bound to an MCPServer overrides the document profiles for the tools of that server.
+// Value, Password, Token and ClientSecret are write-only and never returned by the API;
+// they are stored as secret references, plaintext credentials are saved as managed secrets.
+type AuthProfile struct {
+	ID           uint       `gorm:"primaryKey;column:id" json:"id"`                                // Unique identifier for the profile
+	Name         string     `gorm:"column:name;type:varchar(64);uniqueIndex" json:"name"`          // Unique name of the profile
+	Type         string     `gorm:"column:type;type:varchar(32)" json:"type"`                      // Authentication type (api_key, basic, bearer, oauth2_client_credentials)
+	Description  string     `gorm:"column:description;type:text" json:"description"`               // Description of the profile
+	SchemeName   string     `gorm:"column:scheme_name;type:varchar(64)" json:"scheme_name"`        // Name of the securityScheme the profile was created from
+	In           string     `gorm:"column:param_in;type:varchar(16)" json:"in"`                    // Location of the API key (header, query, cookie)
+	ParamName    string     `gorm:"column:param_name;type:varchar(128)" json:"param_name"`         // Header, query parameter or cookie name of the API key
+	Value        string     `gorm:"column:value;type:text" json:"value,omitempty"`                 // API key value
+	Username     string     `gorm:"column:username;type:varchar(255)" json:"username"`             // HTTP basic user name
+	Password     string     `gorm:"column:password;type:text" json:"password,omitempty"`           // HTTP basic password
+	Token        string     `gorm:"column:token;type:text" json:"token,omitempty"`                 // HTTP bearer token
+	TokenURL     string     `gorm:"column:token_url;type:varchar(1024)" json:"token_url"`          // OAuth2 token endpoint
+	ClientID     string     `gorm:"column:client_id;type:varchar(255)" json:"client_id"`           // OAuth2 client ID
+	ClientSecret string     `gorm:"column:client_secret;type:text" json:"client_secret,omitempty"` // OAuth2 client secret
+	Scopes       StringList `gorm:"column:scopes;type:json" json:"scopes"`                         // OAuth2 scopes requested with the token
+	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`            // Timestamp when the profile was created
+	UpdatedAt    time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`            // Timestamp when the profile was last updated
+}
+
+// authProfileFields has the fields of AuthProfile without its MarshalJSON method.
+type authProfileFields AuthProfile
+
+// MarshalJSON encodes the profile without its credentials.
+func (p AuthProfile) MarshalJSON() ([]byte, error) {
+	masked := authProfileFields(p)
+	masked.Value, masked.Password, masked.Token, masked.ClientSecret = "", "", "", ""
+	return json.Marshal(masked)
+}
+
+// SecurityScheme describes a securityScheme declared by a swagger document
+// together with the AuthProfile settings it maps to.
+type SecurityScheme struct {
+	Name        string   `json:"name"`                         // Key of the scheme in components.securitySchemes or securityDefinitions
+	SchemeType  string   `json:"scheme_type"`                  // Type declared by the document (apiKey, http, oauth2, openIdConnect)
+	Description string   `json:"description"`                  // Description declared by the document
+	Type        string   `json:"type,omitempty"`               // AuthProfile type the scheme maps to, empty when unsupported
+	In          string   `json:"in,omitempty"`                 // Location of the API key
+	ParamName   string   `json:"param_name,omitempty"`         // Header, query parameter or cookie name of the API key
+	TokenURL    string   `json:"token_url,omitempty"`          // OAuth2 token endpoint of the client credentials flow
+	Scopes      []string `json:"scopes,omitempty"`             // OAuth2 scopes declared by the client credentials flow
+	Unsupported string   `json:"unsupported_reason,omitempty"` // Why no AuthProfile can be created from the scheme
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthProfile_MarshalJSON(t *testing.T) {
+	profile := AuthProfile{
+		ID: 1, Name: "petstore", Type: AuthTypeOAuth2ClientCredentials,
+		Value: "key", Username: "admin", Password: "pass", Token: "token",
+		TokenURL: "https://auth.test/token", ClientID: "id", ClientSecret: "secret",
+	}
+
+	// 值与指针均不输出凭证
+	for _, v := range []interface{}{profile, &profile, []AuthProfile{profile}} {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		for _, credential := range []string{"key", "pass", "token", "secret"} {
+			assert.NotContains(t, string(b), `"`+credential+`"`)
+		}
+		assert.Contains(t, string(b), `"username":"admin"`)
+		assert.Contains(t, string(b), `"client_id":"id"`)
+	}
+	// 原对象不受影响
+	assert.Equal(t, "secret", profile.ClientSecret)
+
+	// 请求中的凭证照常写入
+	var decoded AuthProfile
+	require.NoError(t, json.Unmarshal([]byte(`{"name": "petstore", "token": "t"}`), &decoded))
+	assert.Equal(t, "t", decoded.Token)
+}
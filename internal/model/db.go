@@ -38,10 +38,24 @@ func DBConfigFromMap(m map[string]interface{}) db.Config {
 	if v, ok := m["type"].(string); ok {
 		cfg.Type = v
 	}
+	if v, ok := m["path"].(string); ok {
+		cfg.Path = v
+	}
+	if v, ok := m["ssl_mode"].(string); ok {
+		cfg.SSLMode = v
+	}
+	if v, ok := m["search_path"].(string); ok {
+		cfg.SearchPath = v
+	}
+	if v, ok := m["busy_timeout"].(int); ok {
+		cfg.BusyTimeout = v
+	} else if v, ok := m["busy_timeout"].(float64); ok {
+		cfg.BusyTimeout = int(v)
+	}
 	return cfg
 }
 
-// InitDBs 初始化所有数据库连接
+// InitDBs 初始化所有数据库连接，表结构由 internal/migrate 中的迁移创建
 func InitDBs() error {
 	all := config.DBConfigs()
 	for name, m := range all {
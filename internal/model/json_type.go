@@ -0,0 +1,65 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// jsonDBDataType returns the column type of JSON values for the dialect of db.
+// PostgreSQL uses jsonb so the documents can be indexed and queried with its JSON operators.
+func jsonDBDataType(db *gorm.DB) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return "jsonb"
+	default:
+		return "json"
+	}
+}
+
+// GormDBDataType implements migrator.GormDataTypeInterface.
+func (APIParameters) GormDBDataType(db *gorm.DB, _ *schema.Field) string { return jsonDBDataType(db) }
+
+// GormDBDataType implements migrator.GormDataTypeInterface.
+func (StringMap) GormDBDataType(db *gorm.DB, _ *schema.Field) string { return jsonDBDataType(db) }
+
+// GormDBDataType implements migrator.GormDataTypeInterface.
+func (StringList) GormDBDataType(db *gorm.DB, _ *schema.Field) string { return jsonDBDataType(db) }
+
+// GormDBDataType implements migrator.GormDataTypeInterface.
+func (JSON) GormDBDataType(db *gorm.DB, _ *schema.Field) string { return jsonDBDataType(db) }
+
+// GormDBDataType implements migrator.GormDataTypeInterface.
+func (TransportConfig) GormDBDataType(db *gorm.DB, _ *schema.Field) string { return jsonDBDataType(db) }
+
+// JSONText is a JSON document kept as a string in Go and in API payloads, stored in a JSON column.
+type JSONText string
+
+// GormDBDataType implements migrator.GormDataTypeInterface.
+func (JSONText) GormDBDataType(db *gorm.DB, _ *schema.Field) string { return jsonDBDataType(db) }
+
+// Value converts JSONText to a database-compatible format. An empty document is stored as NULL,
+// since an empty string is not valid JSON.
+func (t JSONText) Value() (driver.Value, error) {
+	if t == "" {
+		return nil, nil
+	}
+	return string(t), nil
+}
+
+// Scan converts a database value back to JSONText.
+func (t *JSONText) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		*t = JSONText(v)
+	case []byte:
+		*t = JSONText(v)
+	case nil:
+		*t = ""
+	default:
+		return fmt.Errorf("unsupported type: %T", value)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
+)
+
+func TestJSONColumnTypes(t *testing.T) {
+	pg := &gorm.DB{Config: &gorm.Config{Dialector: postgres.New(postgres.Config{})}}
+	my := &gorm.DB{Config: &gorm.Config{Dialector: mysql.New(mysql.Config{})}}
+
+	// APIEndpoint 的 JSON 列在 PostgreSQL 中为 jsonb
+	for _, v := range []migrator.GormDataTypeInterface{APIParameters{}, StringMap{}, JSONText(""), JSON{}, StringList{}, TransportConfig{}} {
+		assert.Equal(t, "jsonb", v.GormDBDataType(pg, nil), "%T", v)
+		assert.Equal(t, "json", v.GormDBDataType(my, nil), "%T", v)
+	}
+}
+
+func TestJSONText(t *testing.T) {
+	v, err := JSONText("").Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = JSONText(`{"200":{}}`).Value()
+	require.NoError(t, err)
+	assert.Equal(t, `{"200":{}}`, v)
+
+	var text JSONText
+	require.NoError(t, text.Scan([]byte(`{"200":{}}`)))
+	assert.Equal(t, JSONText(`{"200":{}}`), text)
+	require.NoError(t, text.Scan(nil))
+	assert.Empty(t, text)
+}
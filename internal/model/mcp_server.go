@@ -0,0 +1,77 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MCPServer represents a named MCP server composed from selected API endpoints.
+// Each server is reachable at its own MCP endpoint path derived from Name.
+type MCPServer struct {
+	ID            uint            `gorm:"primaryKey;column:id" json:"id"`                               // Unique identifier for the server
+	Name          string          `gorm:"column:name;type:varchar(64);uniqueIndex" json:"name"`         // Unique name, used in the MCP endpoint path
+	Description   string          `gorm:"column:description;type:text" json:"description"`              // Description returned to MCP clients as instructions
+	BaseURL       string          `gorm:"column:base_url;type:varchar(255)" json:"base_url"`            // Upstream base URL, defaults to the first server of each endpoint's document
+	Headers       StringMap       `gorm:"column:headers;type:json" json:"headers"`                      // Default headers sent with every tool call
+	AuthProfileID *uint           `gorm:"column:auth_profile_id;index" json:"auth_profile_id"`          // AuthProfile applied to every tool call, overriding the document profiles
+	Transport     TransportConfig `gorm:"column:transport;type:json" json:"transport"`                  // Transport settings
+	Tools         []MCPServerTool `gorm:"foreignKey:ServerID;constraint:OnDelete:CASCADE" json:"tools"` // Endpoints exposed as tools
+	CreatedAt     time.Time       `gorm:"column:created_at;autoCreateTime" json:"created_at"`           // Timestamp when the server was created
+	UpdatedAt     time.Time       `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`           // Timestamp when the server was last updated
+}
+
+// MCPServerTool binds an APIEndpoint to an MCPServer with per-tool overrides.
+type MCPServerTool struct {
+	ID           uint           `gorm:"primaryKey;column:id" json:"id"`                      // Unique identifier for the binding
+	ServerID     uint           `gorm:"column:server_id;index" json:"server_id"`             // Owning MCPServer
+	EndpointID   uint           `gorm:"column:endpoint_id;index" json:"endpoint_id"`         // Exposed APIEndpoint
+	ToolName     string         `gorm:"column:tool_name;type:varchar(64)" json:"tool_name"`  // Tool name override, derived from the endpoint when empty
+	Description  string         `gorm:"column:description;type:text" json:"description"`     // Tool description override
+	HiddenParams StringList     `gorm:"column:hidden_params;type:json" json:"hidden_params"` // Parameters removed from the tool input schema
+	FixedValues  StringMap      `gorm:"column:fixed_values;type:json" json:"fixed_values"`   // Parameter values always sent, hidden from the tool input schema
+	CreatedAt    time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`  // Timestamp when the binding was created
+	UpdatedAt    time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`  // Timestamp when the binding was last updated
+	DeletedAt    gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at"`           // Timestamp when the binding was moved to the trash
+}
+
+// TransportConfig holds the MCP transport settings of an MCPServer.
+type TransportConfig struct {
+	StreamableHTTP bool `json:"streamable_http"` // Whether the Streamable HTTP endpoint is enabled
+	SSE            bool `json:"sse"`             // Whether the legacy HTTP+SSE endpoints are enabled
+	SSEResponses   bool `json:"sse_responses"`   // Whether Streamable HTTP requests are answered with an event stream when accepted
+}
+
+// DefaultTransportConfig enables every transport.
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{StreamableHTTP: true, SSE: true}
+}
+
+// Value converts TransportConfig to a database-compatible format.
+func (t TransportConfig) Value() (driver.Value, error) {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan converts a database value back to TransportConfig.
+func (t *TransportConfig) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case string:
+		bytes = []byte(v)
+	case []byte:
+		bytes = v
+	case nil:
+		*t = TransportConfig{}
+		return nil
+	default:
+		return fmt.Errorf("unsupported type: %T", value)
+	}
+	return json.Unmarshal(bytes, t)
+}
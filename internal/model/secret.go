@@ -0,0 +1,55 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SecretRefPattern matches a secret reference such as {{secret:PETSTORE_KEY}}.
+// References may appear in endpoint parameter values, headers and bodies, MCP server headers and fixed values,
+// and AuthProfile credentials; they are replaced with the decrypted value only when the request is sent.
+var SecretRefPattern = regexp.MustCompile(`\{\{\s*secret:([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// SecretNamePattern restricts the names of secrets to the characters allowed in references.
+var SecretNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// ManagedSecretPrefix starts the names of the secrets created automatically for credentials saved elsewhere,
+// such as AuthProfile credentials and SwaggerDocument source auth headers.
+// They are deleted together with the record referencing them; user secrets cannot use the prefix.
+const ManagedSecretPrefix = "auto."
+
+// Secret is a credential stored encrypted at rest with envelope encryption.
+// The value is never returned by the API.
+type Secret struct {
+	ID          uint      `gorm:"primaryKey;column:id" json:"id"`                       // Unique identifier for the secret
+	Name        string    `gorm:"column:name;type:varchar(64);uniqueIndex" json:"name"` // Unique name used in references
+	Description string    `gorm:"column:description;type:text" json:"description"`      // Description of the secret
+	KeyID       string    `gorm:"column:key_id;type:varchar(64)" json:"key_id"`         // ID of the master key that wrapped the data key
+	WrappedKey  []byte    `gorm:"column:wrapped_key" json:"-"`                          // Data key encrypted with the master key
+	Ciphertext  []byte    `gorm:"column:ciphertext" json:"-"`                           // Value encrypted with the data key
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`   // Timestamp when the secret was created
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`   // Timestamp when the value was last changed
+}
+
+// SecretRefs returns the names of the secrets referenced by s, in order of appearance.
+func SecretRefs(s string) []string {
+	var names []string
+	for _, m := range SecretRefPattern.FindAllStringSubmatch(s, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// ManagedSecretRefs returns the names of the managed secrets referenced by values.
+func ManagedSecretRefs(values ...string) []string {
+	var names []string
+	for _, value := range values {
+		for _, name := range SecretRefs(value) {
+			if strings.HasPrefix(name, ManagedSecretPrefix) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Spec formats supported by SwaggerDocument.SpecFormat.
+const (
+	SpecFormatSwagger2 = "swagger2" // Swagger 2.0
+	SpecFormatOpenAPI3 = "openapi3" // OpenAPI 3.0.x
+)
+
+// SwaggerDocument represents an imported Swagger/OpenAPI specification.
+// Every APIEndpoint extracted from the document references it through APIEndpoint.SwaggerID.
+type SwaggerDocument struct {
+	ID         uint           `gorm:"primaryKey;column:id" json:"id"`                         // Unique identifier for the document
+	Title      string         `gorm:"column:title;type:varchar(255)" json:"title"`            // info.title of the specification
+	Version    string         `gorm:"column:version;type:varchar(64)" json:"version"`         // info.version of the specification
+	SpecFormat string         `gorm:"column:spec_format;type:varchar(16)" json:"spec_format"` // Specification format (swagger2, openapi3)
+	Content    string         `gorm:"column:content;size:16777216" json:"content,omitempty"`  // Raw specification content
+	Servers    StringList     `gorm:"column:servers;type:json" json:"servers"`                // Server URLs declared by the specification
+	Checksum   string         `gorm:"column:checksum;type:varchar(64)" json:"checksum"`       // SHA-256 checksum of the raw content
+	CreatedBy  string         `gorm:"column:created_by;type:varchar(64)" json:"created_by"`   // User who imported the document
+	CreatedAt  time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`     // Timestamp when the document was imported
+	UpdatedAt  time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`     // Timestamp when the document was last updated
+	DeletedAt  gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at"`              // Timestamp when the document was moved to the trash
+
+	AuthProfileID *uint `gorm:"column:auth_profile_id;index" json:"auth_profile_id"` // AuthProfile applied to the endpoints of the document
+
+	// Documents imported from a URL are re-fetched periodically and re-imported when their checksum changes.
+	SourceURL        string     `gorm:"column:source_url;type:varchar(1024)" json:"source_url"`  // URL the document was imported from
+	SourceAuthHeader string     `gorm:"column:source_auth_header;type:varchar(1024)" json:"-"`   // Secret reference of the Authorization header sent when fetching the source URL
+	LastSyncedAt     *time.Time `gorm:"column:last_synced_at" json:"last_synced_at,omitempty"`   // Timestamp of the last fetch of the source URL
+	SyncError        string     `gorm:"column:sync_error;type:text" json:"sync_error,omitempty"` // Error of the last fetch, empty when it succeeded
+}
+
+// StringList is a slice of strings stored as a JSON array.
+type StringList []string
+
+// Value converts StringList to a database-compatible format.
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		l = StringList{}
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan converts a database value back to StringList.
+func (l *StringList) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case string:
+		bytes = []byte(v)
+	case []byte:
+		bytes = v
+	case nil:
+		*l = nil
+		return nil
+	default:
+		return fmt.Errorf("unsupported type: %T", value)
+	}
+	return json.Unmarshal(bytes, l)
+}
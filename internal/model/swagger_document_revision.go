@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Sources of a SwaggerDocumentRevision.
+const (
+	RevisionSourceImport   = "import"   // Content posted by a user
+	RevisionSourceURL      = "url"      // Content fetched from the source URL on import
+	RevisionSourceSync     = "sync"     // Content fetched from the source URL by the sync scheduler
+	RevisionSourceRollback = "rollback" // Content restored from an earlier revision
+)
+
+// SwaggerDocumentRevision is an imported revision of a SwaggerDocument.
+// A revision is recorded every time the content of the document changes, so any earlier endpoint set can be restored.
+type SwaggerDocumentRevision struct {
+	ID             uint      `gorm:"primaryKey;column:id" json:"id"`                                       // Unique identifier for the revision
+	SwaggerID      uint      `gorm:"column:swagger_id;uniqueIndex:idx_swagger_revision" json:"swagger_id"` // Document the revision belongs to
+	Revision       int       `gorm:"column:revision;uniqueIndex:idx_swagger_revision" json:"revision"`     // Revision number, starting at 1 for each document
+	Title          string    `gorm:"column:title;type:varchar(255)" json:"title"`                          // info.title of the revision
+	Version        string    `gorm:"column:version;type:varchar(64)" json:"version"`                       // info.version of the revision
+	SpecFormat     string    `gorm:"column:spec_format;type:varchar(16)" json:"spec_format"`               // Specification format (swagger2, openapi3)
+	Content        string    `gorm:"column:content;size:16777216" json:"content,omitempty"`                // Raw specification content
+	Checksum       string    `gorm:"column:checksum;type:varchar(64)" json:"checksum"`                     // SHA-256 checksum of the raw content
+	Source         string    `gorm:"column:source;type:varchar(16)" json:"source"`                         // How the revision was imported (import, url, sync, rollback)
+	RolledBackFrom int       `gorm:"column:rolled_back_from" json:"rolled_back_from,omitempty"`            // Revision restored by a rollback
+	CreatedBy      string    `gorm:"column:created_by;type:varchar(64)" json:"created_by"`                 // User who imported the revision
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`                   // Timestamp when the revision was imported
+}
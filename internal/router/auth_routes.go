@@ -0,0 +1,23 @@
+package router
+
+import (
+	"mcp-manager/internal/controller"
+	"mcp-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAuthRoutes 注册鉴权配置相关路由
+func RegisterAuthRoutes(r *gin.Engine) {
+	handler := controller.NewAuthProfileHandler(service.NewAuthProfileService(nil, nil, nil))
+
+	r.GET("/api/auth/profiles", handler.ListProfiles)         // 查询所有鉴权配置
+	r.POST("/api/auth/profiles", handler.CreateProfile)       // 创建鉴权配置
+	r.GET("/api/auth/profiles/:id", handler.GetProfileByID)   // 查询单个鉴权配置
+	r.PUT("/api/auth/profiles/:id", handler.UpdateProfile)    // 更新鉴权配置
+	r.DELETE("/api/auth/profiles/:id", handler.DeleteProfile) // 删除鉴权配置并解除引用
+
+	r.GET("/api/swagger/documents/:id/security-schemes", handler.ListSecuritySchemes) // 查询文档声明的 securitySchemes
+	r.POST("/api/swagger/documents/:id/auth-profiles", handler.CreateFromScheme)      // 按声明创建鉴权配置
+	r.PUT("/api/swagger/documents/:id/auth-profile", handler.BindDocument)            // 绑定或解除文档的鉴权配置
+}
@@ -0,0 +1,51 @@
+package router
+
+import (
+	"mcp-manager/internal/controller"
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/mcp"
+	"mcp-manager/internal/service"
+	"mcp-manager/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterMCPRoutes 注册 MCP Server 管理接口及传输层路由
+// 每种接入方式均同时提供 Streamable HTTP 与旧版 HTTP+SSE 两种传输
+// 返回清理空闲会话的会话管理器，由调用方启动
+func RegisterMCPRoutes(r *gin.Engine) BackgroundTask {
+	serverDAO := dao.NewMCPServerDAO(nil)
+	endpointDAO := dao.NewAPIEndpointDAO(nil)
+	documentDAO := dao.NewSwaggerDocumentDAO(nil)
+	profileDAO := dao.NewAuthProfileDAO(nil)
+
+	// MCP Server 实例管理
+	handler := controller.NewMCPServerHandler(service.NewMCPServerService(serverDAO, endpointDAO, profileDAO))
+	r.GET("/api/mcp/servers", handler.ListServers)         // 查询所有 server
+	r.POST("/api/mcp/servers", handler.CreateServer)       // 创建 server
+	r.GET("/api/mcp/servers/:id", handler.GetServerByID)   // 查询单个 server 详情
+	r.PUT("/api/mcp/servers/:id", handler.UpdateServer)    // 更新 server 及其工具绑定
+	r.DELETE("/api/mcp/servers/:id", handler.DeleteServer) // 删除 server
+
+	sessions := mcp.NewSessionManager(config.MCPSessionIdleTimeout())
+	factory := mcp.NewServerFactory(serverDAO, endpointDAO, documentDAO, profileDAO, dao.NewSecretDAO(nil))
+	sseResponses := mcp.WithSSEResponses(config.MCPSSEResponses())
+
+	// 默认 server：暴露所有已导入文档的接口
+	transport := mcp.NewHTTPTransport(sessions, mcp.StaticServer(factory.DocumentServer(0)), sseResponses)
+	r.POST("/mcp", transport.HandlePost)
+	r.GET("/mcp", transport.HandleGet)
+	r.DELETE("/mcp", transport.HandleDelete)
+	r.GET("/sse", transport.HandleSSE)
+	r.POST("/messages", transport.HandleMessages)
+
+	// 按名称接入的 server，传输方式由 server 的 transport 配置决定
+	streamable := mcp.NewHTTPTransport(sessions, factory.Resolver(mcp.TransportStreamableHTTP), sseResponses)
+	r.POST("/mcp/servers/:name", streamable.HandlePost)
+	r.GET("/mcp/servers/:name", streamable.HandleGet)
+	r.DELETE("/mcp/servers/:name", streamable.HandleDelete)
+	legacy := mcp.NewHTTPTransport(sessions, factory.Resolver(mcp.TransportSSE), sseResponses)
+	r.GET("/mcp/servers/:name/sse", legacy.HandleSSE)
+	r.POST("/mcp/servers/:name/messages", legacy.HandleMessages)
+	return sessions
+}
@@ -1,12 +1,21 @@
 package router
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"mcp-manager/internal/middleware"
 )
 
-// RegisterRoutes 注册所有接口路由
-func RegisterRoutes(r *gin.Engine) {
+// BackgroundTask 为随 HTTP 服务运行的后台任务，Start 不阻塞并在 ctx 被取消后退出
+type BackgroundTask interface {
+	Start(ctx context.Context)
+}
+
+// RegisterRoutes 注册所有接口路由，返回路由依赖的后台任务，由调用方以可取消的 context 启动
+func RegisterRoutes(r *gin.Engine) []BackgroundTask {
+	var tasks []BackgroundTask
+
 	// 注册跨域中间件
 	r.Use(middleware.CORSMiddleware())
 
@@ -14,5 +23,18 @@ func RegisterRoutes(r *gin.Engine) {
 	RegisterUtilityRoutes(r)
 
 	// 注册Swagger相关路由
-	RegisterSwaggerHandlers(r)
+	tasks = append(tasks, RegisterSwaggerHandlers(r))
+
+	// 注册回收站相关路由
+	tasks = append(tasks, RegisterTrashRoutes(r)...)
+
+	// 注册鉴权配置相关路由
+	RegisterAuthRoutes(r)
+
+	// 注册secret相关路由
+	RegisterSecretRoutes(r)
+
+	// 注册MCP协议相关路由
+	tasks = append(tasks, RegisterMCPRoutes(r))
+	return tasks
 }
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"mcp-manager/internal/controller"
+	"mcp-manager/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterSecretRoutes 注册 secret 管理相关路由
+func RegisterSecretRoutes(r *gin.Engine) {
+	handler := controller.NewSecretHandler(service.NewSecretService(nil, service.DefaultKeyring()))
+
+	r.GET("/api/secrets", handler.ListSecrets)         // 查询所有 secret 的元信息
+	r.POST("/api/secrets", handler.CreateSecret)       // 创建 secret
+	r.POST("/api/secrets/rotate", handler.RotateKeys)  // 以当前主密钥重新加密所有数据密钥
+	r.GET("/api/secrets/:id", handler.GetSecretByID)   // 查询单个 secret 的元信息
+	r.PUT("/api/secrets/:id", handler.UpdateSecret)    // 更新 secret 的描述或取值
+	r.DELETE("/api/secrets/:id", handler.DeleteSecret) // 删除 secret
+}
@@ -3,25 +3,46 @@ package router
 import (
 	"mcp-manager/internal/controller"
 	"mcp-manager/internal/service"
+	"mcp-manager/pkg/config"
 
 	"github.com/gin-gonic/gin"
 )
 
 // RegisterSwaggerHandlers 注册所有 Swagger 相关的 HTTP 路由
-// 包括接口解析、管理、测试、校验等能力
-// 依赖 service.GetSwaggerService() 注入业务实现
-func RegisterSwaggerHandlers(r *gin.Engine) {
-	handler := controller.NewSwaggerServiceHandler(service.NewSwaggerService())
+// 包括接口解析、管理、测试、校验等能力，业务实现由 service.NewSwaggerService() 创建
+// 返回定时同步 URL 来源文档的调度器，由调用方启动
+func RegisterSwaggerHandlers(r *gin.Engine) BackgroundTask {
+	swaggerService := service.NewSwaggerService()
+	handler := controller.NewSwaggerServiceHandler(swaggerService)
 
 	// 业务接口相关
 	r.POST("/api/swagger/parse", handler.ParseAndSave)               // 解析并保存 swagger 接口
-	r.GET("/api/swagger/endpoints", handler.ListAPIEndpoints)        // 查询指定 swaggerID 下所有接口
+	r.GET("/api/swagger/endpoints", handler.ListAPIEndpoints)        // 分页查询接口，支持过滤、搜索与排序
+	r.POST("/api/swagger/endpoints/bulk", handler.BulkEndpoints)     // 批量操作接口
 	r.GET("/api/swagger/endpoint/:id", handler.GetAPIEndpointByID)   // 查询单个接口详情
 	r.DELETE("/api/swagger/endpoint/:id", handler.DeleteAPIEndpoint) // 删除接口
 	r.PUT("/api/swagger/endpoint", handler.UpdateAPIEndpoint)        // 更新接口
+	r.PATCH("/api/swagger/endpoint/:id", handler.PatchAPIEndpoint)   // 以 JSON Merge Patch 部分更新接口
 	r.POST("/api/swagger/endpoint/test", handler.TestAPIEndpoint)    // 测试接口
 
+	// swagger 文档管理相关
+	r.GET("/api/swagger/documents", handler.ListDocuments)                       // 查询所有已导入的文档
+	r.POST("/api/swagger/documents", handler.CreateDocument)                     // 导入文档
+	r.GET("/api/swagger/documents/:id", handler.GetDocumentByID)                 // 查询单个文档详情
+	r.PUT("/api/swagger/documents/:id", handler.UpdateDocument)                  // 更新文档元信息
+	r.DELETE("/api/swagger/documents/:id", handler.DeleteDocument)               // 删除文档及其接口
+	r.POST("/api/swagger/documents/import-url", handler.ImportDocumentByURL)     // 通过 URL 导入文档
+	r.POST("/api/swagger/documents/:id/sync", handler.SyncDocument)              // 立即同步 URL 来源的文档
+	r.POST("/api/swagger/documents/:id/reimport", handler.ReimportDocument)      // 按差异重新导入文档
+	r.GET("/api/swagger/documents/:id/revisions", handler.ListRevisions)         // 查询文档的修订历史
+	r.GET("/api/swagger/documents/:id/revisions/:revision", handler.GetRevision) // 查询指定修订的内容
+	r.GET("/api/swagger/documents/:id/diff", handler.DiffRevisions)              // 比较两个修订的结构差异
+	r.POST("/api/swagger/documents/:id/rollback", handler.RollbackDocument)      // 回滚到指定修订
+
 	// swagger 校验相关
 	r.POST("/api/swagger/validate/file", controller.ValidateSwaggerByFile) // 文件上传校验
 	r.POST("/api/swagger/validate/text", controller.ValidateSwaggerByText) // 文本内容校验
+
+	// 定时同步通过 URL 导入的文档
+	return service.NewSyncScheduler(swaggerService, config.SwaggerSyncInterval())
 }
@@ -0,0 +1,33 @@
+package router
+
+import (
+	"mcp-manager/internal/controller"
+	"mcp-manager/internal/service"
+	"mcp-manager/pkg/config"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// trashPurgeInterval 为检查回收站中过期记录的间隔
+const trashPurgeInterval = time.Hour
+
+// RegisterTrashRoutes 注册回收站相关路由
+// 返回定时彻底删除超过保留时长的文档与接口的调度器，由调用方启动
+func RegisterTrashRoutes(r *gin.Engine) []BackgroundTask {
+	retention := config.TrashRetention()
+	trashService := service.NewTrashService(nil, retention)
+	handler := controller.NewTrashHandler(trashService)
+
+	r.GET("/api/swagger/trash", handler.ListTrash)                              // 查询回收站中的文档与接口
+	r.POST("/api/swagger/trash/documents/:id/restore", handler.RestoreDocument) // 恢复文档及其接口
+	r.POST("/api/swagger/trash/endpoints/:id/restore", handler.RestoreEndpoint) // 恢复单独删除的接口
+	r.DELETE("/api/swagger/trash/documents/:id", handler.PurgeDocument)         // 彻底删除文档
+	r.DELETE("/api/swagger/trash/endpoints/:id", handler.PurgeEndpoint)         // 彻底删除接口
+
+	// 保留时长为 0 时不自动清理
+	if retention <= 0 {
+		return nil
+	}
+	return []BackgroundTask{service.NewPurgeScheduler(trashService, trashPurgeInterval)}
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mcp-manager/internal/model"
+	http "mcp-manager/internal/utils/http"
+	"mcp-manager/pkg/logger"
+	"net/url"
+	"strings"
+)
+
+// APIRequest 描述根据 APIEndpoint 组装出的一次上游 HTTP 请求
+type APIRequest struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    string
+}
+
+// BuildAPIRequest 根据接口定义及其参数取值组装上游请求
+// path 参数替换到路径中，query 参数拼接到 URL，header 参数与 endpoint.Headers 合并，
+// POST/PUT/PATCH 请求优先使用 in=body 的参数值，否则使用 endpoint.Body
+func BuildAPIRequest(endpoint *model.APIEndpoint, baseURL string) (*APIRequest, error) {
+	// 1. 处理 path 参数
+	accURL := strings.TrimSuffix(baseURL, "/") + endpoint.Path
+	for _, param := range endpoint.Parameters {
+		if param.In == "path" {
+			if param.Value == "" && param.Required {
+				return nil, fmt.Errorf("missing required path parameter: %s", param.Name)
+			}
+			accURL = strings.ReplaceAll(accURL, "{"+param.Name+"}", url.PathEscape(param.Value))
+		}
+	}
+
+	// 2. 处理 query 参数
+	query := url.Values{}
+	for _, param := range endpoint.Parameters {
+		if param.In == "query" && param.Value != "" {
+			query.Add(param.Name, param.Value)
+		}
+	}
+	if len(query) > 0 {
+		accURL += "?" + query.Encode()
+	}
+	// 路径不能改变请求的目标，否则请求中的 secret 与鉴权信息会被发送到其他主机
+	if err := checkRequestTarget(accURL, baseURL); err != nil {
+		return nil, fmt.Errorf("invalid path %s: %w", endpoint.Path, err)
+	}
+
+	// 3. 处理 header
+	headers := make(map[string]string)
+	for _, param := range endpoint.Parameters {
+		if param.In == "header" && param.Value != "" {
+			headers[param.Name] = param.Value
+		}
+	}
+	for k, v := range endpoint.Headers {
+		headers[k] = v
+	}
+
+	// 4. 处理 body（支持 application/json）
+	var bodyStr string
+	method := strings.ToUpper(endpoint.Method)
+	if method == "POST" || method == "PUT" || method == "PATCH" {
+		for _, param := range endpoint.Parameters {
+			if param.In == "body" {
+				// 未传入 body 参数时使用接口上保存的 Body
+				if param.Value == "" && param.Required && endpoint.Body == "" {
+					return nil, fmt.Errorf("missing required body parameter: %s", param.Name)
+				}
+				bodyStr = param.Value
+				break
+			}
+		}
+		if bodyStr == "" && endpoint.Body != "" {
+			bodyStr = endpoint.Body
+		}
+		if bodyStr != "" {
+			if _, ok := headers["Content-Type"]; !ok {
+				headers["Content-Type"] = "application/json"
+			}
+		}
+	}
+
+	return &APIRequest{
+		Method:  method,
+		URL:     accURL,
+		Headers: headers,
+		Body:    bodyStr,
+	}, nil
+}
+
+// checkRequestTarget 检查组装出的 rawURL 与 baseURL 的协议与主机是否一致
+func checkRequestTarget(rawURL, baseURL string) error {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return err
+	}
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if target.Scheme != base.Scheme || target.Host != base.Host || target.User.String() != base.User.String() {
+		return fmt.Errorf("request url does not point to the host of base url %s", baseURL)
+	}
+	return nil
+}
+
+// APIExecutor 负责执行 APIEndpoint 对应的上游请求
+// 接口测试与 MCP 工具调用共用同一套请求组装与发送逻辑
+type APIExecutor interface {
+	// Execute 组装并发送请求，auth 不为 nil 时按其加入鉴权信息，返回结构化的响应，非 2xx 状态码不视为错误
+	Execute(ctx context.Context, endpoint *model.APIEndpoint, baseURL string, auth *model.AuthProfile) (*http.Response, error)
+}
+
+// apiExecutor 实现 APIExecutor 接口
+type apiExecutor struct {
+	httpClient    http.HTTPClient
+	authenticator *Authenticator
+	secrets       SecretResolver
+}
+
+// NewAPIExecutor 创建一个新的 APIExecutor 实例，OAuth2 token 同样通过 httpClient 获取
+// 请求中的 {{secret:NAME}} 引用在发送前通过 secrets 解密替换，secrets 为 nil 时引用了 secret 的请求返回 ErrSecretsNotConfigured
+func NewAPIExecutor(httpClient http.HTTPClient, secrets SecretResolver) APIExecutor {
+	return &apiExecutor{httpClient: httpClient, authenticator: NewAuthenticator(httpClient), secrets: secrets}
+}
+
+// Execute 返回的响应与错误中出现的 secret 取值均已脱敏
+func (e *apiExecutor) Execute(ctx context.Context, endpoint *model.APIEndpoint, baseURL string, auth *model.AuthProfile) (*http.Response, error) {
+	endpoint, auth, redactor, err := e.resolveSecrets(ctx, endpoint, auth)
+	if err != nil {
+		return nil, err
+	}
+	req, err := BuildAPIRequest(endpoint, baseURL)
+	if err != nil {
+		return nil, redactError(redactor, err)
+	}
+	resp, err := e.send(ctx, req, auth)
+	if err == nil && resp.StatusCode == 401 && auth != nil && auth.Type == model.AuthTypeOAuth2ClientCredentials {
+		// token 可能在有效期内被撤销，重新获取后重试一次
+		e.authenticator.Invalidate(auth)
+		resp, err = e.send(ctx, req, auth)
+	}
+	if err != nil {
+		return nil, redactError(redactor, err)
+	}
+	return redactResponse(redactor, resp), nil
+}
+
+// send 在 req 的副本上加入鉴权信息并发送
+func (e *apiExecutor) send(ctx context.Context, req *APIRequest, auth *model.AuthProfile) (*http.Response, error) {
+	signed := *req
+	signed.Headers = make(map[string]string, len(req.Headers))
+	for k, v := range req.Headers {
+		signed.Headers[k] = v
+	}
+	if auth != nil {
+		if err := e.authenticator.Apply(ctx, auth, &signed); err != nil {
+			return nil, err
+		}
+	}
+	var bodyReader io.Reader
+	if signed.Body != "" {
+		bodyReader = strings.NewReader(signed.Body)
+	}
+	return e.httpClient.DoRequest(ctx, signed.Method, signed.URL, signed.Headers, bodyReader)
+}
+
+// resolveSecrets 返回替换了 secret 引用的 endpoint 与 auth 副本，以及对解密出的取值脱敏的 Redactor
+// 没有引用时原样返回，Redactor 为 nil
+func (e *apiExecutor) resolveSecrets(ctx context.Context, endpoint *model.APIEndpoint, auth *model.AuthProfile) (*model.APIEndpoint, *model.AuthProfile, *logger.Redactor, error) {
+	var fields []*string
+	resolvedEndpoint := *endpoint
+	resolvedEndpoint.Parameters = append(model.APIParameters(nil), endpoint.Parameters...)
+	for i := range resolvedEndpoint.Parameters {
+		fields = append(fields, &resolvedEndpoint.Parameters[i].Value)
+	}
+	resolvedEndpoint.Headers = make(model.StringMap, len(endpoint.Headers))
+	headerValues := make(map[string]*string, len(endpoint.Headers))
+	for k, v := range endpoint.Headers {
+		value := v
+		headerValues[k] = &value
+		fields = append(fields, &value)
+	}
+	fields = append(fields, &resolvedEndpoint.Body)
+	var resolvedAuth *model.AuthProfile
+	if auth != nil {
+		copied := *auth
+		resolvedAuth = &copied
+		fields = append(fields, &copied.Value, &copied.Username, &copied.Password, &copied.Token, &copied.ClientID, &copied.ClientSecret)
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		for _, name := range model.SecretRefs(*field) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return endpoint, auth, nil, nil
+	}
+	if e.secrets == nil {
+		return nil, nil, nil, ErrSecretsNotConfigured
+	}
+	values, err := e.secrets.Resolve(ctx, names)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	redactor := logger.NewRedactor()
+	for _, v := range values {
+		redactor.Add(v)
+	}
+	for _, field := range fields {
+		*field = model.SecretRefPattern.ReplaceAllStringFunc(*field, func(ref string) string {
+			return values[model.SecretRefPattern.FindStringSubmatch(ref)[1]]
+		})
+	}
+	for k, v := range headerValues {
+		resolvedEndpoint.Headers[k] = *v
+	}
+	return &resolvedEndpoint, resolvedAuth, redactor, nil
+}
+
+// redactResponse 返回脱敏了 secret 取值的响应副本，上游可能在响应中回显请求内容
+func redactResponse(redactor *logger.Redactor, resp *http.Response) *http.Response {
+	if redactor == nil || resp == nil {
+		return resp
+	}
+	redacted := *resp
+	redacted.Status = redactor.Redact(resp.Status)
+	redacted.URL = redactor.Redact(resp.URL)
+	redacted.Body = redactor.Redact(resp.Body)
+	if resp.Headers != nil {
+		redacted.Headers = make(map[string][]string, len(resp.Headers))
+		for k, values := range resp.Headers {
+			for _, v := range values {
+				redacted.Headers[k] = append(redacted.Headers[k], redactor.Redact(v))
+			}
+		}
+	}
+	return &redacted
+}
+
+// redactError 返回脱敏了 secret 取值的错误，错误中不含 secret 时原样返回
+func redactError(redactor *logger.Redactor, err error) error {
+	if redactor == nil {
+		return err
+	}
+	if msg := redactor.Redact(err.Error()); msg != err.Error() {
+		return errors.New(msg)
+	}
+	return err
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/utils/converter"
+	"mcp-manager/internal/utils/parser"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gorm.io/gorm"
+)
+
+// AuthProfileService 定义鉴权配置的业务接口
+type AuthProfileService interface {
+	// CreateProfile 校验后创建鉴权配置，明文凭证保存为 secret，配置中只记录引用
+	CreateProfile(ctx context.Context, profile *model.AuthProfile) error
+	// UpdateProfile 以 profile 整体替换已有的鉴权配置，未提交的凭证沿用已保存的取值，缓存的 OAuth2 token 随之失效
+	UpdateProfile(ctx context.Context, profile *model.AuthProfile) error
+	// DeleteProfile 删除鉴权配置及其自动保存的凭证，并解除文档与 MCP Server 对它的引用
+	DeleteProfile(ctx context.Context, id uint) error
+	// GetProfileByID 根据 ID 查询鉴权配置
+	GetProfileByID(ctx context.Context, id uint) (*model.AuthProfile, error)
+	// ListProfiles 查询所有鉴权配置
+	ListProfiles(ctx context.Context) ([]model.AuthProfile, error)
+	// SecuritySchemes 列出文档中声明的 securitySchemes（Swagger 2.0 为 securityDefinitions）
+	SecuritySchemes(ctx context.Context, swaggerID uint) ([]model.SecurityScheme, error)
+	// CreateFromScheme 按文档中名为 scheme 的声明填充类型、位置、token 地址等信息后创建鉴权配置，
+	// 凭证由 profile 提供；文档尚未绑定鉴权配置时自动绑定
+	CreateFromScheme(ctx context.Context, swaggerID uint, scheme string, profile *model.AuthProfile) error
+	// BindDocument 把鉴权配置绑定到文档，profileID 为 nil 时解除绑定
+	BindDocument(ctx context.Context, swaggerID uint, profileID *uint) error
+}
+
+// authProfileService 实现 AuthProfileService 接口
+type authProfileService struct {
+	dao         dao.AuthProfileDAO
+	documentDAO dao.SwaggerDocumentDAO
+	secrets     *secretStore
+	specParser  parser.SwaggerParserWithExtract[*openapi3.T]
+}
+
+// NewAuthProfileService 创建一个新的 AuthProfileService 实例，凭证以配置的主密钥加密保存
+func NewAuthProfileService(profileDAO dao.AuthProfileDAO, documentDAO dao.SwaggerDocumentDAO, secretDAO dao.SecretDAO) AuthProfileService {
+	if profileDAO == nil {
+		profileDAO = dao.NewAuthProfileDAO(nil)
+	}
+	if documentDAO == nil {
+		documentDAO = dao.NewSwaggerDocumentDAO(nil)
+	}
+	return &authProfileService{
+		dao:         profileDAO,
+		documentDAO: documentDAO,
+		secrets:     newSecretStore(secretDAO, DefaultKeyring()),
+		specParser:  parser.NewSwaggerParser(),
+	}
+}
+
+func (s *authProfileService) CreateProfile(ctx context.Context, profile *model.AuthProfile) error {
+	if err := validateAuthProfile(profile); err != nil {
+		return err
+	}
+	if existing, err := s.dao.GetByName(ctx, profile.Name); err == nil && existing != nil {
+		return fmt.Errorf("auth profile name already exists: %s", profile.Name)
+	}
+	sealed, err := s.sealCredentials(ctx, profile)
+	if err != nil {
+		return err
+	}
+	profile.ID = 0
+	if err := s.dao.Create(ctx, profile); err != nil {
+		s.secrets.releaseValues(ctx, sealed...)
+		return err
+	}
+	return nil
+}
+
+func (s *authProfileService) UpdateProfile(ctx context.Context, profile *model.AuthProfile) error {
+	existing, err := s.dao.GetByID(ctx, profile.ID)
+	if err != nil {
+		return err
+	}
+	// 凭证不会返回给调用方，更新时留空表示保持不变
+	stored := credentials(existing)
+	for i, value := range credentials(profile) {
+		if *value == "" {
+			*value = *stored[i]
+		}
+	}
+	if err := validateAuthProfile(profile); err != nil {
+		return err
+	}
+	if other, err := s.dao.GetByName(ctx, profile.Name); err == nil && other != nil && other.ID != profile.ID {
+		return fmt.Errorf("auth profile name already exists: %s", profile.Name)
+	}
+	sealed, err := s.sealCredentials(ctx, profile)
+	if err != nil {
+		return err
+	}
+	profile.CreatedAt = existing.CreatedAt
+	if err := s.dao.Update(ctx, profile); err != nil {
+		s.secrets.releaseValues(ctx, sealed...)
+		return err
+	}
+	// 删除被替换的凭证
+	current := credentials(profile)
+	for i, previous := range stored {
+		if *previous != *current[i] {
+			s.secrets.releaseValues(ctx, *previous)
+		}
+	}
+	return nil
+}
+
+func (s *authProfileService) DeleteProfile(ctx context.Context, id uint) error {
+	profile, err := s.dao.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.dao.Delete(ctx, id); err != nil {
+		return err
+	}
+	for _, value := range credentials(profile) {
+		s.secrets.releaseValues(ctx, *value)
+	}
+	return nil
+}
+
+// credentials 返回 profile 中只写不读的凭证字段
+func credentials(profile *model.AuthProfile) []*string {
+	return []*string{&profile.Value, &profile.Password, &profile.Token, &profile.ClientSecret}
+}
+
+// sealCredentials 把 profile 中的明文凭证保存为 secret 并替换为引用，已是引用的凭证保持不变
+// 返回新建 secret 的引用，保存配置失败时用于删除
+func (s *authProfileService) sealCredentials(ctx context.Context, profile *model.AuthProfile) ([]string, error) {
+	names := []string{"value", "password", "token", "client_secret"}
+	var sealed []string
+	for i, value := range credentials(profile) {
+		ref, err := s.secrets.sealValue(ctx, "auth_profile."+names[i], fmt.Sprintf("%s of auth profile %s", names[i], profile.Name), *value)
+		if err != nil {
+			s.secrets.releaseValues(ctx, sealed...)
+			return nil, fmt.Errorf("save %s of auth profile: %w", names[i], err)
+		}
+		if ref != *value {
+			sealed = append(sealed, ref)
+			*value = ref
+		}
+	}
+	return sealed, nil
+}
+
+func (s *authProfileService) GetProfileByID(ctx context.Context, id uint) (*model.AuthProfile, error) {
+	return s.dao.GetByID(ctx, id)
+}
+
+func (s *authProfileService) ListProfiles(ctx context.Context) ([]model.AuthProfile, error) {
+	return s.dao.List(ctx)
+}
+
+func (s *authProfileService) SecuritySchemes(ctx context.Context, swaggerID uint) ([]model.SecurityScheme, error) {
+	doc, err := s.documentDAO.GetByID(ctx, swaggerID)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := s.specParser.ParseFromData([]byte(doc.Content))
+	if err != nil {
+		return nil, fmt.Errorf("parse swagger document %d: %w", swaggerID, err)
+	}
+	return converter.SecuritySchemes(spec), nil
+}
+
+func (s *authProfileService) CreateFromScheme(ctx context.Context, swaggerID uint, scheme string, profile *model.AuthProfile) error {
+	doc, err := s.documentDAO.GetByID(ctx, swaggerID)
+	if err != nil {
+		return err
+	}
+	schemes, err := s.SecuritySchemes(ctx, swaggerID)
+	if err != nil {
+		return err
+	}
+	var declared *model.SecurityScheme
+	for i := range schemes {
+		if schemes[i].Name == scheme {
+			declared = &schemes[i]
+			break
+		}
+	}
+	if declared == nil {
+		return fmt.Errorf("security scheme %q is not declared in swagger document %d", scheme, swaggerID)
+	}
+	if declared.Unsupported != "" {
+		return fmt.Errorf("security scheme %q: %s", scheme, declared.Unsupported)
+	}
+
+	// 类型与位置以文档声明为准，token 地址与 scope 未指定时使用文档中的值
+	profile.Type = declared.Type
+	profile.SchemeName = declared.Name
+	profile.In = declared.In
+	profile.ParamName = declared.ParamName
+	if profile.TokenURL == "" {
+		profile.TokenURL = declared.TokenURL
+	}
+	if len(profile.Scopes) == 0 {
+		profile.Scopes = declared.Scopes
+	}
+	if profile.Name == "" {
+		profile.Name = fmt.Sprintf("%d-%s", swaggerID, scheme)
+	}
+	if profile.Description == "" {
+		profile.Description = declared.Description
+	}
+	if err := s.CreateProfile(ctx, profile); err != nil {
+		return err
+	}
+	if doc.AuthProfileID != nil {
+		return nil
+	}
+	return s.dao.BindDocument(ctx, swaggerID, &profile.ID)
+}
+
+func (s *authProfileService) BindDocument(ctx context.Context, swaggerID uint, profileID *uint) error {
+	if profileID != nil {
+		if _, err := s.dao.GetByID(ctx, *profileID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("auth profile not found: %d", *profileID)
+			}
+			return err
+		}
+	}
+	return s.dao.BindDocument(ctx, swaggerID, profileID)
+}
+
+// validateAuthProfile 按类型检查鉴权配置必需的字段
+func validateAuthProfile(profile *model.AuthProfile) error {
+	if profile.Name == "" || len(profile.Name) > 64 {
+		return fmt.Errorf("auth profile name is required and must not exceed 64 characters")
+	}
+	switch profile.Type {
+	case model.AuthTypeAPIKey:
+		switch profile.In {
+		case model.AuthInHeader, model.AuthInQuery, model.AuthInCookie:
+		default:
+			return fmt.Errorf("invalid api key location: %q, must be header, query or cookie", profile.In)
+		}
+		if profile.ParamName == "" {
+			return fmt.Errorf("param_name is required for api_key profiles")
+		}
+		if profile.Value == "" {
+			return fmt.Errorf("value is required for api_key profiles")
+		}
+	case model.AuthTypeBasic:
+		if profile.Username == "" {
+			return fmt.Errorf("username is required for basic profiles")
+		}
+	case model.AuthTypeBearer:
+		if profile.Token == "" {
+			return fmt.Errorf("token is required for bearer profiles")
+		}
+	case model.AuthTypeOAuth2ClientCredentials:
+		u, err := url.Parse(profile.TokenURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid token_url: %q", profile.TokenURL)
+		}
+		if profile.ClientID == "" {
+			return fmt.Errorf("client_id is required for oauth2_client_credentials profiles")
+		}
+	default:
+		return fmt.Errorf("unsupported auth profile type: %q", profile.Type)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"mcp-manager/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newTestAuthProfileService 构造以 keyring 加密凭证的 authProfileService，记录新建的 secret
+func newTestAuthProfileService(t *testing.T, profileDAO *MockAuthProfileDAO, secretDAO *MockSecretDAO) (*authProfileService, *[]*model.Secret) {
+	s := NewAuthProfileService(profileDAO, new(MockSwaggerDocumentDAO), secretDAO).(*authProfileService)
+	s.secrets = newSecretStore(secretDAO, testKeyring(t, "", "k1"))
+	var created []*model.Secret
+	secretDAO.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		secret := args.Get(1).(*model.Secret)
+		secret.ID = uint(len(created) + 1)
+		created = append(created, secret)
+	}).Return(nil)
+	return s, &created
+}
+
+func TestAuthProfileService_CreateProfile_SealsCredentials(t *testing.T) {
+	ctx := context.Background()
+	profileDAO := new(MockAuthProfileDAO)
+	secretDAO := new(MockSecretDAO)
+	s, created := newTestAuthProfileService(t, profileDAO, secretDAO)
+	profileDAO.On("GetByName", ctx, "petstore").Return(nil, gorm.ErrRecordNotFound)
+	profileDAO.On("Create", ctx, mock.Anything).Return(nil)
+
+	profile := &model.AuthProfile{Name: "petstore", Type: model.AuthTypeBasic, Username: "admin", Password: "pass", Token: "{{secret:PET_TOKEN}}"}
+	require.NoError(t, s.CreateProfile(ctx, profile))
+
+	// 明文凭证保存为 secret，配置中只记录引用；已是引用的凭证保持不变
+	require.Len(t, *created, 1)
+	secret := (*created)[0]
+	assert.True(t, strings.HasPrefix(secret.Name, "auto.auth_profile.password."), secret.Name)
+	assert.Equal(t, "password of auth profile petstore", secret.Description)
+	assert.Equal(t, "{{secret:"+secret.Name+"}}", profile.Password)
+	assert.Equal(t, "{{secret:PET_TOKEN}}", profile.Token)
+	assert.Equal(t, "admin", profile.Username)
+
+	secretDAO.On("ListByNames", ctx, []string{secret.Name}).Return([]model.Secret{*secret}, nil)
+	values, err := s.secrets.Resolve(ctx, []string{secret.Name})
+	require.NoError(t, err)
+	assert.Equal(t, "pass", values[secret.Name])
+}
+
+func TestAuthProfileService_CreateProfile_SecretsNotConfigured(t *testing.T) {
+	ctx := context.Background()
+	profileDAO := new(MockAuthProfileDAO)
+	s := NewAuthProfileService(profileDAO, new(MockSwaggerDocumentDAO), new(MockSecretDAO)).(*authProfileService)
+	s.secrets = newSecretStore(new(MockSecretDAO), nil)
+	profileDAO.On("GetByName", ctx, "petstore").Return(nil, gorm.ErrRecordNotFound)
+
+	err := s.CreateProfile(ctx, &model.AuthProfile{Name: "petstore", Type: model.AuthTypeBearer, Token: "t"})
+	assert.ErrorIs(t, err, ErrSecretsNotConfigured)
+	profileDAO.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	// 引用已有 secret 的凭证无需主密钥即可保存
+	profileDAO.On("Create", ctx, mock.Anything).Return(nil)
+	assert.NoError(t, s.CreateProfile(ctx, &model.AuthProfile{Name: "petstore", Type: model.AuthTypeBearer, Token: "{{secret:PET_TOKEN}}"}))
+}
+
+func TestAuthProfileService_UpdateProfile_KeepsCredentials(t *testing.T) {
+	ctx := context.Background()
+	profileDAO := new(MockAuthProfileDAO)
+	secretDAO := new(MockSecretDAO)
+	s, created := newTestAuthProfileService(t, profileDAO, secretDAO)
+	createdAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	stored := "{{secret:auto.auth_profile.client_secret.old}}"
+	profileDAO.On("GetByID", ctx, uint(1)).Return(&model.AuthProfile{
+		ID: 1, Name: "petstore", Type: model.AuthTypeOAuth2ClientCredentials,
+		TokenURL: "https://auth.test/token", ClientID: "id", ClientSecret: stored, CreatedAt: createdAt,
+	}, nil)
+	profileDAO.On("GetByName", ctx, "petstore").Return(nil, gorm.ErrRecordNotFound)
+	profileDAO.On("Update", ctx, mock.Anything).Return(nil)
+
+	// 未提交的凭证沿用已保存的取值
+	profile := &model.AuthProfile{ID: 1, Name: "petstore", Type: model.AuthTypeOAuth2ClientCredentials,
+		TokenURL: "https://auth.test/token", ClientID: "id2"}
+	require.NoError(t, s.UpdateProfile(ctx, profile))
+	assert.Equal(t, stored, profile.ClientSecret)
+	assert.Equal(t, "id2", profile.ClientID)
+	assert.Equal(t, createdAt, profile.CreatedAt)
+	assert.Empty(t, *created)
+	secretDAO.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+
+	// 提交的凭证保存为新的 secret，替换掉的 secret 被删除
+	secretDAO.On("ListByNames", ctx, []string{"auto.auth_profile.client_secret.old"}).
+		Return([]model.Secret{{ID: 9, Name: "auto.auth_profile.client_secret.old"}}, nil)
+	secretDAO.On("Delete", ctx, uint(9)).Return(nil)
+	profile = &model.AuthProfile{ID: 1, Name: "petstore", Type: model.AuthTypeOAuth2ClientCredentials,
+		TokenURL: "https://auth.test/token", ClientID: "id", ClientSecret: "rotated"}
+	require.NoError(t, s.UpdateProfile(ctx, profile))
+	require.Len(t, *created, 1)
+	assert.Equal(t, "{{secret:"+(*created)[0].Name+"}}", profile.ClientSecret)
+	secretDAO.AssertCalled(t, "Delete", ctx, uint(9))
+}
+
+func TestAuthProfileService_DeleteProfile_ReleasesCredentials(t *testing.T) {
+	ctx := context.Background()
+	profileDAO := new(MockAuthProfileDAO)
+	secretDAO := new(MockSecretDAO)
+	s, _ := newTestAuthProfileService(t, profileDAO, secretDAO)
+	profileDAO.On("GetByID", ctx, uint(1)).Return(&model.AuthProfile{
+		ID: 1, Name: "petstore", Type: model.AuthTypeBasic, Username: "admin",
+		Password: "{{secret:auto.auth_profile.password.a}}", Token: "{{secret:PET_TOKEN}}",
+	}, nil)
+	profileDAO.On("Delete", ctx, uint(1)).Return(nil)
+	secretDAO.On("ListByNames", ctx, []string{"auto.auth_profile.password.a"}).
+		Return([]model.Secret{{ID: 3, Name: "auto.auth_profile.password.a"}}, nil)
+	secretDAO.On("Delete", ctx, uint(3)).Return(nil)
+
+	require.NoError(t, s.DeleteProfile(ctx, 1))
+	// 只删除自动保存的 secret，用户创建的 secret 不受影响
+	secretDAO.AssertCalled(t, "Delete", ctx, uint(3))
+	secretDAO.AssertNumberOfCalls(t, "ListByNames", 1)
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mcp-manager/internal/model"
+	http "mcp-manager/internal/utils/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryLeeway 为 token 过期前提前刷新的时间，避免请求途中过期
+const tokenExpiryLeeway = 30 * time.Second
+
+// Authenticator 把鉴权配置应用到上游请求，OAuth2 client credentials 的 token 按配置缓存，过期前自动刷新
+type Authenticator struct {
+	httpClient http.HTTPClient
+	now        func() time.Time
+
+	mu     sync.Mutex
+	tokens map[uint]*cachedToken
+}
+
+// cachedToken 为缓存的 OAuth2 token，配置修改后（UpdatedAt 变化）失效
+type cachedToken struct {
+	accessToken string
+	expiresAt   time.Time // 为零值时表示未声明有效期，直到上游返回 401 才刷新
+	updatedAt   time.Time
+}
+
+// NewAuthenticator 创建一个通过 httpClient 获取 OAuth2 token 的 Authenticator
+func NewAuthenticator(httpClient http.HTTPClient) *Authenticator {
+	return &Authenticator{httpClient: httpClient, now: time.Now, tokens: make(map[uint]*cachedToken)}
+}
+
+// Apply 按 profile 在 req 中加入鉴权信息，会覆盖同名的请求头、query 参数与 cookie
+func (a *Authenticator) Apply(ctx context.Context, profile *model.AuthProfile, req *APIRequest) error {
+	switch profile.Type {
+	case model.AuthTypeAPIKey:
+		return applyAPIKey(profile, req)
+	case model.AuthTypeBasic:
+		credentials := base64.StdEncoding.EncodeToString([]byte(profile.Username + ":" + profile.Password))
+		req.Headers["Authorization"] = "Basic " + credentials
+	case model.AuthTypeBearer:
+		req.Headers["Authorization"] = "Bearer " + profile.Token
+	case model.AuthTypeOAuth2ClientCredentials:
+		token, err := a.token(ctx, profile)
+		if err != nil {
+			return err
+		}
+		req.Headers["Authorization"] = "Bearer " + token
+	default:
+		return fmt.Errorf("unsupported auth profile type: %s", profile.Type)
+	}
+	return nil
+}
+
+// Invalidate 丢弃 profile 缓存的 token，下次 Apply 时重新获取
+func (a *Authenticator) Invalidate(profile *model.AuthProfile) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.tokens, profile.ID)
+}
+
+// applyAPIKey 按 profile.In 把 API key 放到请求头、query 参数或 cookie 中
+func applyAPIKey(profile *model.AuthProfile, req *APIRequest) error {
+	switch profile.In {
+	case model.AuthInHeader:
+		req.Headers[profile.ParamName] = profile.Value
+	case model.AuthInQuery:
+		u, err := url.Parse(req.URL)
+		if err != nil {
+			return err
+		}
+		query := u.Query()
+		query.Set(profile.ParamName, profile.Value)
+		u.RawQuery = query.Encode()
+		req.URL = u.String()
+	case model.AuthInCookie:
+		cookie := profile.ParamName + "=" + profile.Value
+		if existing := req.Headers["Cookie"]; existing != "" {
+			cookie = existing + "; " + cookie
+		}
+		req.Headers["Cookie"] = cookie
+	default:
+		return fmt.Errorf("unsupported api key location: %s", profile.In)
+	}
+	return nil
+}
+
+// token 返回 profile 缓存的 token，不存在、已过期或配置已修改时重新获取
+// 未保存的配置（ID 为 0）不缓存
+func (a *Authenticator) token(ctx context.Context, profile *model.AuthProfile) (string, error) {
+	if profile.ID != 0 {
+		a.mu.Lock()
+		cached, ok := a.tokens[profile.ID]
+		a.mu.Unlock()
+		if ok && cached.updatedAt.Equal(profile.UpdatedAt) &&
+			(cached.expiresAt.IsZero() || a.now().Add(tokenExpiryLeeway).Before(cached.expiresAt)) {
+			return cached.accessToken, nil
+		}
+	}
+
+	token, err := a.fetchToken(ctx, profile)
+	if err != nil {
+		return "", err
+	}
+	if profile.ID != 0 {
+		a.mu.Lock()
+		a.tokens[profile.ID] = token
+		a.mu.Unlock()
+	}
+	return token.accessToken, nil
+}
+
+// fetchToken 以 client credentials 方式向 profile.TokenURL 申请 token，客户端凭证按 RFC 6749 以 Basic 方式发送
+func (a *Authenticator) fetchToken(ctx context.Context, profile *model.AuthProfile) (*cachedToken, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(profile.Scopes) > 0 {
+		form.Set("scope", strings.Join(profile.Scopes, " "))
+	}
+	credentials := url.QueryEscape(profile.ClientID) + ":" + url.QueryEscape(profile.ClientSecret)
+	headers := map[string]string{
+		"Content-Type":  "application/x-www-form-urlencoded",
+		"Accept":        "application/json",
+		"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials)),
+	}
+	resp, err := a.httpClient.DoRequest(ctx, "POST", profile.TokenURL, headers, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("fetch oauth2 token from %s: %w", profile.TokenURL, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("fetch oauth2 token from %s: %s: %s", profile.TokenURL, resp.Status, truncate(resp.Body, 256))
+	}
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+		return nil, fmt.Errorf("decode oauth2 token from %s: %w", profile.TokenURL, err)
+	}
+	if body.AccessToken == "" {
+		return nil, fmt.Errorf("oauth2 token response from %s has no access_token", profile.TokenURL)
+	}
+	token := &cachedToken{accessToken: body.AccessToken, updatedAt: profile.UpdatedAt}
+	if body.ExpiresIn > 0 {
+		token.expiresAt = a.now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// truncate 截断过长的错误内容
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
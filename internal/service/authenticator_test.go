@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"mcp-manager/internal/model"
+	httpclient "mcp-manager/internal/utils/http"
+	"mcp-manager/internal/utils/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const tokenURL = "https://auth.example.com/token"
+
+var machineProfile = &model.AuthProfile{
+	ID:           4,
+	Name:         "machine",
+	Type:         model.AuthTypeOAuth2ClientCredentials,
+	TokenURL:     tokenURL,
+	ClientID:     "client",
+	ClientSecret: "s3cret",
+	Scopes:       model.StringList{"read", "write"},
+}
+
+// tokenRequest 匹配申请 token 的请求，请求体只能读取一次，因此在 Run 中检查
+func tokenRequest() (string, string, interface{}, interface{}) {
+	return "POST", tokenURL, mock.MatchedBy(func(h map[string]string) bool {
+		return h["Authorization"] == "Basic Y2xpZW50OnMzY3JldA=="
+	}), mock.Anything
+}
+
+func TestAuthenticator_APIKey(t *testing.T) {
+	a := NewAuthenticator(new(MockHTTPClient))
+	ctx := context.Background()
+
+	req := &APIRequest{URL: "http://api/pets?limit=1", Headers: map[string]string{"Cookie": "session=1"}}
+	require.NoError(t, a.Apply(ctx, &model.AuthProfile{Type: model.AuthTypeAPIKey, In: model.AuthInQuery, ParamName: "api_key", Value: "k y"}, req))
+	assert.Equal(t, "http://api/pets?api_key=k+y&limit=1", req.URL)
+
+	require.NoError(t, a.Apply(ctx, &model.AuthProfile{Type: model.AuthTypeAPIKey, In: model.AuthInCookie, ParamName: "token", Value: "abc"}, req))
+	assert.Equal(t, "session=1; token=abc", req.Headers["Cookie"])
+
+	require.NoError(t, a.Apply(ctx, &model.AuthProfile{Type: model.AuthTypeAPIKey, In: model.AuthInHeader, ParamName: "X-API-Key", Value: "abc"}, req))
+	assert.Equal(t, "abc", req.Headers["X-API-Key"])
+}
+
+func TestAuthenticator_BasicAndBearer(t *testing.T) {
+	a := NewAuthenticator(new(MockHTTPClient))
+	req := &APIRequest{Headers: map[string]string{}}
+
+	require.NoError(t, a.Apply(context.Background(), &model.AuthProfile{Type: model.AuthTypeBasic, Username: "user", Password: "pass"}, req))
+	assert.Equal(t, "Basic dXNlcjpwYXNz", req.Headers["Authorization"])
+
+	require.NoError(t, a.Apply(context.Background(), &model.AuthProfile{Type: model.AuthTypeBearer, Token: "t0k"}, req))
+	assert.Equal(t, "Bearer t0k", req.Headers["Authorization"])
+}
+
+func TestAuthenticator_OAuth2TokenCaching(t *testing.T) {
+	client := new(MockHTTPClient)
+	a := NewAuthenticator(client)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	method, url, headers, body := tokenRequest()
+	client.On("DoRequest", ctx, method, url, headers, body).
+		Run(func(args mock.Arguments) {
+			b, _ := io.ReadAll(args.Get(4).(io.Reader))
+			assert.Equal(t, "grant_type=client_credentials&scope=read+write", string(b))
+		}).
+		Return(&httpclient.Response{StatusCode: 200, Body: `{"access_token":"first","expires_in":3600}`}, nil).Once()
+	client.On("DoRequest", ctx, method, url, headers, body).
+		Return(&httpclient.Response{StatusCode: 200, Body: `{"access_token":"second","expires_in":3600}`}, nil).Once()
+
+	apply := func() string {
+		req := &APIRequest{Headers: map[string]string{}}
+		require.NoError(t, a.Apply(ctx, machineProfile, req))
+		return req.Headers["Authorization"]
+	}
+	assert.Equal(t, "Bearer first", apply())
+	now = now.Add(30 * time.Minute)
+	assert.Equal(t, "Bearer first", apply(), "the cached token is reused")
+
+	// 过期前提前刷新
+	now = now.Add(30*time.Minute - tokenExpiryLeeway/2)
+	assert.Equal(t, "Bearer second", apply())
+	client.AssertNumberOfCalls(t, "DoRequest", 2)
+}
+
+func TestAuthenticator_OAuth2TokenError(t *testing.T) {
+	client := new(MockHTTPClient)
+	a := NewAuthenticator(client)
+	client.On("DoRequest", mock.Anything, "POST", tokenURL, mock.Anything, mock.Anything).
+		Return(&httpclient.Response{StatusCode: 401, Status: "401 Unauthorized", Body: `{"error":"invalid_client"}`}, nil)
+
+	err := a.Apply(context.Background(), machineProfile, &APIRequest{Headers: map[string]string{}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_client")
+}
+
+func TestAPIExecutor_RefreshesRevokedToken(t *testing.T) {
+	client := new(MockHTTPClient)
+	executor := NewAPIExecutor(client, nil)
+	ctx := context.Background()
+	endpoint := &model.APIEndpoint{Path: "/pets", Method: "GET"}
+
+	method, url, headers, body := tokenRequest()
+	client.On("DoRequest", ctx, method, url, headers, body).
+		Return(&httpclient.Response{StatusCode: 200, Body: `{"access_token":"revoked"}`}, nil).Once()
+	client.On("DoRequest", ctx, method, url, headers, body).
+		Return(&httpclient.Response{StatusCode: 200, Body: `{"access_token":"fresh"}`}, nil).Once()
+	bearer := func(token string) interface{} {
+		return mock.MatchedBy(func(h map[string]string) bool { return h["Authorization"] == "Bearer "+token })
+	}
+	client.On("DoRequest", ctx, "GET", "http://api/pets", bearer("revoked"), nil).
+		Return(&httpclient.Response{StatusCode: 401, Status: "401 Unauthorized"}, nil).Once()
+	client.On("DoRequest", ctx, "GET", "http://api/pets", bearer("fresh"), nil).
+		Return(&httpclient.Response{StatusCode: 200, Status: "200 OK", Body: "[]"}, nil).Once()
+
+	resp, err := executor.Execute(ctx, endpoint, "http://api", machineProfile)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	client.AssertExpectations(t)
+}
+
+func TestSwaggerService_TestAPIEndpoint_DocumentAuth(t *testing.T) {
+	httpClient := new(MockHTTPClient)
+	s := newTestSwaggerService(new(MockSwaggerParser), new(MockAPIEndpointDAO), httpClient)
+	s.specParser = parser.NewSwaggerParser()
+	profileID := uint(2)
+	s.documentDAO.(*MockSwaggerDocumentDAO).On("GetByID", mock.Anything, uint(7)).
+		Return(&model.SwaggerDocument{ID: 7, Content: contractSpec, Servers: model.StringList{"http://pets.test/"}, AuthProfileID: &profileID}, nil)
+	s.profileDAO.(*MockAuthProfileDAO).On("GetByID", mock.Anything, profileID).
+		Return(&model.AuthProfile{ID: profileID, Type: model.AuthTypeAPIKey, In: model.AuthInHeader, ParamName: "X-API-Key", Value: "k"}, nil)
+	httpClient.On("DoRequest", mock.Anything, "GET", "http://pets.test/pets/1", mock.MatchedBy(func(h map[string]string) bool {
+		return h["X-API-Key"] == "k"
+	}), mock.Anything).Return(jsonResponse(200, `{"id": 1, "name": "Rex"}`), nil)
+
+	result, err := s.TestAPIEndpoint(context.Background(), storeEndpoint(s, petEndpoint), "http://pets.test")
+	require.NoError(t, err)
+	assert.Equal(t, 200, result.StatusCode)
+	httpClient.AssertExpectations(t)
+
+	// 其他地址不是文档声明的服务器，不携带文档的凭证
+	httpClient.On("DoRequest", mock.Anything, "GET", "http://other.test/pets/1", mock.MatchedBy(func(h map[string]string) bool {
+		_, ok := h["X-API-Key"]
+		return !ok
+	}), mock.Anything).Return(jsonResponse(200, `{"id": 1, "name": "Rex"}`), nil)
+	_, err = s.TestAPIEndpoint(context.Background(), storeEndpoint(s, petEndpoint), "http://other.test")
+	require.NoError(t, err)
+	httpClient.AssertExpectations(t)
+	s.profileDAO.(*MockAuthProfileDAO).AssertNumberOfCalls(t, "GetByID", 1)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	"regexp"
+)
+
+// mcpServerNamePattern 限制 server 名称，名称会出现在 MCP 接入路径中
+var mcpServerNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// mcpToolNamePattern 与 MCP 客户端普遍接受的工具名格式一致
+var mcpToolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// MCPServerService 定义 MCP Server 实例管理的业务接口
+type MCPServerService interface {
+	// CreateServer 创建 MCP Server 及其工具绑定
+	CreateServer(ctx context.Context, server *model.MCPServer) error
+	// UpdateServer 更新 MCP Server，工具绑定整体替换
+	UpdateServer(ctx context.Context, server *model.MCPServer) error
+	// DeleteServer 删除 MCP Server 及其工具绑定
+	DeleteServer(ctx context.Context, id uint) error
+	// GetServerByID 根据 ID 查询 MCP Server
+	GetServerByID(ctx context.Context, id uint) (*model.MCPServer, error)
+	// ListServers 查询所有 MCP Server
+	ListServers(ctx context.Context) ([]model.MCPServer, error)
+}
+
+// mcpServerService 实现 MCPServerService 接口
+type mcpServerService struct {
+	dao         dao.MCPServerDAO
+	endpointDAO dao.APIEndpointDAO
+	profileDAO  dao.AuthProfileDAO
+}
+
+// NewMCPServerService 创建一个新的 MCPServerService 实例
+func NewMCPServerService(serverDAO dao.MCPServerDAO, endpointDAO dao.APIEndpointDAO, profileDAO dao.AuthProfileDAO) MCPServerService {
+	if serverDAO == nil {
+		serverDAO = dao.NewMCPServerDAO(nil)
+	}
+	if endpointDAO == nil {
+		endpointDAO = dao.NewAPIEndpointDAO(nil)
+	}
+	if profileDAO == nil {
+		profileDAO = dao.NewAuthProfileDAO(nil)
+	}
+	return &mcpServerService{dao: serverDAO, endpointDAO: endpointDAO, profileDAO: profileDAO}
+}
+
+// CreateServer 校验后创建 server，未配置传输方式时默认全部启用
+func (s *mcpServerService) CreateServer(ctx context.Context, server *model.MCPServer) error {
+	if server.Transport == (model.TransportConfig{}) {
+		server.Transport = model.DefaultTransportConfig()
+	}
+	if err := s.validate(ctx, server); err != nil {
+		return err
+	}
+	if existing, err := s.dao.GetByName(ctx, server.Name); err == nil && existing != nil {
+		return fmt.Errorf("mcp server name already exists: %s", server.Name)
+	}
+	server.ID = 0
+	for i := range server.Tools {
+		server.Tools[i].ID = 0
+		server.Tools[i].ServerID = 0
+	}
+	return s.dao.Create(ctx, server)
+}
+
+// UpdateServer 校验后更新 server，名称不可与其他 server 重复；未提供传输方式时保持原配置
+func (s *mcpServerService) UpdateServer(ctx context.Context, server *model.MCPServer) error {
+	existing, err := s.dao.GetByID(ctx, server.ID)
+	if err != nil {
+		return err
+	}
+	if server.Transport == (model.TransportConfig{}) {
+		server.Transport = existing.Transport
+	}
+	if err := s.validate(ctx, server); err != nil {
+		return err
+	}
+	if other, err := s.dao.GetByName(ctx, server.Name); err == nil && other != nil && other.ID != server.ID {
+		return fmt.Errorf("mcp server name already exists: %s", server.Name)
+	}
+	server.CreatedAt = existing.CreatedAt
+	if err := s.dao.Update(ctx, server); err != nil {
+		return err
+	}
+	updated, err := s.dao.GetByID(ctx, server.ID)
+	if err != nil {
+		return err
+	}
+	*server = *updated
+	return nil
+}
+
+func (s *mcpServerService) DeleteServer(ctx context.Context, id uint) error {
+	return s.dao.Delete(ctx, id)
+}
+
+func (s *mcpServerService) GetServerByID(ctx context.Context, id uint) (*model.MCPServer, error) {
+	return s.dao.GetByID(ctx, id)
+}
+
+func (s *mcpServerService) ListServers(ctx context.Context) ([]model.MCPServer, error) {
+	return s.dao.List(ctx)
+}
+
+// validate 校验名称格式、工具名唯一性以及绑定的接口是否存在
+func (s *mcpServerService) validate(ctx context.Context, server *model.MCPServer) error {
+	if !mcpServerNamePattern.MatchString(server.Name) {
+		return fmt.Errorf("invalid mcp server name: %q, only letters, digits, '_' and '-' are allowed", server.Name)
+	}
+	if server.AuthProfileID != nil {
+		if _, err := s.profileDAO.GetByID(ctx, *server.AuthProfileID); err != nil {
+			return fmt.Errorf("auth profile not found: %d", *server.AuthProfileID)
+		}
+	}
+
+	ids := make([]uint, 0, len(server.Tools))
+	toolNames := make(map[string]bool)
+	endpointIDs := make(map[uint]bool)
+	for _, tool := range server.Tools {
+		if endpointIDs[tool.EndpointID] {
+			return fmt.Errorf("endpoint %d is bound more than once", tool.EndpointID)
+		}
+		endpointIDs[tool.EndpointID] = true
+		ids = append(ids, tool.EndpointID)
+
+		if tool.ToolName == "" {
+			continue
+		}
+		if !mcpToolNamePattern.MatchString(tool.ToolName) {
+			return fmt.Errorf("invalid tool name: %q, only letters, digits, '_' and '-' are allowed", tool.ToolName)
+		}
+		if toolNames[tool.ToolName] {
+			return fmt.Errorf("duplicate tool name: %s", tool.ToolName)
+		}
+		toolNames[tool.ToolName] = true
+	}
+
+	endpoints, err := s.endpointDAO.ListByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	found := make(map[uint]bool, len(endpoints))
+	for _, e := range endpoints {
+		found[e.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return fmt.Errorf("endpoint not found: %d", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"mcp-manager/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPServerService_UpdateServer_KeepsTransport(t *testing.T) {
+	ctx := context.Background()
+	serverDAO := new(MockMCPServerDAO)
+	endpointDAO := new(MockAPIEndpointDAO)
+	svc := NewMCPServerService(serverDAO, endpointDAO, new(MockAuthProfileDAO))
+
+	existing := &model.MCPServer{ID: 1, Name: "pets", Transport: model.TransportConfig{SSE: true}}
+	serverDAO.On("GetByID", ctx, uint(1)).Return(existing, nil)
+	serverDAO.On("GetByName", ctx, "pets").Return(existing, nil)
+	endpointDAO.On("ListByIDs", ctx, []uint{}).Return([]model.APIEndpoint{}, nil)
+	serverDAO.On("Update", ctx, mock.MatchedBy(func(s *model.MCPServer) bool {
+		return s.Transport == model.TransportConfig{SSE: true}
+	})).Return(nil)
+
+	// 请求未携带 transport 时不应关闭所有传输方式
+	require.NoError(t, svc.UpdateServer(ctx, &model.MCPServer{ID: 1, Name: "pets", Description: "updated"}))
+	serverDAO.AssertExpectations(t)
+	assert.True(t, existing.Transport.SSE)
+}
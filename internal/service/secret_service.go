@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/utils/envelope"
+	"mcp-manager/pkg/config"
+	"mcp-manager/pkg/logger"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrSecretsNotConfigured 表示未配置主密钥，无法保存或读取 secret
+var ErrSecretsNotConfigured = errors.New("secret storage is not configured, set secrets.keys or SECRETS_KEYS")
+
+// SecretService 定义 secret 管理的业务接口，secret 的取值只能写入，不会通过接口返回
+type SecretService interface {
+	// CreateSecret 加密 value 后创建 secret
+	CreateSecret(ctx context.Context, secret *model.Secret, value string) error
+	// UpdateSecret 更新 secret 的描述，value 不为 nil 时以当前主密钥重新加密新的取值
+	UpdateSecret(ctx context.Context, secret *model.Secret, value *string) error
+	// DeleteSecret 删除 secret，引用它的请求在发送时报错
+	DeleteSecret(ctx context.Context, id uint) error
+	// GetSecretByID 根据 ID 查询 secret 的元信息
+	GetSecretByID(ctx context.Context, id uint) (*model.Secret, error)
+	// ListSecrets 查询所有 secret 的元信息
+	ListSecrets(ctx context.Context) ([]model.Secret, error)
+	// RotateKeys 以当前主密钥重新加密所有 secret 的数据密钥，返回重新加密的数量
+	RotateKeys(ctx context.Context) (int, error)
+}
+
+// SecretResolver 按名称解密 secret，只在 APIExecutor 发送请求时使用
+type SecretResolver interface {
+	// Resolve 返回 names 对应的明文，任一 secret 不存在时返回错误
+	Resolve(ctx context.Context, names []string) (map[string]string, error)
+}
+
+// secretStore 实现 SecretService 与 SecretResolver 接口
+type secretStore struct {
+	dao     dao.SecretDAO
+	keyring *envelope.Keyring
+}
+
+var (
+	defaultKeyringOnce sync.Once
+	defaultKeyring     *envelope.Keyring
+)
+
+// DefaultKeyring 返回按配置构造的主密钥，只构造一次；未配置或配置有误时返回 nil，此时无法保存或读取 secret
+func DefaultKeyring() *envelope.Keyring {
+	defaultKeyringOnce.Do(func() {
+		keyring, err := envelope.NewKeyring(config.SecretKeys(), config.SecretActiveKey())
+		switch {
+		case errors.Is(err, envelope.ErrNoKeys):
+			log.Infof("no secret master key configured, secrets are disabled")
+		case err != nil:
+			log.Errorf("invalid secret master keys, secrets are disabled: %v", err)
+		default:
+			defaultKeyring = keyring
+		}
+	})
+	return defaultKeyring
+}
+
+// NewSecretService 创建一个新的 SecretService 实例，keyring 为 nil 时保存 secret 返回 ErrSecretsNotConfigured
+func NewSecretService(secretDAO dao.SecretDAO, keyring *envelope.Keyring) SecretService {
+	return newSecretStore(secretDAO, keyring)
+}
+
+// NewSecretResolver 创建一个新的 SecretResolver 实例，keyring 为 nil 时解密 secret 返回 ErrSecretsNotConfigured
+func NewSecretResolver(secretDAO dao.SecretDAO, keyring *envelope.Keyring) SecretResolver {
+	return newSecretStore(secretDAO, keyring)
+}
+
+func newSecretStore(secretDAO dao.SecretDAO, keyring *envelope.Keyring) *secretStore {
+	if secretDAO == nil {
+		secretDAO = dao.NewSecretDAO(nil)
+	}
+	return &secretStore{dao: secretDAO, keyring: keyring}
+}
+
+func (s *secretStore) CreateSecret(ctx context.Context, secret *model.Secret, value string) error {
+	if !model.SecretNamePattern.MatchString(secret.Name) {
+		return fmt.Errorf("invalid secret name: %q, only letters, digits, '_', '.' and '-' are allowed", secret.Name)
+	}
+	if strings.HasPrefix(secret.Name, model.ManagedSecretPrefix) {
+		return fmt.Errorf("invalid secret name: %q, names starting with %q are reserved", secret.Name, model.ManagedSecretPrefix)
+	}
+	if value == "" {
+		return fmt.Errorf("secret value is required")
+	}
+	if existing, err := s.dao.ListByNames(ctx, []string{secret.Name}); err == nil && len(existing) > 0 {
+		return fmt.Errorf("secret name already exists: %s", secret.Name)
+	}
+	if err := s.seal(secret, value); err != nil {
+		return err
+	}
+	secret.ID = 0
+	if err := s.dao.Create(ctx, secret); err != nil {
+		return err
+	}
+	logger.DefaultRedactor.Set(secret.Name, value)
+	return nil
+}
+
+func (s *secretStore) UpdateSecret(ctx context.Context, secret *model.Secret, value *string) error {
+	existing, err := s.dao.GetByID(ctx, secret.ID)
+	if err != nil {
+		return err
+	}
+	// 名称被引用，不允许修改
+	existing.Description = secret.Description
+	if value != nil {
+		if *value == "" {
+			return fmt.Errorf("secret value is required")
+		}
+		if err := s.seal(existing, *value); err != nil {
+			return err
+		}
+	}
+	if err := s.dao.Update(ctx, existing); err != nil {
+		return err
+	}
+	// 日志中改为脱敏新取值
+	if value != nil {
+		logger.DefaultRedactor.Set(existing.Name, *value)
+	}
+	*secret = *existing
+	return nil
+}
+
+func (s *secretStore) DeleteSecret(ctx context.Context, id uint) error {
+	existing, err := s.dao.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.dao.Delete(ctx, id); err != nil {
+		return err
+	}
+	logger.DefaultRedactor.Remove(existing.Name)
+	return nil
+}
+
+func (s *secretStore) GetSecretByID(ctx context.Context, id uint) (*model.Secret, error) {
+	return s.dao.GetByID(ctx, id)
+}
+
+func (s *secretStore) ListSecrets(ctx context.Context) ([]model.Secret, error) {
+	return s.dao.List(ctx)
+}
+
+func (s *secretStore) RotateKeys(ctx context.Context) (int, error) {
+	if s.keyring == nil {
+		return 0, ErrSecretsNotConfigured
+	}
+	secrets, err := s.dao.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var rotated []model.Secret
+	for _, secret := range secrets {
+		sealed := &envelope.Sealed{KeyID: secret.KeyID, WrappedKey: secret.WrappedKey, Ciphertext: secret.Ciphertext}
+		changed, err := s.keyring.Rewrap(sealed)
+		if err != nil {
+			return 0, fmt.Errorf("rotate secret %s: %w", secret.Name, err)
+		}
+		if changed {
+			secret.KeyID, secret.WrappedKey = sealed.KeyID, sealed.WrappedKey
+			rotated = append(rotated, secret)
+		}
+	}
+	if len(rotated) == 0 {
+		return 0, nil
+	}
+	if err := s.dao.UpdateKeys(ctx, rotated); err != nil {
+		return 0, err
+	}
+	return len(rotated), nil
+}
+
+func (s *secretStore) Resolve(ctx context.Context, names []string) (map[string]string, error) {
+	if s.keyring == nil {
+		return nil, ErrSecretsNotConfigured
+	}
+	secrets, err := s.dao.ListByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(secrets))
+	for _, secret := range secrets {
+		plaintext, err := s.keyring.Open(&envelope.Sealed{KeyID: secret.KeyID, WrappedKey: secret.WrappedKey, Ciphertext: secret.Ciphertext})
+		if err != nil {
+			return nil, fmt.Errorf("decrypt secret %s: %w", secret.Name, err)
+		}
+		values[secret.Name] = string(plaintext)
+		logger.DefaultRedactor.Set(secret.Name, string(plaintext))
+	}
+	for _, name := range names {
+		if _, ok := values[name]; !ok {
+			return nil, fmt.Errorf("secret not found: %s", name)
+		}
+	}
+	return values, nil
+}
+
+// sealValue 把明文 value 保存为名称以 model.ManagedSecretPrefix+kind 开头的 secret，返回对它的引用
+// value 为空或已包含 secret 引用时原样返回
+func (s *secretStore) sealValue(ctx context.Context, kind, description, value string) (string, error) {
+	if value == "" || model.SecretRefPattern.MatchString(value) {
+		return value, nil
+	}
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	secret := &model.Secret{Name: model.ManagedSecretPrefix + kind + "." + hex.EncodeToString(suffix), Description: description}
+	if err := s.seal(secret, value); err != nil {
+		return "", err
+	}
+	if err := s.dao.Create(ctx, secret); err != nil {
+		return "", err
+	}
+	logger.DefaultRedactor.Set(secret.Name, value)
+	return "{{secret:" + secret.Name + "}}", nil
+}
+
+// releaseValues 删除 values 中引用的自动保存的 secret，用户创建的 secret 不受影响
+// 删除失败只记录日志，残留的 secret 不影响使用
+func (s *secretStore) releaseValues(ctx context.Context, values ...string) {
+	names := model.ManagedSecretRefs(values...)
+	if len(names) == 0 {
+		return
+	}
+	secrets, err := s.dao.ListByNames(ctx, names)
+	if err != nil {
+		log.Warnf("release secrets %v failed: %v", names, err)
+		return
+	}
+	for _, secret := range secrets {
+		if err := s.dao.Delete(ctx, secret.ID); err != nil {
+			log.Warnf("release secret %s failed: %v", secret.Name, err)
+			continue
+		}
+		logger.DefaultRedactor.Remove(secret.Name)
+	}
+}
+
+// expand 返回把 value 中的 secret 引用替换为明文后的值
+func (s *secretStore) expand(ctx context.Context, value string) (string, error) {
+	names := model.SecretRefs(value)
+	if len(names) == 0 {
+		return value, nil
+	}
+	values, err := s.Resolve(ctx, names)
+	if err != nil {
+		return "", err
+	}
+	return model.SecretRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		return values[model.SecretRefPattern.FindStringSubmatch(ref)[1]]
+	}), nil
+}
+
+// seal 以当前主密钥加密 value 并写入 secret
+func (s *secretStore) seal(secret *model.Secret, value string) error {
+	if s.keyring == nil {
+		return ErrSecretsNotConfigured
+	}
+	sealed, err := s.keyring.Seal([]byte(value))
+	if err != nil {
+		return err
+	}
+	secret.KeyID, secret.WrappedKey, secret.Ciphertext = sealed.KeyID, sealed.WrappedKey, sealed.Ciphertext
+	return nil
+}
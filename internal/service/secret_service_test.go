@@ -0,0 +1,213 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/utils/envelope"
+	httpclient "mcp-manager/internal/utils/http"
+	"mcp-manager/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSecretDAO 模拟 SecretDAO
+type MockSecretDAO struct {
+	mock.Mock
+}
+
+func (m *MockSecretDAO) Create(ctx context.Context, secret *model.Secret) error {
+	args := m.Called(ctx, secret)
+	return args.Error(0)
+}
+
+func (m *MockSecretDAO) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSecretDAO) Update(ctx context.Context, secret *model.Secret) error {
+	args := m.Called(ctx, secret)
+	return args.Error(0)
+}
+
+func (m *MockSecretDAO) GetByID(ctx context.Context, id uint) (*model.Secret, error) {
+	args := m.Called(ctx, id)
+	secret, _ := args.Get(0).(*model.Secret)
+	return secret, args.Error(1)
+}
+
+func (m *MockSecretDAO) ListByNames(ctx context.Context, names []string) ([]model.Secret, error) {
+	args := m.Called(ctx, names)
+	return args.Get(0).([]model.Secret), args.Error(1)
+}
+
+func (m *MockSecretDAO) List(ctx context.Context) ([]model.Secret, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.Secret), args.Error(1)
+}
+
+func (m *MockSecretDAO) UpdateKeys(ctx context.Context, secrets []model.Secret) error {
+	args := m.Called(ctx, secrets)
+	return args.Error(0)
+}
+
+func testKeyring(t *testing.T, active string, ids ...string) *envelope.Keyring {
+	keys := make(map[string]string)
+	for i, id := range ids {
+		keys[id] = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{byte(i + 1)}, envelope.KeySize))
+	}
+	keyring, err := envelope.NewKeyring(keys, active)
+	require.NoError(t, err)
+	return keyring
+}
+
+// sealedSecret 创建一个以 keyring 加密 value 的 secret
+func sealedSecret(t *testing.T, keyring *envelope.Keyring, id uint, name, value string) model.Secret {
+	secret := model.Secret{ID: id, Name: name}
+	require.NoError(t, newSecretStore(new(MockSecretDAO), keyring).seal(&secret, value))
+	return secret
+}
+
+func TestSecretService_CreateAndResolve(t *testing.T) {
+	ctx := context.Background()
+	secretDAO := new(MockSecretDAO)
+	keyring := testKeyring(t, "", "k1")
+	store := newSecretStore(secretDAO, keyring)
+
+	var saved *model.Secret
+	secretDAO.On("ListByNames", ctx, []string{"PET_KEY"}).Return([]model.Secret{}, nil).Once()
+	secretDAO.On("Create", ctx, mock.AnythingOfType("*model.Secret")).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*model.Secret)
+	}).Return(nil)
+
+	require.NoError(t, store.CreateSecret(ctx, &model.Secret{Name: "PET_KEY"}, "pet-api-key"))
+	require.NotNil(t, saved)
+	assert.Equal(t, "k1", saved.KeyID)
+	assert.NotContains(t, string(saved.Ciphertext), "pet-api-key")
+
+	secretDAO.On("ListByNames", ctx, []string{"PET_KEY"}).Return([]model.Secret{*saved}, nil).Once()
+	values, err := store.Resolve(ctx, []string{"PET_KEY"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"PET_KEY": "pet-api-key"}, values)
+
+	secretDAO.On("ListByNames", ctx, []string{"PET_KEY", "MISSING"}).Return([]model.Secret{*saved}, nil)
+	_, err = store.Resolve(ctx, []string{"PET_KEY", "MISSING"})
+	assert.EqualError(t, err, "secret not found: MISSING")
+}
+
+func TestSecretService_Validation(t *testing.T) {
+	ctx := context.Background()
+	store := newSecretStore(new(MockSecretDAO), testKeyring(t, "", "k1"))
+	assert.ErrorContains(t, store.CreateSecret(ctx, &model.Secret{Name: "bad name"}, "v"), "invalid secret name")
+	assert.ErrorContains(t, store.CreateSecret(ctx, &model.Secret{Name: "auto.mine"}, "v"), "reserved")
+	assert.ErrorContains(t, store.CreateSecret(ctx, &model.Secret{Name: "EMPTY"}, ""), "value is required")
+
+	secretDAO := new(MockSecretDAO)
+	secretDAO.On("ListByNames", ctx, []string{"KEY"}).Return([]model.Secret{}, nil)
+	unconfigured := newSecretStore(secretDAO, nil)
+	assert.ErrorIs(t, unconfigured.CreateSecret(ctx, &model.Secret{Name: "KEY"}, "value"), ErrSecretsNotConfigured)
+	_, err := unconfigured.Resolve(ctx, []string{"KEY"})
+	assert.ErrorIs(t, err, ErrSecretsNotConfigured)
+}
+
+func TestSecretService_RedactsCurrentValues(t *testing.T) {
+	ctx := context.Background()
+	keyring := testKeyring(t, "", "k1")
+	secretDAO := new(MockSecretDAO)
+	store := newSecretStore(secretDAO, keyring)
+	stored := sealedSecret(t, keyring, 1, "REDACT_KEY", "first-value")
+	secretDAO.On("ListByNames", ctx, []string{"REDACT_KEY"}).Return([]model.Secret{stored}, nil)
+	secretDAO.On("GetByID", ctx, uint(1)).Return(&stored, nil)
+	secretDAO.On("Update", ctx, mock.AnythingOfType("*model.Secret")).Return(nil)
+	secretDAO.On("Delete", ctx, uint(1)).Return(nil)
+
+	_, err := store.Resolve(ctx, []string{"REDACT_KEY"})
+	require.NoError(t, err)
+	assert.Equal(t, logger.Redacted, logger.DefaultRedactor.Redact("first-value"))
+
+	// 更新后只脱敏新取值，删除后不再脱敏
+	second := "second-value"
+	require.NoError(t, store.UpdateSecret(ctx, &model.Secret{ID: 1}, &second))
+	assert.Equal(t, "first-value "+logger.Redacted, logger.DefaultRedactor.Redact("first-value second-value"))
+	require.NoError(t, store.DeleteSecret(ctx, 1))
+	assert.Equal(t, "second-value", logger.DefaultRedactor.Redact("second-value"))
+}
+
+func TestSecretService_RotateKeys(t *testing.T) {
+	ctx := context.Background()
+	old := testKeyring(t, "", "k1")
+	a := sealedSecret(t, old, 1, "A", "value-a")
+	rotated := testKeyring(t, "k2", "k1", "k2")
+	b := sealedSecret(t, rotated, 2, "B", "value-b")
+
+	secretDAO := new(MockSecretDAO)
+	store := newSecretStore(secretDAO, rotated)
+	secretDAO.On("List", ctx).Return([]model.Secret{a, b}, nil)
+	secretDAO.On("UpdateKeys", ctx, mock.MatchedBy(func(secrets []model.Secret) bool {
+		return len(secrets) == 1 && secrets[0].ID == 1 && secrets[0].KeyID == "k2"
+	})).Run(func(args mock.Arguments) {
+		a = args.Get(1).([]model.Secret)[0]
+	}).Return(nil)
+
+	count, err := store.RotateKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "secrets already wrapped by the active key are skipped")
+
+	// 轮换后以新主密钥包装的数据密钥仍可解密
+	secretDAO.On("ListByNames", ctx, []string{"A"}).Return([]model.Secret{a}, nil)
+	values, err := newSecretStore(secretDAO, rotated).Resolve(ctx, []string{"A"})
+	require.NoError(t, err)
+	assert.Equal(t, "value-a", values["A"])
+}
+
+func TestAPIExecutor_ResolvesAndRedactsSecrets(t *testing.T) {
+	ctx := context.Background()
+	keyring := testKeyring(t, "", "k1")
+	secretDAO := new(MockSecretDAO)
+	secretDAO.On("ListByNames", ctx, []string{"TRACE", "PET_KEY", "PET_TOKEN"}).Return([]model.Secret{
+		sealedSecret(t, keyring, 1, "TRACE", "trace-value"),
+		sealedSecret(t, keyring, 2, "PET_KEY", "key-value"),
+		sealedSecret(t, keyring, 3, "PET_TOKEN", "token-value"),
+	}, nil)
+	client := new(MockHTTPClient)
+	executor := NewAPIExecutor(client, newSecretStore(secretDAO, keyring))
+
+	endpoint := &model.APIEndpoint{
+		Path:       "/pets",
+		Method:     "GET",
+		Parameters: model.APIParameters{{Name: "trace", In: "query", Value: "{{secret:TRACE}}"}},
+		Headers:    model.StringMap{"X-API-Key": "{{ secret:PET_KEY }}"},
+	}
+	auth := &model.AuthProfile{Type: model.AuthTypeBearer, Token: "{{secret:PET_TOKEN}}"}
+	client.On("DoRequest", ctx, "GET", "http://api/pets?trace=trace-value", map[string]string{
+		"X-API-Key":     "key-value",
+		"Authorization": "Bearer token-value",
+	}, nil).Return(&httpclient.Response{
+		StatusCode: 200,
+		Headers:    map[string][]string{"X-Echo": {"key-value"}},
+		Body:       `{"authorization": "Bearer token-value"}`,
+		URL:        "http://api/pets?trace=trace-value",
+	}, nil)
+
+	resp, err := executor.Execute(ctx, endpoint, "http://api", auth)
+	require.NoError(t, err)
+	assert.Equal(t, `{"authorization": "Bearer ******"}`, resp.Body)
+	assert.Equal(t, "******", resp.Headers.Get("X-Echo"))
+	assert.Equal(t, "http://api/pets?trace=******", resp.URL)
+	assert.Equal(t, "{{ secret:PET_KEY }}", endpoint.Headers["X-API-Key"], "the endpoint is not modified")
+	assert.Equal(t, "{{secret:PET_TOKEN}}", auth.Token, "the profile is not modified")
+}
+
+func TestAPIExecutor_SecretsNotConfigured(t *testing.T) {
+	executor := NewAPIExecutor(new(MockHTTPClient), nil)
+	endpoint := &model.APIEndpoint{Path: "/pets", Method: "GET", Headers: model.StringMap{"X-API-Key": "{{secret:PET_KEY}}"}}
+
+	_, err := executor.Execute(context.Background(), endpoint, "http://api", nil)
+	assert.ErrorIs(t, err, ErrSecretsNotConfigured)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/utils/diff"
+)
+
+// 重新导入时存在影响已发布工具的不兼容变更时的处理策略
+const (
+	BreakingChangePolicyBlock = "block" // 拒绝导入，需要强制导入时才应用
+	BreakingChangePolicyWarn  = "warn"  // 照常导入，在导入结果中返回不兼容变更
+)
+
+// BreakingChangeError 表示重新导入因不兼容变更被拒绝
+type BreakingChangeError struct {
+	Servers []string      `json:"affected_servers"`
+	Changes []diff.Change `json:"breaking_changes"`
+}
+
+func (e *BreakingChangeError) Error() string {
+	return fmt.Sprintf("%d breaking changes affect tools of mcp servers %s, re-import with force to apply them",
+		len(e.Changes), strings.Join(e.Servers, ", "))
+}
+
+// breakingChanges 分析 target 当前内容到 parsed 的不兼容变更，只保留涉及已被 MCP Server 暴露为工具的接口的变更，
+// 返回受影响的 server 名称与变更。文档未被任何 server 使用或内容未变化时返回 nil
+func (s *swaggerService) breakingChanges(target, parsed *model.SwaggerDocument, existing []model.APIEndpoint, servers []model.MCPServer) ([]string, []diff.Change, error) {
+	if target.Content == "" || target.Checksum == parsed.Checksum || len(existing) == 0 || len(servers) == 0 {
+		return nil, nil, nil
+	}
+
+	base, err := s.specParser.ParseFromData([]byte(target.Content))
+	if err != nil {
+		log.Warnf("parse previous content of swagger document %d failed, breaking changes are not detected: %v", target.ID, err)
+		return nil, nil, nil
+	}
+	next, err := s.specParser.ParseFromData([]byte(parsed.Content))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 每个已暴露为工具的接口对应的 server
+	serversByEndpoint := make(map[uint][]string)
+	for _, server := range servers {
+		for _, tool := range server.Tools {
+			serversByEndpoint[tool.EndpointID] = append(serversByEndpoint[tool.EndpointID], server.Name)
+		}
+	}
+	var published []model.APIEndpoint
+	for i := range existing {
+		if len(serversByEndpoint[existing[i].ID]) > 0 {
+			published = append(published, existing[i])
+		}
+	}
+
+	affected := make(map[string]bool)
+	var changes []diff.Change
+	for _, change := range diff.Analyze(base, next).Breaking() {
+		endpoint := findPublished(published, change.Operation)
+		if endpoint == nil {
+			continue
+		}
+		changes = append(changes, change)
+		for _, name := range serversByEndpoint[endpoint.ID] {
+			affected[name] = true
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil, nil
+	}
+	names := make([]string, 0, len(affected))
+	for name := range affected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, changes, nil
+}
+
+// findPublished 按 operationId 或 method+path 查找变更所在的接口
+func findPublished(published []model.APIEndpoint, op diff.Operation) *model.APIEndpoint {
+	for i := range published {
+		if op.OperationID != "" && published[i].OperationID == op.OperationID {
+			return &published[i]
+		}
+	}
+	for i := range published {
+		if published[i].Method == op.Method && published[i].Path == op.Path {
+			return &published[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/utils/diff"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newPublishedTestSwaggerService 构造文档 7 以 petsV1 导入、endpointID 对应的接口被 pets-mcp 暴露为工具的 swaggerService
+func newPublishedTestSwaggerService(t *testing.T, endpointID uint) (*swaggerService, *MockSwaggerDocumentDAO, *model.SwaggerDocument) {
+	mockDAO := new(MockAPIEndpointDAO)
+	mockDocumentDAO := new(MockSwaggerDocumentDAO)
+	service := newURLTestSwaggerService(mockDAO, mockDocumentDAO)
+
+	ctx := context.Background()
+	target := &model.SwaggerDocument{ID: 7, Title: "Pets", Content: petsV1, Checksum: checksum([]byte(petsV1))}
+	mockDocumentDAO.On("GetByID", ctx, uint(7)).Return(target, nil)
+	mockDocumentDAO.On("Reimport", ctx, uint(7)).Return(&dao.ImportState{
+		Document:  target,
+		Endpoints: importedEndpoints(t, service, petsV1),
+		Servers:   []model.MCPServer{{ID: 3, Name: "pets-mcp", Tools: []model.MCPServerTool{{ServerID: 3, EndpointID: endpointID}}}},
+	}, nil)
+	return service, mockDocumentDAO, target
+}
+
+func TestSwaggerService_ReimportDocument_BlocksBreakingChanges(t *testing.T) {
+	// deletePet 被暴露为工具，v2 中删除了该接口
+	service, mockDocumentDAO, _ := newPublishedTestSwaggerService(t, 2)
+
+	_, err := service.ReimportDocument(context.Background(), 7, []byte(petsV2), false, false)
+	var breaking *BreakingChangeError
+	require.ErrorAs(t, err, &breaking)
+	assert.Equal(t, []string{"pets-mcp"}, breaking.Servers)
+	require.Len(t, breaking.Changes, 1)
+	assert.Equal(t, diff.KindOperationRemoved, breaking.Changes[0].Kind)
+	assert.Equal(t, "deletePet", breaking.Changes[0].Operation.OperationID)
+	mockDocumentDAO.AssertNotCalled(t, "SaveImport", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSwaggerService_ReimportDocument_ForceBreakingChanges(t *testing.T) {
+	service, mockDocumentDAO, target := newPublishedTestSwaggerService(t, 2)
+	mockDocumentDAO.On("SaveImport", mock.Anything, target, mock.AnythingOfType("*dao.EndpointChangeSet"), mock.Anything).
+		Run(saveImport(7)).Return(nil)
+
+	// 强制导入时应用变更，并在结果中返回不兼容变更
+	result, err := service.ReimportDocument(context.Background(), 7, []byte(petsV2), false, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pets-mcp"}, result.AffectedServers)
+	require.Len(t, result.BreakingChanges, 1)
+	assert.Equal(t, "2.0", result.Document.Version)
+}
+
+func TestSwaggerService_ReimportDocument_WarnsOnBreakingChanges(t *testing.T) {
+	service, mockDocumentDAO, target := newPublishedTestSwaggerService(t, 2)
+	service.breakingPolicy = BreakingChangePolicyWarn
+	mockDocumentDAO.On("SaveImport", mock.Anything, target, mock.AnythingOfType("*dao.EndpointChangeSet"), mock.Anything).
+		Run(saveImport(7)).Return(nil)
+
+	result, err := service.ReimportDocument(context.Background(), 7, []byte(petsV2), false, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pets-mcp"}, result.AffectedServers)
+	require.Len(t, result.BreakingChanges, 1)
+}
+
+func TestSwaggerService_SyncDocument_BlocksBreakingChanges(t *testing.T) {
+	source := newSpecSource(t, petsV2)
+	service, mockDocumentDAO, target := newPublishedTestSwaggerService(t, 2)
+	target.SourceURL = source.URL
+	mockDocumentDAO.On("Update", mock.Anything, target).Return(nil)
+
+	// 同步被拒绝时保留原有内容，并在文档上记录失败原因
+	changed, err := service.SyncDocument(context.Background(), 7)
+	var breaking *BreakingChangeError
+	require.ErrorAs(t, err, &breaking)
+	assert.False(t, changed)
+	assert.Contains(t, target.SyncError, "breaking changes affect tools of mcp servers pets-mcp")
+	assert.NotNil(t, target.LastSyncedAt)
+	assert.Equal(t, checksum([]byte(petsV1)), target.Checksum)
+	mockDocumentDAO.AssertCalled(t, "Update", mock.Anything, target)
+	mockDocumentDAO.AssertNotCalled(t, "SaveImport", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSwaggerService_ReimportDocument_IgnoresUnpublishedChanges(t *testing.T) {
+	// 只有 listPets 被暴露为工具，其路径变化与新增的参数上限不影响调用方
+	service, mockDocumentDAO, target := newPublishedTestSwaggerService(t, 1)
+	mockDocumentDAO.On("SaveImport", mock.Anything, target, mock.AnythingOfType("*dao.EndpointChangeSet"), mock.Anything).
+		Run(saveImport(7)).Return(nil)
+
+	result, err := service.ReimportDocument(context.Background(), 7, []byte(petsV2), false, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.BreakingChanges)
+	assert.Empty(t, result.AffectedServers)
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+)
+
+// 批量操作的类型
+const (
+	BulkActionDelete     = "delete"      // 删除接口
+	BulkActionAddTags    = "add_tags"    // 添加标签
+	BulkActionRemoveTags = "remove_tags" // 移除标签
+	BulkActionEnable     = "enable"      // 启用接口
+	BulkActionDisable    = "disable"     // 禁用接口，不再作为 MCP 工具暴露
+	BulkActionPatch      = "patch"       // 修改请求头或参数默认值
+)
+
+// BulkEndpointRequest 为批量操作的参数，IDs 与 Filter 二选一
+type BulkEndpointRequest struct {
+	IDs    []uint              `json:"ids"`    // 接口 ID
+	Filter *BulkEndpointFilter `json:"filter"` // 过滤条件，至少包含一项
+	Action string              `json:"action"` // delete、add_tags、remove_tags、enable、disable、patch
+
+	Tags              []string          `json:"tags"`               // add_tags、remove_tags 的标签
+	Headers           map[string]string `json:"headers"`            // patch 设置的请求头，值为空时删除该请求头
+	ParameterDefaults map[string]string `json:"parameter_defaults"` // patch 按参数名设置的默认值，接口没有该参数时忽略
+
+	// ContinueOnError 为 false 时，任一接口不存在或失败都会整体回滚；为 true 时只跳过失败的接口
+	ContinueOnError bool `json:"continue_on_error"`
+}
+
+// BulkEndpointFilter 为批量操作按条件选择接口时的过滤条件，含义与接口列表的查询参数相同
+type BulkEndpointFilter struct {
+	SwaggerID  uint   `json:"swagger_id"`
+	Method     string `json:"method"`
+	Tag        string `json:"tag"`
+	PathPrefix string `json:"path_prefix"`
+	Keyword    string `json:"q"`
+}
+
+func (f *BulkEndpointFilter) query() dao.EndpointQuery {
+	return dao.EndpointQuery{SwaggerID: f.SwaggerID, Method: f.Method, Tag: f.Tag, PathPrefix: f.PathPrefix, Keyword: f.Keyword}
+}
+
+// BulkReport 为批量操作的结果
+type BulkReport struct {
+	Action     string               `json:"action"`
+	Matched    int                  `json:"matched"`     // 选中的接口数
+	Succeeded  int                  `json:"succeeded"`   // 修改或删除的接口数
+	Unchanged  int                  `json:"unchanged"`   // 无需修改的接口数
+	Failed     int                  `json:"failed"`      // 不存在或失败的接口数
+	RolledBack bool                 `json:"rolled_back"` // 是否因失败而整体回滚
+	Items      []dao.BulkItemResult `json:"items"`
+}
+
+// Validate 检查批量操作的参数
+func (r *BulkEndpointRequest) Validate() error {
+	if (len(r.IDs) == 0) == (r.Filter == nil) {
+		return fmt.Errorf("exactly one of ids and filter is required")
+	}
+	if r.Filter != nil && *r.Filter == (BulkEndpointFilter{}) {
+		return fmt.Errorf("filter must contain at least one condition")
+	}
+	switch r.Action {
+	case BulkActionDelete, BulkActionEnable, BulkActionDisable:
+	case BulkActionAddTags, BulkActionRemoveTags:
+		if len(r.Tags) == 0 {
+			return fmt.Errorf("tags are required for %s", r.Action)
+		}
+		for _, tag := range r.Tags {
+			if strings.TrimSpace(tag) == "" || strings.Contains(tag, ",") {
+				return fmt.Errorf("invalid tag: %q", tag)
+			}
+		}
+	case BulkActionPatch:
+		if len(r.Headers) == 0 && len(r.ParameterDefaults) == 0 {
+			return fmt.Errorf("headers or parameter_defaults are required for patch")
+		}
+		for name := range r.Headers {
+			if strings.TrimSpace(name) == "" {
+				return fmt.Errorf("header name must not be empty")
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported bulk action: %s", r.Action)
+	}
+	return nil
+}
+
+// BulkEndpoints 在同一事务中对选中的接口执行批量操作，返回逐项结果
+func (s *swaggerService) BulkEndpoints(ctx context.Context, req *BulkEndpointRequest) (*BulkReport, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	ids := uniqueIDs(req.IDs)
+	if req.Filter != nil {
+		var err error
+		if ids, err = s.dao.ListIDs(ctx, req.Filter.query()); err != nil {
+			return nil, err
+		}
+	}
+
+	items, err := s.dao.Bulk(ctx, ids, req.ContinueOnError, bulkFunc(req))
+	if err != nil {
+		return nil, err
+	}
+	report := &BulkReport{Action: req.Action, Matched: len(ids), Items: items}
+	for _, item := range items {
+		switch item.Status {
+		case dao.BulkStatusUpdated, dao.BulkStatusDeleted:
+			report.Succeeded++
+		case dao.BulkStatusUnchanged:
+			report.Unchanged++
+		case dao.BulkStatusNotFound, dao.BulkStatusFailed:
+			report.Failed++
+		case dao.BulkStatusRolledBack:
+			report.RolledBack = true
+		}
+	}
+	if report.Failed > 0 && !req.ContinueOnError {
+		report.RolledBack = true
+	}
+	return report, nil
+}
+
+// bulkFunc 返回对单个接口执行 req 的函数
+func bulkFunc(req *BulkEndpointRequest) dao.BulkFunc {
+	return func(endpoint *model.APIEndpoint) (dao.BulkOp, error) {
+		changed := false
+		switch req.Action {
+		case BulkActionDelete:
+			return dao.BulkDelete, nil
+		case BulkActionEnable, BulkActionDisable:
+			disabled := req.Action == BulkActionDisable
+			changed = endpoint.Disabled != disabled
+			endpoint.Disabled = disabled
+		case BulkActionAddTags, BulkActionRemoveTags:
+			tags := editTags(endpoint.Tags, req.Tags, req.Action == BulkActionAddTags)
+			changed = tags != endpoint.Tags
+			endpoint.Tags = tags
+		case BulkActionPatch:
+			changed = patchHeaders(endpoint, req.Headers)
+			changed = patchParameterDefaults(endpoint, req.ParameterDefaults) || changed
+		}
+		if !changed {
+			return dao.BulkSkip, nil
+		}
+		return dao.BulkUpdate, nil
+	}
+}
+
+// editTags 在以逗号分隔的 tags 中添加或移除 edits，保持原有顺序
+func editTags(tags string, edits []string, add bool) string {
+	var current []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			current = append(current, tag)
+		}
+	}
+	has := func(list []string, tag string) bool {
+		for _, t := range list {
+			if t == tag {
+				return true
+			}
+		}
+		return false
+	}
+	var out []string
+	if add {
+		out = current
+		for _, tag := range edits {
+			if tag = strings.TrimSpace(tag); !has(out, tag) {
+				out = append(out, tag)
+			}
+		}
+	} else {
+		trimmed := make([]string, len(edits))
+		for i, tag := range edits {
+			trimmed[i] = strings.TrimSpace(tag)
+		}
+		for _, tag := range current {
+			if !has(trimmed, tag) {
+				out = append(out, tag)
+			}
+		}
+	}
+	if strings.Join(out, ",") == strings.Join(current, ",") {
+		// 无变化时保留原始写法
+		return tags
+	}
+	return strings.Join(out, ",")
+}
+
+// patchHeaders 设置接口的请求头，值为空时删除，返回是否有变化
+func patchHeaders(endpoint *model.APIEndpoint, headers map[string]string) bool {
+	changed := false
+	for name, value := range headers {
+		old, ok := endpoint.Headers[name]
+		if value == "" {
+			if ok {
+				delete(endpoint.Headers, name)
+				changed = true
+			}
+			continue
+		}
+		if !ok || old != value {
+			if endpoint.Headers == nil {
+				endpoint.Headers = model.StringMap{}
+			}
+			endpoint.Headers[name] = value
+			changed = true
+		}
+	}
+	return changed
+}
+
+// patchParameterDefaults 按参数名设置参数默认值，同名参数（如 query 与 header）都会被设置，返回是否有变化
+func patchParameterDefaults(endpoint *model.APIEndpoint, defaults map[string]string) bool {
+	changed := false
+	for i := range endpoint.Parameters {
+		value, ok := defaults[endpoint.Parameters[i].Name]
+		if ok && endpoint.Parameters[i].Value != value {
+			endpoint.Parameters[i].Value = value
+			changed = true
+		}
+	}
+	return changed
+}
+
+// uniqueIDs 去除重复的 ID，保持原有顺序
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	out := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
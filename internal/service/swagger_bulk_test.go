@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkEndpointRequest_Validate(t *testing.T) {
+	cases := []struct {
+		name string
+		req  BulkEndpointRequest
+		ok   bool
+	}{
+		{"ids", BulkEndpointRequest{IDs: []uint{1}, Action: BulkActionDelete}, true},
+		{"filter", BulkEndpointRequest{Filter: &BulkEndpointFilter{Tag: "pets"}, Action: BulkActionDisable}, true},
+		{"no selection", BulkEndpointRequest{Action: BulkActionDelete}, false},
+		{"both selections", BulkEndpointRequest{IDs: []uint{1}, Filter: &BulkEndpointFilter{Tag: "pets"}, Action: BulkActionDelete}, false},
+		{"empty filter", BulkEndpointRequest{Filter: &BulkEndpointFilter{}, Action: BulkActionDelete}, false},
+		{"unknown action", BulkEndpointRequest{IDs: []uint{1}, Action: "rename"}, false},
+		{"tags missing", BulkEndpointRequest{IDs: []uint{1}, Action: BulkActionAddTags}, false},
+		{"tag with comma", BulkEndpointRequest{IDs: []uint{1}, Action: BulkActionAddTags, Tags: []string{"a,b"}}, false},
+		{"empty patch", BulkEndpointRequest{IDs: []uint{1}, Action: BulkActionPatch}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.req.Validate()
+			if tc.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestBulkFunc(t *testing.T) {
+	endpoint := func() *model.APIEndpoint {
+		return &model.APIEndpoint{
+			Tags:    "pets,admin",
+			Headers: model.StringMap{"X-Tenant": "a", "X-Debug": "1"},
+			Parameters: model.APIParameters{
+				{Name: "limit", In: "query", Value: "10"},
+				{Name: "id", In: "path"},
+			},
+		}
+	}
+
+	e := endpoint()
+	op, err := bulkFunc(&BulkEndpointRequest{Action: BulkActionAddTags, Tags: []string{"admin", "beta"}})(e)
+	require.NoError(t, err)
+	assert.Equal(t, dao.BulkUpdate, op)
+	assert.Equal(t, "pets,admin,beta", e.Tags)
+
+	e = endpoint()
+	op, _ = bulkFunc(&BulkEndpointRequest{Action: BulkActionRemoveTags, Tags: []string{"pets"}})(e)
+	assert.Equal(t, dao.BulkUpdate, op)
+	assert.Equal(t, "admin", e.Tags)
+
+	e = endpoint()
+	op, _ = bulkFunc(&BulkEndpointRequest{Action: BulkActionRemoveTags, Tags: []string{"beta"}})(e)
+	assert.Equal(t, dao.BulkSkip, op)
+	assert.Equal(t, "pets,admin", e.Tags)
+
+	e = endpoint()
+	op, _ = bulkFunc(&BulkEndpointRequest{Action: BulkActionDisable})(e)
+	assert.Equal(t, dao.BulkUpdate, op)
+	assert.True(t, e.Disabled)
+	op, _ = bulkFunc(&BulkEndpointRequest{Action: BulkActionDisable})(e)
+	assert.Equal(t, dao.BulkSkip, op)
+
+	e = endpoint()
+	op, _ = bulkFunc(&BulkEndpointRequest{
+		Action:            BulkActionPatch,
+		Headers:           map[string]string{"X-Tenant": "b", "X-Debug": ""},
+		ParameterDefaults: map[string]string{"limit": "50", "offset": "0"},
+	})(e)
+	assert.Equal(t, dao.BulkUpdate, op)
+	assert.Equal(t, model.StringMap{"X-Tenant": "b"}, e.Headers)
+	assert.Equal(t, "50", e.Parameters[0].Value)
+
+	// 接口没有要修改的参数时不做修改
+	e = endpoint()
+	op, _ = bulkFunc(&BulkEndpointRequest{Action: BulkActionPatch, ParameterDefaults: map[string]string{"offset": "0"}})(e)
+	assert.Equal(t, dao.BulkSkip, op)
+}
+
+func TestSwaggerService_BulkEndpoints(t *testing.T) {
+	mockDAO := new(MockAPIEndpointDAO)
+	service := newTestSwaggerService(new(MockSwaggerParser), mockDAO, new(MockHTTPClient))
+	ctx := context.Background()
+
+	mockDAO.On("ListIDs", ctx, dao.EndpointQuery{SwaggerID: 1, Tag: "pets"}).Return([]uint{1, 2, 3}, nil)
+	mockDAO.On("Bulk", ctx, []uint{1, 2, 3}, false, mock.AnythingOfType("dao.BulkFunc")).Return([]dao.BulkItemResult{
+		{ID: 1, Status: dao.BulkStatusRolledBack},
+		{ID: 2, Status: dao.BulkStatusUnchanged},
+		{ID: 3, Status: dao.BulkStatusNotFound, Error: "endpoint not found"},
+	}, nil)
+
+	report, err := service.BulkEndpoints(ctx, &BulkEndpointRequest{
+		Filter: &BulkEndpointFilter{SwaggerID: 1, Tag: "pets"},
+		Action: BulkActionDisable,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Matched)
+	assert.Equal(t, 0, report.Succeeded)
+	assert.Equal(t, 1, report.Unchanged)
+	assert.Equal(t, 1, report.Failed)
+	assert.True(t, report.RolledBack)
+}
+
+func TestSwaggerService_BulkEndpoints_DeduplicatesIDs(t *testing.T) {
+	mockDAO := new(MockAPIEndpointDAO)
+	service := newTestSwaggerService(new(MockSwaggerParser), mockDAO, new(MockHTTPClient))
+	ctx := context.Background()
+
+	mockDAO.On("Bulk", ctx, []uint{2, 1}, true, mock.AnythingOfType("dao.BulkFunc")).Return([]dao.BulkItemResult{
+		{ID: 2, Status: dao.BulkStatusDeleted},
+		{ID: 1, Status: dao.BulkStatusDeleted},
+	}, nil)
+
+	report, err := service.BulkEndpoints(ctx, &BulkEndpointRequest{IDs: []uint{2, 1, 2}, Action: BulkActionDelete, ContinueOnError: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Succeeded)
+	assert.False(t, report.RolledBack)
+	mockDAO.AssertExpectations(t)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"mcp-manager/internal/model"
+)
+
+// newOpenAPI3Document 从 OpenAPI 3 文档中提取 SwaggerDocument 元信息
+func newOpenAPI3Document(spec *openapi3.T) *model.SwaggerDocument {
+	doc := &model.SwaggerDocument{
+		SpecFormat: model.SpecFormatOpenAPI3,
+		Servers:    model.StringList{},
+	}
+	if spec.Info != nil {
+		doc.Title = spec.Info.Title
+		doc.Version = spec.Info.Version
+	}
+	for _, server := range spec.Servers {
+		if server != nil && server.URL != "" {
+			doc.Servers = append(doc.Servers, server.URL)
+		}
+	}
+	return doc
+}
+
+// newSwagger2Document 从 Swagger 2.0 文档中提取 SwaggerDocument 元信息
+// servers 由 schemes + host + basePath 拼接而成，未声明 schemes 时默认为 http
+func newSwagger2Document(spec *openapi2.T) *model.SwaggerDocument {
+	doc := &model.SwaggerDocument{
+		SpecFormat: model.SpecFormatSwagger2,
+		Title:      spec.Info.Title,
+		Version:    spec.Info.Version,
+		Servers:    model.StringList{},
+	}
+	if spec.Host == "" {
+		return doc
+	}
+	schemes := spec.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"http"}
+	}
+	basePath := strings.TrimSuffix(spec.BasePath, "/")
+	for _, scheme := range schemes {
+		doc.Servers = append(doc.Servers, scheme+"://"+spec.Host+basePath)
+	}
+	return doc
+}
+
+// checksum 计算原始文档内容的 SHA-256 摘要
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
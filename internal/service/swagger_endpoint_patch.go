@@ -0,0 +1,185 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/utils/converter"
+	"mcp-manager/internal/utils/mergepatch"
+)
+
+// ErrVersionRequired 表示部分更新没有携带读取时的版本号
+var ErrVersionRequired = errors.New("version is required, send the ETag in If-Match or version in the patch")
+
+// FieldError 为单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// EndpointValidationError 表示接口的字段校验失败
+type EndpointValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *EndpointValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+	return "invalid endpoint: " + strings.Join(msgs, "; ")
+}
+
+// endpointMethods 为允许的 HTTP 方法
+var endpointMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "HEAD": true, "OPTIONS": true, "TRACE": true,
+}
+
+// parameterLocations 为参数允许的位置，body 为导入时表示请求体的参数
+var parameterLocations = map[string]bool{"path": true, "query": true, "header": true, "cookie": true, converter.ParameterInBody: true}
+
+// PatchAPIEndpoint 以 JSON Merge Patch（RFC 7386）修改接口，version 为读取时的版本号，为 0 时取 patch 中的 version
+// 版本号与数据库中的不同时返回 dao.ErrVersionConflict，字段校验失败时返回 *EndpointValidationError
+func (s *swaggerService) PatchAPIEndpoint(ctx context.Context, id uint, patch []byte, version int64) (*model.APIEndpoint, error) {
+	fields, err := mergepatch.Fields(patch)
+	if err != nil {
+		return nil, &EndpointValidationError{Fields: []FieldError{{Field: "", Message: err.Error()}}}
+	}
+	if raw, ok := fields["version"]; ok {
+		var v int64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, &EndpointValidationError{Fields: []FieldError{{Field: "version", Message: "must be an integer"}}}
+		}
+		if version != 0 && v != version {
+			return nil, &EndpointValidationError{Fields: []FieldError{{Field: "version", Message: "does not match If-Match"}}}
+		}
+		version = v
+	}
+	if version == 0 {
+		return nil, ErrVersionRequired
+	}
+
+	current, err := s.dao.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Version != version {
+		return nil, dao.ErrVersionConflict
+	}
+
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := mergepatch.Apply(doc, patch)
+	if err != nil {
+		return nil, &EndpointValidationError{Fields: []FieldError{{Field: "", Message: err.Error()}}}
+	}
+	var updated model.APIEndpoint
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&updated); err != nil {
+		return nil, &EndpointValidationError{Fields: []FieldError{decodeFieldError(err)}}
+	}
+
+	var invalid []FieldError
+	if updated.ID != current.ID {
+		invalid = append(invalid, FieldError{Field: "id", Message: "is read-only"})
+	}
+	if updated.SwaggerID != current.SwaggerID {
+		invalid = append(invalid, FieldError{Field: "swagger_id", Message: "is read-only"})
+	}
+	// 时间戳由服务端维护，移入回收站只能通过删除接口
+	if !updated.CreatedAt.Equal(current.CreatedAt) {
+		invalid = append(invalid, FieldError{Field: "created_at", Message: "is read-only"})
+	}
+	if !updated.UpdatedAt.Equal(current.UpdatedAt) {
+		invalid = append(invalid, FieldError{Field: "updated_at", Message: "is read-only"})
+	}
+	if updated.DeletedAt.Valid != current.DeletedAt.Valid || !updated.DeletedAt.Time.Equal(current.DeletedAt.Time) {
+		invalid = append(invalid, FieldError{Field: "deleted_at", Message: "is read-only"})
+	}
+	updated.Method = strings.ToUpper(updated.Method)
+	invalid = append(invalid, validateEndpoint(&updated)...)
+	if len(invalid) > 0 {
+		return nil, &EndpointValidationError{Fields: invalid}
+	}
+
+	updated.Version = version
+	if err := s.dao.Update(ctx, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// decodeFieldError 把解码错误转换为字段错误
+func decodeFieldError(err error) FieldError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return FieldError{Field: typeErr.Field, Message: "must be " + typeErr.Type.String()}
+	}
+	// DisallowUnknownFields 的错误形如 json: unknown field "xxx"
+	if msg := err.Error(); strings.HasPrefix(msg, "json: unknown field ") {
+		return FieldError{Field: strings.Trim(strings.TrimPrefix(msg, "json: unknown field "), `"`), Message: "is not a field of the endpoint"}
+	}
+	return FieldError{Field: "", Message: err.Error()}
+}
+
+// validateEndpoint 校验接口的字段，返回所有不合法的字段
+func validateEndpoint(e *model.APIEndpoint) []FieldError {
+	var invalid []FieldError
+	add := func(field, format string, args ...interface{}) {
+		invalid = append(invalid, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	maxLen := func(field, value string, n int) {
+		if utf8.RuneCountInString(value) > n {
+			add(field, "must be at most %d characters", n)
+		}
+	}
+
+	if !endpointMethods[e.Method] {
+		add("method", "must be one of GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS, TRACE")
+	}
+	if !strings.HasPrefix(e.Path, "/") {
+		add("path", "must start with /")
+	} else if strings.IndexFunc(e.Path, unicode.IsSpace) >= 0 {
+		add("path", "must not contain whitespace")
+	}
+	maxLen("path", e.Path, 255)
+	maxLen("summary", e.Summary, 255)
+	maxLen("operation_id", e.OperationID, 64)
+	maxLen("tags", e.Tags, 255)
+
+	for i, p := range e.Parameters {
+		field := fmt.Sprintf("parameters[%d]", i)
+		if strings.TrimSpace(p.Name) == "" {
+			add(field+".name", "must not be empty")
+		}
+		if !parameterLocations[p.In] {
+			add(field+".in", "must be one of path, query, header, cookie, body")
+		}
+	}
+	for name := range e.Headers {
+		if strings.TrimSpace(name) == "" {
+			add("headers", "header name must not be empty")
+		}
+	}
+	if e.Responses != "" && !json.Valid([]byte(e.Responses)) {
+		add("responses", "must be a JSON document")
+	}
+	if len(e.InputSchema) > 0 {
+		var schema map[string]interface{}
+		if err := json.Unmarshal(e.InputSchema, &schema); err != nil {
+			add("input_schema", "must be a JSON object")
+		}
+	}
+	return invalid
+}
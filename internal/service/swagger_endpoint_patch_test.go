@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/utils/converter"
+	"mcp-manager/internal/utils/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newPatchTestSwaggerService 构造接口 1 当前版本为 3 的 swaggerService
+func newPatchTestSwaggerService() (*swaggerService, *MockAPIEndpointDAO) {
+	mockDAO := new(MockAPIEndpointDAO)
+	service := newTestSwaggerService(new(MockSwaggerParser), mockDAO, new(MockHTTPClient))
+	current := *sampleEndpoint
+	current.Headers = model.StringMap{"Content-Type": "application/json", "X-Debug": "1"}
+	current.Version = 3
+	mockDAO.On("GetByID", mock.Anything, uint(1)).Return(&current, nil)
+	return service, mockDAO
+}
+
+func TestSwaggerService_PatchAPIEndpoint(t *testing.T) {
+	service, mockDAO := newPatchTestSwaggerService()
+	mockDAO.On("Update", mock.Anything, mock.AnythingOfType("*model.APIEndpoint")).Return(nil)
+
+	patch := `{"summary": "Renamed", "method": "post", "headers": {"X-Debug": null, "X-Tenant": "acme"}, "description": null}`
+	updated, err := service.PatchAPIEndpoint(context.Background(), 1, []byte(patch), 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Renamed", updated.Summary)
+	assert.Equal(t, "POST", updated.Method)
+	assert.Empty(t, updated.Description)
+	assert.Equal(t, model.StringMap{"Content-Type": "application/json", "X-Tenant": "acme"}, updated.Headers)
+	// 未出现在 patch 中的字段保持不变
+	assert.Equal(t, sampleEndpoint.Path, updated.Path)
+	assert.Equal(t, sampleEndpoint.Parameters, updated.Parameters)
+	assert.Equal(t, int64(3), mockDAO.Calls[1].Arguments.Get(1).(*model.APIEndpoint).Version)
+}
+
+func TestSwaggerService_PatchAPIEndpoint_ImportedBody(t *testing.T) {
+	spec, err := parser.NewSwaggerParser().ParseFromData([]byte(contractSpec))
+	require.NoError(t, err)
+	var current *model.APIEndpoint
+	for _, e := range converter.NewOpenAPI3Converter().ConvertToAPIEndpoint(spec) {
+		if e.Method == "POST" {
+			e.ID, e.Version = 5, 1
+			current = &e
+		}
+	}
+	require.NotNil(t, current)
+	mockDAO := new(MockAPIEndpointDAO)
+	service := newTestSwaggerService(new(MockSwaggerParser), mockDAO, new(MockHTTPClient))
+	mockDAO.On("GetByID", mock.Anything, uint(5)).Return(current, nil)
+	mockDAO.On("Update", mock.Anything, mock.AnythingOfType("*model.APIEndpoint")).Return(nil)
+
+	// 导入时生成的请求体参数不影响修改其他字段
+	updated, err := service.PatchAPIEndpoint(context.Background(), 5, []byte(`{"summary": "Create a pet"}`), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Create a pet", updated.Summary)
+	assert.Equal(t, converter.ParameterInBody, updated.Parameters[len(updated.Parameters)-1].In)
+}
+
+func TestSwaggerService_PatchAPIEndpoint_VersionFromBody(t *testing.T) {
+	service, mockDAO := newPatchTestSwaggerService()
+	mockDAO.On("Update", mock.Anything, mock.AnythingOfType("*model.APIEndpoint")).Return(nil)
+
+	_, err := service.PatchAPIEndpoint(context.Background(), 1, []byte(`{"version": 3, "tags": "a,b"}`), 0)
+	require.NoError(t, err)
+
+	_, err = service.PatchAPIEndpoint(context.Background(), 1, []byte(`{"version": 2}`), 3)
+	var invalid *EndpointValidationError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "version", invalid.Fields[0].Field)
+}
+
+func TestSwaggerService_PatchAPIEndpoint_Conflict(t *testing.T) {
+	service, mockDAO := newPatchTestSwaggerService()
+
+	_, err := service.PatchAPIEndpoint(context.Background(), 1, []byte(`{"summary": "x"}`), 2)
+	assert.ErrorIs(t, err, dao.ErrVersionConflict)
+
+	_, err = service.PatchAPIEndpoint(context.Background(), 1, []byte(`{"summary": "x"}`), 0)
+	assert.ErrorIs(t, err, ErrVersionRequired)
+	mockDAO.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestSwaggerService_PatchAPIEndpoint_Validation(t *testing.T) {
+	cases := []struct {
+		patch  string
+		fields []string
+	}{
+		{`{"method": "FETCH", "path": "users"}`, []string{"method", "path"}},
+		{`{"id": 9, "swagger_id": 2}`, []string{"id", "swagger_id"}},
+		{`{"created_at": "2020-01-01T00:00:00Z", "updated_at": "2020-01-01T00:00:00Z"}`, []string{"created_at", "updated_at"}},
+		{`{"deleted_at": "2020-01-01T00:00:00Z"}`, []string{"deleted_at"}},
+		{`{"parameters": [{"name": "", "in": "form"}]}`, []string{"parameters[0].name", "parameters[0].in"}},
+		{`{"summary": 1}`, []string{"summary"}},
+		{`{"owner": "me"}`, []string{"owner"}},
+		{`{"responses": "{"}`, []string{"responses"}},
+		{`{"input_schema": [1]}`, []string{"input_schema"}},
+		{`[1]`, []string{""}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.patch, func(t *testing.T) {
+			service, mockDAO := newPatchTestSwaggerService()
+			_, err := service.PatchAPIEndpoint(context.Background(), 1, []byte(tc.patch), 3)
+			var invalid *EndpointValidationError
+			require.ErrorAs(t, err, &invalid)
+			var fields []string
+			for _, f := range invalid.Fields {
+				fields = append(fields, f.Field)
+			}
+			assert.Equal(t, tc.fields, fields)
+			mockDAO.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/utils/diff"
+)
+
+// OperationRef 标识导入报告中的一个接口
+type OperationRef struct {
+	ID          uint   `json:"id,omitempty"`
+	OperationID string `json:"operation_id,omitempty"`
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+}
+
+// ImportReport 描述一次导入对文档接口集合的变更
+type ImportReport struct {
+	Added     []OperationRef `json:"added"`
+	Removed   []OperationRef `json:"removed"`
+	Changed   []OperationRef `json:"changed"`
+	Unchanged int            `json:"unchanged"`
+}
+
+// ImportResult 为一次导入的结果
+type ImportResult struct {
+	Document  *model.SwaggerDocument `json:"document"`
+	Endpoints []model.APIEndpoint    `json:"endpoints"`
+	Report    ImportReport           `json:"report"`
+	// Revision 为本次导入记录的修订号，内容与最新修订相同时为 0
+	Revision int `json:"revision,omitempty"`
+	// BreakingChanges 为涉及已发布工具的不兼容变更，AffectedServers 为使用这些工具的 MCP Server
+	BreakingChanges []diff.Change `json:"breaking_changes,omitempty"`
+	AffectedServers []string      `json:"affected_servers,omitempty"`
+}
+
+// importOptions 控制一次导入的行为
+type importOptions struct {
+	overwrite      bool   // 为 true 时手动修改过的接口字段也以文档内容为准
+	source         string // 记录到修订上的导入来源
+	createdBy      string // 记录到修订上的导入人
+	rolledBackFrom int    // 回滚时恢复的修订号
+	force          bool   // 为 true 时即使策略为 block 也应用不兼容变更
+	// prepare 在导入事务中修改重新读取的目标文档，如记录同步时间
+	prepare func(doc *model.SwaggerDocument)
+}
+
+// newOperationRef 返回接口在导入报告中的标识
+func newOperationRef(e *model.APIEndpoint) OperationRef {
+	return OperationRef{ID: e.ID, OperationID: e.OperationID, Method: e.Method, Path: e.Path}
+}
+
+// findExisting 按来源地址查找重新导入的目标文档，未找到时返回 nil
+// 不按标题匹配：不同服务的文档常使用相同的默认标题，误匹配会覆盖其他文档的接口
+func (s *swaggerService) findExisting(ctx context.Context, sourceURL string) (*model.SwaggerDocument, error) {
+	if sourceURL == "" {
+		return nil, nil
+	}
+	doc, err := s.documentDAO.GetBySourceURL(ctx, sourceURL)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return doc, err
+}
+
+// applyImport 将解析出的文档与接口保存到 target 上，target 为 nil 时新建文档
+// 已有接口按 operationId 或 method+path 匹配，匹配到的接口保留手动修改过的字段（opts.overwrite 为 true 时除外）。
+// 重新导入时读取已有接口与修订、检查不兼容变更以及保存文档、接口变更和修订都在同一事务中完成
+func (s *swaggerService) applyImport(ctx context.Context, target, parsed *model.SwaggerDocument, endpoints []model.APIEndpoint, opts importOptions) (*ImportResult, error) {
+	revision := &model.SwaggerDocumentRevision{
+		Title:          parsed.Title,
+		Version:        parsed.Version,
+		SpecFormat:     parsed.SpecFormat,
+		Content:        parsed.Content,
+		Checksum:       parsed.Checksum,
+		Source:         opts.source,
+		RolledBackFrom: opts.rolledBackFrom,
+		CreatedBy:      opts.createdBy,
+	}
+	if target == nil {
+		changes := &dao.EndpointChangeSet{Create: endpoints}
+		if err := s.documentDAO.SaveImport(ctx, parsed, changes, revision); err != nil {
+			return nil, err
+		}
+		report := ImportReport{}
+		for i := range changes.Create {
+			report.Added = append(report.Added, newOperationRef(&changes.Create[i]))
+		}
+		return &ImportResult{Document: parsed, Endpoints: changes.Create, Report: report, Revision: revision.Revision}, nil
+	}
+
+	var (
+		result  *ImportResult
+		changes *dao.EndpointChangeSet
+		saved   *model.SwaggerDocumentRevision
+	)
+	err := s.documentDAO.Reimport(ctx, target.ID, func(state *dao.ImportState) (*dao.ImportPlan, error) {
+		doc := state.Document
+		// 文档已被 MCP Server 使用时检查不兼容变更，按策略拒绝或告警
+		affected, breaking, err := s.breakingChanges(doc, parsed, state.Endpoints, state.Servers)
+		if err != nil {
+			return nil, err
+		}
+		if len(breaking) > 0 {
+			if s.breakingPolicy == BreakingChangePolicyBlock && !opts.force {
+				return nil, &BreakingChangeError{Servers: affected, Changes: breaking}
+			}
+			log.Warnf("swagger document %d re-imported with %d breaking changes affecting mcp servers %v", doc.ID, len(breaking), affected)
+		}
+
+		var baseline *model.SwaggerDocumentRevision
+		baseline, saved = nextRevision(doc, state.Latest, revision)
+		// 上一次导入的内容用于判断已有接口的哪些字段被手动修改过
+		var previous []model.APIEndpoint
+		if doc.Content != "" {
+			if _, previous, err = s.parseDocument([]byte(doc.Content)); err != nil {
+				log.Warnf("parse previous content of swagger document %d failed, manual edits are not detected: %v", doc.ID, err)
+				previous = nil
+			}
+		}
+
+		var (
+			report *ImportReport
+			merged []model.APIEndpoint
+		)
+		changes, report, merged = diffEndpoints(state.Endpoints, previous, endpoints, opts.overwrite)
+		doc.Title = parsed.Title
+		doc.Version = parsed.Version
+		doc.SpecFormat = parsed.SpecFormat
+		doc.Servers = parsed.Servers
+		doc.Content = parsed.Content
+		doc.Checksum = parsed.Checksum
+		if opts.prepare != nil {
+			opts.prepare(doc)
+		}
+		result = &ImportResult{Document: doc, Endpoints: merged, Report: *report, BreakingChanges: breaking, AffectedServers: affected}
+		return &dao.ImportPlan{Changes: changes, Baseline: baseline, Revision: saved}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 新增接口的 ID 与修订号在保存后才确定
+	created := 0
+	for i := range result.Endpoints {
+		if result.Endpoints[i].ID == 0 {
+			result.Endpoints[i] = changes.Create[created]
+			created++
+		}
+	}
+	for i := range result.Report.Added {
+		result.Report.Added[i].ID = changes.Create[i].ID
+	}
+	if saved != nil {
+		result.Revision = saved.Revision
+	}
+	return result, nil
+}
+
+// nextRevision 返回导入到 target 时需要记录的修订，内容与最新修订 latest 相同时返回的 next 为 nil
+// 文档尚无修订（早于修订记录导入）且内容将要变化时，还返回把当前内容记录为第一个修订的 baseline，以便回滚
+func nextRevision(target *model.SwaggerDocument, latest, revision *model.SwaggerDocumentRevision) (baseline, next *model.SwaggerDocumentRevision) {
+	if latest != nil {
+		if latest.Checksum == revision.Checksum {
+			return nil, nil
+		}
+		return nil, revision
+	}
+	if target.Content != "" && target.Checksum != revision.Checksum {
+		baseline = &model.SwaggerDocumentRevision{
+			SwaggerID:  target.ID,
+			Title:      target.Title,
+			Version:    target.Version,
+			SpecFormat: target.SpecFormat,
+			Content:    target.Content,
+			Checksum:   target.Checksum,
+			Source:     model.RevisionSourceImport,
+			CreatedBy:  target.CreatedBy,
+		}
+	}
+	return baseline, revision
+}
+
+// diffEndpoints 比较已有接口与新解析出的接口，返回需要应用的变更、导入报告以及按新文档顺序排列的接口
+// previous 为已有接口上一次导入时的内容，为空时认为已有接口均未被手动修改
+func diffEndpoints(existing, previous, next []model.APIEndpoint, overwrite bool) (*dao.EndpointChangeSet, *ImportReport, []model.APIEndpoint) {
+	changes := &dao.EndpointChangeSet{}
+	report := &ImportReport{}
+	merged := make([]model.APIEndpoint, 0, len(next))
+
+	existingIndex := newEndpointIndex(existing)
+	previousIndex := newEndpointIndex(previous)
+	for i := range next {
+		j, ok := existingIndex.match(&next[i])
+		if !ok {
+			changes.Create = append(changes.Create, next[i])
+			report.Added = append(report.Added, newOperationRef(&next[i]))
+			merged = append(merged, next[i])
+			continue
+		}
+		current := existing[j]
+		var prev *model.APIEndpoint
+		if k, ok := previousIndex.match(&current); ok {
+			prev = &previous[k]
+		}
+		endpoint := mergeEndpoint(&current, prev, next[i], overwrite)
+		if sameEndpointContent(&current, &endpoint) {
+			report.Unchanged++
+		} else {
+			changes.Update = append(changes.Update, endpoint)
+			report.Changed = append(report.Changed, newOperationRef(&endpoint))
+		}
+		merged = append(merged, endpoint)
+	}
+	for i := range existing {
+		if !existingIndex.used[i] {
+			changes.Delete = append(changes.Delete, existing[i].ID)
+			report.Removed = append(report.Removed, newOperationRef(&existing[i]))
+		}
+	}
+	return changes, report, merged
+}
+
+// endpointIndex 按 operationId 与 method+path 查找接口，每个接口最多被匹配一次
+type endpointIndex struct {
+	byOperationID map[string]int
+	byRoute       map[string]int
+	used          []bool
+}
+
+func newEndpointIndex(endpoints []model.APIEndpoint) *endpointIndex {
+	index := &endpointIndex{
+		byOperationID: make(map[string]int, len(endpoints)),
+		byRoute:       make(map[string]int, len(endpoints)),
+		used:          make([]bool, len(endpoints)),
+	}
+	for i := range endpoints {
+		if id := endpoints[i].OperationID; id != "" {
+			if _, ok := index.byOperationID[id]; !ok {
+				index.byOperationID[id] = i
+			}
+		}
+		if _, ok := index.byRoute[routeKey(&endpoints[i])]; !ok {
+			index.byRoute[routeKey(&endpoints[i])] = i
+		}
+	}
+	return index
+}
+
+// match 返回与 e 对应的接口下标，operationId 优先，其次 method+path
+func (x *endpointIndex) match(e *model.APIEndpoint) (int, bool) {
+	if e.OperationID != "" {
+		if i, ok := x.byOperationID[e.OperationID]; ok && !x.used[i] {
+			x.used[i] = true
+			return i, true
+		}
+	}
+	if i, ok := x.byRoute[routeKey(e)]; ok && !x.used[i] {
+		x.used[i] = true
+		return i, true
+	}
+	return 0, false
+}
+
+func routeKey(e *model.APIEndpoint) string {
+	return e.Method + " " + e.Path
+}
+
+// mergeEndpoint 以新解析出的接口为准，保留 current 上被手动修改过的摘要、描述、标签、请求头、请求体与参数默认值，
+// 启用状态与版本号不来自文档，始终保留
+// 字段与上一次导入的值 prev 不同即视为手动修改过；prev 为 nil 或 overwrite 为 true 时全部以新接口为准
+func mergeEndpoint(current, prev *model.APIEndpoint, next model.APIEndpoint, overwrite bool) model.APIEndpoint {
+	merged := next
+	merged.ID = current.ID
+	merged.SwaggerID = current.SwaggerID
+	merged.CreatedAt = current.CreatedAt
+	merged.UpdatedAt = current.UpdatedAt
+	merged.Disabled = current.Disabled
+	merged.Version = current.Version
+	if overwrite || prev == nil {
+		return merged
+	}
+
+	keepEdited(&merged.Summary, current.Summary, prev.Summary)
+	keepEdited(&merged.Description, current.Description, prev.Description)
+	keepEdited(&merged.Tags, current.Tags, prev.Tags)
+	keepEdited(&merged.Body, current.Body, prev.Body)
+
+	headers := model.StringMap{}
+	for k, v := range next.Headers {
+		headers[k] = v
+	}
+	for k, v := range current.Headers {
+		if old, ok := prev.Headers[k]; !ok || old != v {
+			headers[k] = v
+		}
+	}
+	for k := range prev.Headers {
+		if _, ok := current.Headers[k]; !ok {
+			// 手动删除的请求头不再恢复
+			delete(headers, k)
+		}
+	}
+	merged.Headers = headers
+
+	params := make(model.APIParameters, 0, len(next.Parameters))
+	for _, p := range next.Parameters {
+		if cur, ok := findAPIParameter(current.Parameters, p.Name, p.In); ok {
+			old, inPrev := findAPIParameter(prev.Parameters, p.Name, p.In)
+			if !inPrev || old.Value != cur.Value {
+				p.Value = cur.Value
+			}
+		}
+		params = append(params, p)
+	}
+	for _, p := range current.Parameters {
+		_, inPrev := findAPIParameter(prev.Parameters, p.Name, p.In)
+		_, inNext := findAPIParameter(next.Parameters, p.Name, p.In)
+		if !inPrev && !inNext {
+			// 手动添加的参数保留
+			params = append(params, p)
+		}
+	}
+	merged.Parameters = params
+	return merged
+}
+
+// keepEdited 当 current 与上一次导入的值 prev 不同时，以 current 覆盖 *field
+func keepEdited(field *string, current, prev string) {
+	if current != prev {
+		*field = current
+	}
+}
+
+func findAPIParameter(params model.APIParameters, name, in string) (model.APIParameter, bool) {
+	for _, p := range params {
+		if p.Name == name && p.In == in {
+			return p, true
+		}
+	}
+	return model.APIParameter{}, false
+}
+
+// sameEndpointContent 比较两个接口中由文档决定或可编辑的字段是否一致
+func sameEndpointContent(a, b *model.APIEndpoint) bool {
+	return a.Path == b.Path &&
+		a.Method == b.Method &&
+		a.Summary == b.Summary &&
+		a.Description == b.Description &&
+		a.OperationID == b.OperationID &&
+		a.Tags == b.Tags &&
+		a.Body == b.Body &&
+		sameJSON(string(a.Responses), string(b.Responses)) &&
+		sameJSON(string(a.InputSchema), string(b.InputSchema)) &&
+		len(a.Parameters) == len(b.Parameters) &&
+		(len(a.Parameters) == 0 || reflect.DeepEqual(a.Parameters, b.Parameters)) &&
+		len(a.Headers) == len(b.Headers) &&
+		(len(a.Headers) == 0 || reflect.DeepEqual(a.Headers, b.Headers))
+}
+
+// sameJSON 按语义比较两个 JSON 文本，数据库可能改变键的顺序与空白
+func sameJSON(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var va, vb interface{}
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const petsV1 = `{"openapi": "3.0.0", "info": {"title": "Pets", "version": "1.0"}, "paths": {
+  "/pets": {"get": {"operationId": "listPets", "summary": "List pets",
+    "parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "default": 10}}],
+    "responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"type": "array", "items": {"type": "string"}}}}}}}},
+  "/pets/{id}": {"delete": {"operationId": "deletePet", "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+    "responses": {"204": {"description": "Deleted"}}}}}}`
+
+// v2 中 listPets 的路径改为 /animals，新增 limit 的上限描述；deletePet 被移除；新增 createPet
+const petsV2 = `{"openapi": "3.0.0", "info": {"title": "Pets", "version": "2.0"}, "paths": {
+  "/animals": {"get": {"operationId": "listPets", "summary": "List all pets",
+    "parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "default": 20, "maximum": 100}}],
+    "responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"type": "array", "items": {"type": "string"}}}}}}},
+    "post": {"operationId": "createPet", "responses": {"201": {"description": "Created"}}}}}}`
+
+// importedEndpoints 返回 content 首次导入后保存的接口，ID 从 1 开始
+func importedEndpoints(t *testing.T, s *swaggerService, content string) []model.APIEndpoint {
+	_, endpoints, err := s.parseDocument([]byte(content))
+	require.NoError(t, err)
+	for i := range endpoints {
+		endpoints[i].ID = uint(i + 1)
+		endpoints[i].SwaggerID = 7
+	}
+	return endpoints
+}
+
+func TestSwaggerService_ReimportDocument(t *testing.T) {
+	mockDAO := new(MockAPIEndpointDAO)
+	mockDocumentDAO := new(MockSwaggerDocumentDAO)
+	service := newURLTestSwaggerService(mockDAO, mockDocumentDAO)
+
+	ctx := context.Background()
+	stored := importedEndpoints(t, service, petsV1)
+	require.Len(t, stored, 2)
+	list := stored[0]
+	require.Equal(t, "listPets", list.OperationID)
+	// 手动修改描述、请求头与参数默认值
+	list.Description = "edited description"
+	list.Headers["X-Tenant"] = "acme"
+	list.Parameters[0].Value = "5"
+	stored[0] = list
+
+	target := &model.SwaggerDocument{ID: 7, Title: "Pets", Content: petsV1, Checksum: checksum([]byte(petsV1))}
+	mockDocumentDAO.On("GetByID", ctx, uint(7)).Return(target, nil)
+	mockDocumentDAO.On("Reimport", ctx, uint(7)).Return(&dao.ImportState{Document: target, Endpoints: stored}, nil)
+	var changes *dao.EndpointChangeSet
+	mockDocumentDAO.On("SaveImport", ctx, target, mock.AnythingOfType("*dao.EndpointChangeSet"), mock.Anything).Run(func(args mock.Arguments) {
+		saveImport(7)(args)
+		changes = args.Get(2).(*dao.EndpointChangeSet)
+	}).Return(nil)
+
+	result, err := service.ReimportDocument(ctx, 7, []byte(petsV2), false, false)
+	require.NoError(t, err)
+
+	// 变更在一次 SaveImport 中提交
+	mockDocumentDAO.AssertNumberOfCalls(t, "SaveImport", 1)
+	require.Len(t, changes.Update, 1)
+	assert.Equal(t, []uint{2}, changes.Delete)
+	require.Len(t, changes.Create, 1)
+
+	report := result.Report
+	require.Len(t, report.Added, 1)
+	assert.Equal(t, "createPet", report.Added[0].OperationID)
+	assert.Equal(t, uint(100), report.Added[0].ID)
+	require.Len(t, report.Removed, 1)
+	assert.Equal(t, "deletePet", report.Removed[0].OperationID)
+	require.Len(t, report.Changed, 1)
+	assert.Equal(t, "listPets", report.Changed[0].OperationID)
+	assert.Equal(t, uint(1), report.Changed[0].ID)
+
+	// 按 operationId 匹配，路径与摘要随文档更新，手动修改的字段保留
+	updated := changes.Update[0]
+	assert.Equal(t, uint(1), updated.ID)
+	assert.Equal(t, "/animals", updated.Path)
+	assert.Equal(t, "List all pets", updated.Summary)
+	assert.Equal(t, "edited description", updated.Description)
+	assert.Equal(t, "acme", updated.Headers["X-Tenant"])
+	assert.Equal(t, "5", updated.Parameters[0].Value)
+	assert.Contains(t, string(updated.InputSchema), `"maximum":100`)
+
+	assert.Equal(t, "2.0", result.Document.Version)
+	assert.Equal(t, petsV2, result.Document.Content)
+	require.Len(t, result.Endpoints, 2)
+	for _, e := range result.Endpoints {
+		assert.NotZero(t, e.ID)
+	}
+}
+
+func TestSwaggerService_ReimportDocument_Overwrite(t *testing.T) {
+	mockDAO := new(MockAPIEndpointDAO)
+	mockDocumentDAO := new(MockSwaggerDocumentDAO)
+	service := newURLTestSwaggerService(mockDAO, mockDocumentDAO)
+
+	ctx := context.Background()
+	stored := importedEndpoints(t, service, petsV1)
+	stored[0].Description = "edited description"
+	stored[0].Parameters[0].Value = "5"
+
+	target := &model.SwaggerDocument{ID: 7, Title: "Pets", Content: petsV1}
+	mockDocumentDAO.On("GetByID", ctx, uint(7)).Return(target, nil)
+	mockDocumentDAO.On("Reimport", ctx, uint(7)).Return(&dao.ImportState{Document: target, Endpoints: stored}, nil)
+	var changes *dao.EndpointChangeSet
+	mockDocumentDAO.On("SaveImport", ctx, target, mock.AnythingOfType("*dao.EndpointChangeSet"), mock.Anything).Run(func(args mock.Arguments) {
+		changes = args.Get(2).(*dao.EndpointChangeSet)
+	}).Return(nil)
+
+	// 重新导入相同内容：未修改的接口不变，被修改的接口恢复为文档内容
+	result, err := service.ReimportDocument(ctx, 7, []byte(petsV1), true, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Report.Unchanged)
+	assert.Empty(t, result.Report.Added)
+	assert.Empty(t, result.Report.Removed)
+	require.Len(t, changes.Update, 1)
+	assert.Equal(t, "", changes.Update[0].Description)
+	assert.Equal(t, "10", changes.Update[0].Parameters[0].Value)
+}
+
+func TestSwaggerService_ParseAndSave_SameTitleCreatesDocument(t *testing.T) {
+	mockDAO := new(MockAPIEndpointDAO)
+	mockDocumentDAO := new(MockSwaggerDocumentDAO)
+	service := newURLTestSwaggerService(mockDAO, mockDocumentDAO)
+
+	ctx := context.Background()
+	mockDocumentDAO.On("SaveImport", ctx, mock.MatchedBy(func(doc *model.SwaggerDocument) bool {
+		return doc.ID == 0 && doc.CreatedBy == "bob"
+	}), mock.AnythingOfType("*dao.EndpointChangeSet"), mock.Anything).Run(saveImport(8)).Return(nil)
+
+	// 与已有文档同标题的内容导入为新文档，不合并到已有文档
+	doc, endpoints, err := service.ImportDocument(ctx, []byte(petsV1), "bob")
+	require.NoError(t, err)
+	assert.Equal(t, uint(8), doc.ID)
+	assert.Len(t, endpoints, 2)
+	mockDAO.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
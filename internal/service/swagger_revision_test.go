@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSwaggerService_DiffRevisions(t *testing.T) {
+	service := newURLTestSwaggerService(new(MockAPIEndpointDAO), new(MockSwaggerDocumentDAO))
+	revisionDAO := new(MockSwaggerRevisionDAO)
+	service.revisionDAO = revisionDAO
+
+	ctx := context.Background()
+	revisionDAO.On("Get", ctx, uint(7), 1).Return(&model.SwaggerDocumentRevision{SwaggerID: 7, Revision: 1, Content: petsV1}, nil)
+	revisionDAO.On("Get", ctx, uint(7), 2).Return(&model.SwaggerDocumentRevision{SwaggerID: 7, Revision: 2, Content: petsV2}, nil)
+	revisionDAO.On("Get", ctx, uint(7), 3).Return(nil, gorm.ErrRecordNotFound)
+
+	report, err := service.DiffRevisions(ctx, 7, 1, 2)
+	require.NoError(t, err)
+	require.Len(t, report.AddedOperations, 1)
+	assert.Equal(t, "createPet", report.AddedOperations[0].OperationID)
+	require.Len(t, report.RemovedOperations, 1)
+	assert.Equal(t, "deletePet", report.RemovedOperations[0].OperationID)
+	require.Len(t, report.ChangedOperations, 1)
+	list := report.ChangedOperations[0]
+	assert.Equal(t, "/animals", list.Path)
+	assert.Equal(t, "/pets", list.BasePath)
+	require.Len(t, list.ChangedParameters, 1)
+	assert.Equal(t, "limit", list.ChangedParameters[0].Name)
+
+	_, err = service.DiffRevisions(ctx, 7, 1, 3)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestSwaggerService_RollbackDocument(t *testing.T) {
+	mockDAO := new(MockAPIEndpointDAO)
+	mockDocumentDAO := new(MockSwaggerDocumentDAO)
+	service := newURLTestSwaggerService(mockDAO, mockDocumentDAO)
+	revisionDAO := new(MockSwaggerRevisionDAO)
+	service.revisionDAO = revisionDAO
+
+	ctx := context.Background()
+	stored := importedEndpoints(t, service, petsV2)
+	target := &model.SwaggerDocument{ID: 7, Title: "Pets", Version: "2.0", Content: petsV2, Checksum: checksum([]byte(petsV2))}
+	mockDocumentDAO.On("GetByID", ctx, uint(7)).Return(target, nil)
+	latest := &model.SwaggerDocumentRevision{SwaggerID: 7, Revision: 2, Checksum: target.Checksum}
+	mockDocumentDAO.On("Reimport", ctx, uint(7)).Return(&dao.ImportState{Document: target, Endpoints: stored, Latest: latest}, nil)
+	revisionDAO.On("Get", ctx, uint(7), 1).Return(&model.SwaggerDocumentRevision{SwaggerID: 7, Revision: 1, Content: petsV1}, nil)
+	var changes *dao.EndpointChangeSet
+	var revision *model.SwaggerDocumentRevision
+	mockDocumentDAO.On("SaveImport", ctx, target, mock.AnythingOfType("*dao.EndpointChangeSet"), mock.Anything).Run(func(args mock.Arguments) {
+		saveImport(7)(args)
+		changes = args.Get(2).(*dao.EndpointChangeSet)
+		revision = args.Get(3).(*model.SwaggerDocumentRevision)
+		revision.Revision = 3
+	}).Return(nil)
+
+	result, err := service.RollbackDocument(ctx, 7, 1, false, false)
+	require.NoError(t, err)
+
+	// 恢复修订 1 的接口集合：createPet 被删除，deletePet 重新创建，listPets 恢复原路径
+	require.Len(t, changes.Create, 1)
+	assert.Equal(t, "deletePet", changes.Create[0].OperationID)
+	assert.Equal(t, []uint{2}, changes.Delete)
+	require.Len(t, changes.Update, 1)
+	assert.Equal(t, "/pets", changes.Update[0].Path)
+
+	// 回滚记录为新的修订
+	require.NotNil(t, revision)
+	assert.Equal(t, model.RevisionSourceRollback, revision.Source)
+	assert.Equal(t, 1, revision.RolledBackFrom)
+	assert.Equal(t, petsV1, revision.Content)
+	assert.Equal(t, 3, result.Revision)
+	assert.Equal(t, "1.0", result.Document.Version)
+	assert.Equal(t, petsV1, result.Document.Content)
+}
+
+func TestSwaggerService_ReimportDocument_RecordsBaselineRevision(t *testing.T) {
+	mockDAO := new(MockAPIEndpointDAO)
+	mockDocumentDAO := new(MockSwaggerDocumentDAO)
+	service := newURLTestSwaggerService(mockDAO, mockDocumentDAO)
+
+	ctx := context.Background()
+	target := &model.SwaggerDocument{ID: 7, Title: "Pets", Version: "1.0", Content: petsV1, Checksum: checksum([]byte(petsV1)), CreatedBy: "alice"}
+	mockDocumentDAO.On("GetByID", ctx, uint(7)).Return(target, nil)
+	mockDocumentDAO.On("Reimport", ctx, uint(7)).Return(&dao.ImportState{Document: target, Endpoints: importedEndpoints(t, service, petsV1)}, nil)
+	mockDocumentDAO.On("SaveImport", ctx, target, mock.AnythingOfType("*dao.EndpointChangeSet"), mock.AnythingOfType("*model.SwaggerDocumentRevision")).
+		Return(nil)
+
+	// 修订功能上线前导入的文档没有修订，重新导入时在同一事务中先为原内容补录一个修订
+	_, err := service.ReimportDocument(ctx, 7, []byte(petsV2), false, false)
+	require.NoError(t, err)
+	require.Len(t, mockDocumentDAO.Plans, 1)
+	baseline := mockDocumentDAO.Plans[0].Baseline
+	require.NotNil(t, baseline)
+	assert.Equal(t, petsV1, baseline.Content)
+	assert.Equal(t, checksum([]byte(petsV1)), baseline.Checksum)
+	assert.Equal(t, "alice", baseline.CreatedBy)
+	mockDocumentDAO.AssertExpectations(t)
+}
+
+func TestSwaggerService_ReimportDocument_SameContentSkipsRevision(t *testing.T) {
+	mockDAO := new(MockAPIEndpointDAO)
+	mockDocumentDAO := new(MockSwaggerDocumentDAO)
+	service := newURLTestSwaggerService(mockDAO, mockDocumentDAO)
+
+	ctx := context.Background()
+	target := &model.SwaggerDocument{ID: 7, Title: "Pets", Content: petsV1, Checksum: checksum([]byte(petsV1))}
+	mockDocumentDAO.On("GetByID", ctx, uint(7)).Return(target, nil)
+	latest := &model.SwaggerDocumentRevision{Revision: 4, Checksum: target.Checksum}
+	mockDocumentDAO.On("Reimport", ctx, uint(7)).Return(&dao.ImportState{Document: target, Endpoints: importedEndpoints(t, service, petsV1), Latest: latest}, nil)
+	mockDocumentDAO.On("SaveImport", ctx, target, mock.AnythingOfType("*dao.EndpointChangeSet"), (*model.SwaggerDocumentRevision)(nil)).
+		Return(nil)
+
+	// 内容与最新修订相同时不记录新的修订
+	result, err := service.ReimportDocument(ctx, 7, []byte(petsV1), false, false)
+	require.NoError(t, err)
+	assert.Zero(t, result.Revision)
+	assert.Nil(t, mockDocumentDAO.Plans[0].Baseline)
+}
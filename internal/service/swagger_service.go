@@ -6,102 +6,326 @@ import (
 	"fmt"
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi3"
-	"io"
+	"github.com/getkin/kin-openapi/routers"
 	"mcp-manager/internal/dao"
 	"mcp-manager/internal/model"
+	"mcp-manager/internal/utils/diff"
 	http "mcp-manager/internal/utils/http"
 	"mcp-manager/internal/utils/parser"
-	"net/url"
-	"strings"
+	"mcp-manager/pkg/config"
+	"time"
 )
 
 // SwaggerService 定义 swagger 解析与 APIEndpoint 管理的业务接口
 type SwaggerService interface {
-	// ParseAndSave 解析 swagger 内容并保存所有接口到数据库
-	ParseAndSave(ctx context.Context, swaggerContent []byte) ([]model.APIEndpoint, error)
+	// ParseAndSave 解析 swagger 内容，保存文档及其所有接口到数据库
+	ParseAndSave(ctx context.Context, swaggerContent []byte, createdBy string) ([]model.APIEndpoint, error)
+	// ImportDocument 解析 swagger 内容，保存文档及其所有接口，返回新建的文档
+	ImportDocument(ctx context.Context, swaggerContent []byte, createdBy string) (*model.SwaggerDocument, []model.APIEndpoint, error)
+	// ImportFromURL 拉取 sourceURL 的文档并导入，记录来源地址以便定时同步，authHeader 保存为 secret
+	ImportFromURL(ctx context.Context, sourceURL, authHeader, createdBy string) (*model.SwaggerDocument, []model.APIEndpoint, error)
+	// ReimportDocument 使用 swaggerContent 重新导入指定文档，返回接口的变更报告
+	// 文档已被 MCP Server 使用且存在不兼容变更时，策略为 block 且 force 为 false 则返回 *BreakingChangeError
+	ReimportDocument(ctx context.Context, id uint, swaggerContent []byte, overwrite, force bool) (*ImportResult, error)
+	// ListRevisions 查询文档的所有修订，新的在前
+	ListRevisions(ctx context.Context, id uint) ([]model.SwaggerDocumentRevision, error)
+	// GetRevision 查询文档的指定修订，包含原始内容
+	GetRevision(ctx context.Context, id uint, revision int) (*model.SwaggerDocumentRevision, error)
+	// DiffRevisions 比较文档的两个修订，返回接口与 Schema 的结构化差异
+	DiffRevisions(ctx context.Context, id uint, from, to int) (*diff.Report, error)
+	// RollbackDocument 将文档恢复为指定修订的内容与接口集合，并记录为新的修订
+	RollbackDocument(ctx context.Context, id uint, revision int, overwrite, force bool) (*ImportResult, error)
+	// SyncDocument 重新拉取文档的来源地址，内容变化时重新解析并更新接口，返回内容是否发生变化
+	SyncDocument(ctx context.Context, id uint) (bool, error)
 	// ListAPIEndpoints 查询指定 swaggerID 下的所有 APIEndpoint
 	ListAPIEndpoints(ctx context.Context, swaggerID uint) ([]model.APIEndpoint, error)
+	// SearchAPIEndpoints 按过滤、搜索、排序条件分页查询 APIEndpoint
+	SearchAPIEndpoints(ctx context.Context, query dao.EndpointQuery) (*dao.EndpointPage, error)
+	// BulkEndpoints 在同一事务中对一组接口执行删除、修改标签、启用禁用或修改请求头与参数默认值，返回逐项结果
+	BulkEndpoints(ctx context.Context, req *BulkEndpointRequest) (*BulkReport, error)
 	// GetAPIEndpointByID 根据 ID 查询 APIEndpoint
 	GetAPIEndpointByID(ctx context.Context, id uint) (*model.APIEndpoint, error)
-	// DeleteAPIEndpoint 删除指定的 APIEndpoint
+	// DeleteAPIEndpoint 将指定的 APIEndpoint 移入回收站
 	DeleteAPIEndpoint(ctx context.Context, id uint) error
-	// UpdateAPIEndpoint 更新指定的 APIEndpoint
+	// UpdateAPIEndpoint 更新指定的 APIEndpoint，字段校验失败时返回 *EndpointValidationError，
+	// endpoint.Version 非 0 时版本号不一致返回 dao.ErrVersionConflict
 	UpdateAPIEndpoint(ctx context.Context, endpoint *model.APIEndpoint) error
-	// TestAPIEndpoint 测试指定 APIEndpoint，返回响应内容
-	TestAPIEndpoint(ctx context.Context, endpoint *model.APIEndpoint, baseURL string) (string, error)
+	// PatchAPIEndpoint 以 JSON Merge Patch 修改指定的 APIEndpoint，version 为读取时的版本号
+	PatchAPIEndpoint(ctx context.Context, id uint, patch []byte, version int64) (*model.APIEndpoint, error)
+	// TestAPIEndpoint 测试 endpoint.ID 对应的 APIEndpoint，endpoint 中提交的参数取值、请求头与请求体替换保存的配置，
+	// 返回包含状态码、响应头与各阶段耗时的结构化结果；
+	// 发送前按所属文档中声明的参数与请求体校验请求，不符合或提交的取值中出现了新的 secret 引用时返回 *RequestValidationError，发送后校验实际的响应；
+	// 接口引用了 secret 而 baseURL 既不是文档声明的服务器也不在 secrets.trusted_base_urls 中时返回 ErrUntrustedBaseURL
+	TestAPIEndpoint(ctx context.Context, endpoint *model.APIEndpoint, baseURL string) (*EndpointTestResult, error)
+
+	// ListDocuments 查询所有已导入的 swagger 文档
+	ListDocuments(ctx context.Context) ([]model.SwaggerDocument, error)
+	// GetDocumentByID 根据 ID 查询 swagger 文档
+	GetDocumentByID(ctx context.Context, id uint) (*model.SwaggerDocument, error)
+	// UpdateDocument 更新 swagger 文档的元信息
+	UpdateDocument(ctx context.Context, doc *model.SwaggerDocument) error
+	// DeleteDocument 将 swagger 文档及其下所有接口移入回收站
+	DeleteDocument(ctx context.Context, id uint) error
 }
 
 // swaggerService 实现 SwaggerService 接口
 type swaggerService struct {
 	swagger2Parser parser.Parser[*openapi2.T]
 	openapi3Parser parser.Parser[*openapi3.T]
-	dao            dao.APIEndpointDAO
-	httpClient     http.HTTPClient
+	// openapi31Parser 将 3.1 文档降级为 3.0 后解析
+	openapi31Parser parser.Parser[*openapi3.T]
+	// specParser 将任意受支持版本的文档解析为 OpenAPI 3 结构，用于比较修订
+	specParser  parser.SwaggerParserWithExtract[*openapi3.T]
+	dao         dao.APIEndpointDAO
+	documentDAO dao.SwaggerDocumentDAO
+	revisionDAO dao.SwaggerRevisionDAO
+	profileDAO  dao.AuthProfileDAO
+	secrets     *secretStore
+	httpClient  http.HTTPClient
+	executor    APIExecutor
+	fetcher     SpecFetcher
+	// breakingPolicy 为重新导入时遇到影响已发布工具的不兼容变更的处理策略
+	breakingPolicy string
+	// trustedBaseURLs 为文档声明的服务器之外，测试时允许发送 secret 与鉴权信息的上游地址
+	trustedBaseURLs []string
 }
 
 // NewSwaggerService 创建一个新的 SwaggerService 实例
 func NewSwaggerService() SwaggerService {
+	httpClient := http.NewHTTPClient()
+	secrets := newSecretStore(nil, DefaultKeyring())
 	return &swaggerService{
-		swagger2Parser: parser.NewSwagger2Parser(),
-		openapi3Parser: parser.NewOpenAPI3Parser(),
-		dao:            dao.NewAPIEndpointDAO(nil),
-		httpClient:     http.NewHTTPClient(),
+		swagger2Parser:  parser.NewSwagger2Parser(),
+		openapi3Parser:  parser.NewOpenAPI3Parser(),
+		openapi31Parser: parser.NewOpenAPI31Parser(),
+		specParser:      parser.NewSwaggerParser(),
+		dao:             dao.NewAPIEndpointDAO(nil),
+		documentDAO:     dao.NewSwaggerDocumentDAO(nil),
+		revisionDAO:     dao.NewSwaggerRevisionDAO(nil),
+		profileDAO:      dao.NewAuthProfileDAO(nil),
+		secrets:         secrets,
+		httpClient:      httpClient,
+		executor:        NewAPIExecutor(httpClient, secrets),
+		fetcher:         NewSpecFetcher(0),
+		breakingPolicy:  config.SwaggerBreakingChangePolicy(),
+		trustedBaseURLs: config.SecretTrustedBaseURLs(),
+	}
+}
+
+// ParseAndSave 解析 swagger 内容，保存文档及其所有接口到数据库
+func (s *swaggerService) ParseAndSave(ctx context.Context, swaggerContent []byte, createdBy string) ([]model.APIEndpoint, error) {
+	_, endpoints, err := s.ImportDocument(ctx, swaggerContent, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// ImportDocument 解析 swagger 内容并保存为新文档，接口通过 SwaggerID 关联到文档
+// 重新导入已有文档需通过 ReimportDocument 显式指定目标
+func (s *swaggerService) ImportDocument(ctx context.Context, swaggerContent []byte, createdBy string) (*model.SwaggerDocument, []model.APIEndpoint, error) {
+	doc, endpoints, err := s.parseDocument(swaggerContent)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc.CreatedBy = createdBy
+	result, err := s.applyImport(ctx, nil, doc, endpoints, importOptions{source: model.RevisionSourceImport, createdBy: createdBy})
+	if err != nil {
+		return nil, nil, err
+	}
+	return result.Document, result.Endpoints, nil
+}
+
+// ReimportDocument 使用 swaggerContent 重新导入指定文档，overwrite 为 true 时手动修改过的接口字段也以文档内容为准
+func (s *swaggerService) ReimportDocument(ctx context.Context, id uint, swaggerContent []byte, overwrite, force bool) (*ImportResult, error) {
+	target, err := s.documentDAO.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	doc, endpoints, err := s.parseDocument(swaggerContent)
+	if err != nil {
+		return nil, err
 	}
+	return s.applyImport(ctx, target, doc, endpoints, importOptions{overwrite: overwrite, source: model.RevisionSourceImport, force: force})
 }
 
-// ParseAndSave 解析 swagger 内容并保存所有接口到数据库
-func (s *swaggerService) ParseAndSave(ctx context.Context, swaggerContent []byte) ([]model.APIEndpoint, error) {
+// ImportFromURL 拉取 sourceURL 的文档并导入，来源地址随文档保存，供定时同步使用
+// 已存在同一来源地址的文档时按差异重新导入该文档
+// authHeader 保存为自动创建的 secret，文档中只记录引用，因此带 authHeader 时需要配置主密钥，未配置时在拉取前返回 ErrSecretsNotConfigured；
+// 来源地址由调用方指定，authHeader 不允许引用 secret，以免已保存的 secret 被发送到其他地址
+func (s *swaggerService) ImportFromURL(ctx context.Context, sourceURL, authHeader, createdBy string) (*model.SwaggerDocument, []model.APIEndpoint, error) {
+	if model.SecretRefPattern.MatchString(authHeader) {
+		return nil, nil, fmt.Errorf("auth_header must not contain secret references")
+	}
+	if authHeader != "" && s.secrets.keyring == nil {
+		return nil, nil, fmt.Errorf("save auth header: %w", ErrSecretsNotConfigured)
+	}
+	content, err := s.fetcher.Fetch(ctx, sourceURL, authHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, endpoints, err := s.parseDocument(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := s.findExisting(ctx, sourceURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	sealed, err := s.secrets.sealValue(ctx, "swagger_document.source_auth_header", "auth header of "+sourceURL, authHeader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("save auth header: %w", err)
+	}
+	now := time.Now()
+	if target == nil {
+		doc.CreatedBy = createdBy
+		doc.SourceURL = sourceURL
+		doc.SourceAuthHeader = sealed
+		doc.LastSyncedAt = &now
+	}
+	var replaced string
+	result, err := s.applyImport(ctx, target, doc, endpoints, importOptions{
+		source:    model.RevisionSourceURL,
+		createdBy: createdBy,
+		prepare: func(existing *model.SwaggerDocument) {
+			replaced = existing.SourceAuthHeader
+			existing.SourceAuthHeader = sealed
+			existing.LastSyncedAt = &now
+			existing.SyncError = ""
+		},
+	})
+	if err != nil {
+		s.secrets.releaseValues(ctx, sealed)
+		return nil, nil, err
+	}
+	s.secrets.releaseValues(ctx, replaced)
+	return result.Document, result.Endpoints, nil
+}
+
+// SyncDocument 重新拉取文档的来源地址，校验和未变化时仅记录同步时间
+// 拉取或解析失败时保留原有内容与接口，失败原因记录在 SyncError 中
+func (s *swaggerService) SyncDocument(ctx context.Context, id uint) (bool, error) {
+	existing, err := s.documentDAO.GetByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if existing.SourceURL == "" {
+		return false, fmt.Errorf("swagger document %d was not imported from a url", id)
+	}
+
+	authHeader, err := s.secrets.expand(ctx, existing.SourceAuthHeader)
+	if err != nil {
+		return false, s.recordSyncResult(ctx, existing, fmt.Errorf("resolve auth header: %w", err))
+	}
+	content, err := s.fetcher.Fetch(ctx, existing.SourceURL, authHeader)
+	if err != nil {
+		return false, s.recordSyncResult(ctx, existing, err)
+	}
+	if checksum(content) == existing.Checksum {
+		return false, s.recordSyncResult(ctx, existing, nil)
+	}
+	doc, endpoints, err := s.parseDocument(content)
+	if err != nil {
+		return false, s.recordSyncResult(ctx, existing, err)
+	}
+
+	now := time.Now()
+	synced := func(doc *model.SwaggerDocument) {
+		doc.LastSyncedAt = &now
+		doc.SyncError = ""
+	}
+	if _, err := s.applyImport(ctx, existing, doc, endpoints, importOptions{source: model.RevisionSourceSync, prepare: synced}); err != nil {
+		return false, s.recordSyncResult(ctx, existing, err)
+	}
+	return true, nil
+}
+
+// recordSyncResult 保存文档的同步时间与结果，syncErr 非空时返回 syncErr
+func (s *swaggerService) recordSyncResult(ctx context.Context, doc *model.SwaggerDocument, syncErr error) error {
+	now := time.Now()
+	doc.LastSyncedAt = &now
+	doc.SyncError = ""
+	if syncErr != nil {
+		doc.SyncError = syncErr.Error()
+	}
+	if err := s.documentDAO.Update(ctx, doc); err != nil {
+		return err
+	}
+	return syncErr
+}
+
+// parseDocument 按声明的版本解析 swagger 内容，返回待保存的文档及其接口
+func (s *swaggerService) parseDocument(swaggerContent []byte) (*model.SwaggerDocument, []model.APIEndpoint, error) {
 	var (
 		endpoints []model.APIEndpoint
+		doc       *model.SwaggerDocument
 	)
 
-	contentStr := string(swaggerContent)
-	isOpenAPI3 := strings.Contains(contentStr, "openapi") && strings.Contains(contentStr, "3.")
-	isSwagger2 := strings.Contains(contentStr, "swagger") && strings.Contains(contentStr, "2.")
-
-	if isOpenAPI3 {
-		doc, err := s.openapi3Parser.ParseFromData(swaggerContent)
-		if err != nil {
-			return nil, err
-		}
-		if err := s.openapi3Parser.Validate(doc); err != nil {
-			return nil, err
-		}
-		parserWithExtract, ok := s.openapi3Parser.(parser.SwaggerParserWithExtract[*openapi3.T])
-		if !ok {
-			return nil, fmt.Errorf("openapi3Parser does not support ExtractAPIEndpoints")
-		}
-		endpoints = parserWithExtract.ExtractAPIEndpoints(doc)
-	} else if isSwagger2 {
-		doc, err := s.swagger2Parser.ParseFromData(swaggerContent)
-		if err != nil {
-			return nil, err
-		}
-		if err := s.swagger2Parser.Validate(doc); err != nil {
-			return nil, err
-		}
-		parserWithExtract, ok := s.swagger2Parser.(parser.SwaggerParserWithExtract[*openapi2.T])
-		if !ok {
-			return nil, fmt.Errorf("swagger2Parser does not support ExtractAPIEndpoints")
+	spec, err := parser.DetectVersion(swaggerContent)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch spec.Format {
+	case model.SpecFormatOpenAPI3:
+		p := s.openapi3Parser
+		if spec.IsOpenAPI31() {
+			p = s.openapi31Parser
 		}
-		endpoints = parserWithExtract.ExtractAPIEndpoints(doc)
-	} else {
-		return nil, fmt.Errorf("unknown swagger/openapi version")
+		doc, endpoints, err = s.parseOpenAPI3(p, spec.JSON)
+	case model.SpecFormatSwagger2:
+		doc, endpoints, err = s.parseSwagger2(spec.JSON)
+	default:
+		err = fmt.Errorf("unknown swagger/openapi version")
+	}
+	if err != nil {
+		return nil, nil, err
 	}
 
-	for i := range endpoints {
-		err := s.dao.Create(ctx, &endpoints[i])
-		if err != nil {
-			return nil, err
-		}
+	doc.Content = string(swaggerContent)
+	doc.Checksum = checksum(swaggerContent)
+	return doc, endpoints, nil
+}
+
+// parseOpenAPI3 使用 p 解析并校验 OpenAPI 3 文档，提取其中的接口
+func (s *swaggerService) parseOpenAPI3(p parser.Parser[*openapi3.T], data []byte) (*model.SwaggerDocument, []model.APIEndpoint, error) {
+	spec, err := p.ParseFromData(data)
+	if err != nil {
+		return nil, nil, err
 	}
-	return endpoints, nil
+	if err := p.Validate(spec); err != nil {
+		return nil, nil, err
+	}
+	parserWithExtract, ok := p.(parser.SwaggerParserWithExtract[*openapi3.T])
+	if !ok {
+		return nil, nil, fmt.Errorf("openapi3Parser does not support ExtractAPIEndpoints")
+	}
+	return newOpenAPI3Document(spec), parserWithExtract.ExtractAPIEndpoints(spec), nil
+}
+
+// parseSwagger2 解析并校验 Swagger 2.0 文档，提取其中的接口
+func (s *swaggerService) parseSwagger2(data []byte) (*model.SwaggerDocument, []model.APIEndpoint, error) {
+	spec, err := s.swagger2Parser.ParseFromData(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.swagger2Parser.Validate(spec); err != nil {
+		return nil, nil, err
+	}
+	parserWithExtract, ok := s.swagger2Parser.(parser.SwaggerParserWithExtract[*openapi2.T])
+	if !ok {
+		return nil, nil, fmt.Errorf("swagger2Parser does not support ExtractAPIEndpoints")
+	}
+	return newSwagger2Document(spec), parserWithExtract.ExtractAPIEndpoints(spec), nil
 }
 
 func (s *swaggerService) ListAPIEndpoints(ctx context.Context, swaggerID uint) ([]model.APIEndpoint, error) {
 	return s.dao.List(ctx, swaggerID)
 }
 
+func (s *swaggerService) SearchAPIEndpoints(ctx context.Context, query dao.EndpointQuery) (*dao.EndpointPage, error) {
+	return s.dao.Search(ctx, query)
+}
+
 func (s *swaggerService) GetAPIEndpointByID(ctx context.Context, id uint) (*model.APIEndpoint, error) {
 	return s.dao.GetByID(ctx, id)
 }
@@ -111,64 +335,148 @@ func (s *swaggerService) DeleteAPIEndpoint(ctx context.Context, id uint) error {
 }
 
 func (s *swaggerService) UpdateAPIEndpoint(ctx context.Context, endpoint *model.APIEndpoint) error {
+	if invalid := validateEndpoint(endpoint); len(invalid) > 0 {
+		return &EndpointValidationError{Fields: invalid}
+	}
 	return s.dao.Update(ctx, endpoint)
 }
 
-func (s *swaggerService) TestAPIEndpoint(ctx context.Context, endpoint *model.APIEndpoint, baseURL string) (string, error) {
-	// 1. 处理 path 参数
-	accURL := baseURL + endpoint.Path
-	for _, param := range endpoint.Parameters {
-		if param.In == "path" {
-			if param.Value == "" && param.Required {
-				return "", fmt.Errorf("missing required path parameter: %s", param.Name)
-			}
-			accURL = strings.ReplaceAll(accURL, "{"+param.Name+"}", param.Value)
+// TestAPIEndpoint 的路径、方法与所属文档以数据库中保存的为准，提交的取值只能引用保存的配置中已有的 secret
+func (s *swaggerService) TestAPIEndpoint(ctx context.Context, submitted *model.APIEndpoint, baseURL string) (*EndpointTestResult, error) {
+	stored, err := s.dao.GetByID(ctx, submitted.ID)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := mergeTestValues(stored, submitted)
+	if err != nil {
+		return nil, err
+	}
+	req, err := BuildAPIRequest(endpoint, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	// 文档不可用时不做校验，直接发送请求并说明未校验的原因
+	var route *routers.Route
+	doc, routeErr := s.endpointDocument(ctx, endpoint)
+	if routeErr == nil {
+		route, routeErr = s.endpointRoute(doc, endpoint)
+	}
+	if routeErr == nil {
+		if err := validateRequest(ctx, route, endpoint, req); err != nil {
+			return nil, err
 		}
 	}
 
-	// 2. 处理 query 参数
-	query := url.Values{}
-	for _, param := range endpoint.Parameters {
-		if param.In == "query" && param.Value != "" {
-			query.Add(param.Name, param.Value)
+	// secret 只解密后发送到文档声明的服务器或配置中信任的地址，
+	// 避免调用方通过 base_url 将 secret 发送到自己的服务器
+	trusted := s.trustedBaseURL(doc, baseURL)
+	if endpointHasSecretRefs(endpoint) && !trusted {
+		return nil, fmt.Errorf("%w: %s", ErrUntrustedBaseURL, baseURL)
+	}
+
+	// 文档绑定的鉴权配置同样只用于可信的地址，不会发送到调用方指定的其他地址
+	var auth *model.AuthProfile
+	if doc != nil && doc.AuthProfileID != nil && trusted {
+		if auth, err = s.profileDAO.GetByID(ctx, *doc.AuthProfileID); err != nil {
+			return nil, fmt.Errorf("auth profile %d: %w", *doc.AuthProfileID, err)
 		}
 	}
-	if len(query) > 0 {
-		accURL += "?" + query.Encode()
+
+	resp, err := s.executor.Execute(ctx, endpoint, baseURL, auth)
+	if err != nil {
+		return nil, err
+	}
+	result := &EndpointTestResult{Response: resp}
+	if routeErr != nil {
+		result.ValidationSkipped = routeErr.Error()
+		return result, nil
 	}
+	result.Violations = validateResponse(ctx, route, resp)
+	return result, nil
+}
 
-	// 3. 处理 header
-	headers := make(map[string]string)
-	for _, param := range endpoint.Parameters {
-		if param.In == "header" && param.Value != "" {
-			headers[param.Name] = param.Value
-		}
+func (s *swaggerService) ListDocuments(ctx context.Context) ([]model.SwaggerDocument, error) {
+	return s.documentDAO.List(ctx)
+}
+
+func (s *swaggerService) GetDocumentByID(ctx context.Context, id uint) (*model.SwaggerDocument, error) {
+	return s.documentDAO.GetByID(ctx, id)
+}
+
+// UpdateDocument 仅允许修改文档的标题、版本与创建人，原始内容只能通过重新导入变更
+func (s *swaggerService) UpdateDocument(ctx context.Context, doc *model.SwaggerDocument) error {
+	existing, err := s.documentDAO.GetByID(ctx, doc.ID)
+	if err != nil {
+		return err
 	}
-	for k, v := range endpoint.Headers {
-		headers[k] = v
-	}
-
-	// 4. 处理 body（支持 application/json）
-	var bodyReader io.Reader
-	if endpoint.Method == "POST" || endpoint.Method == "PUT" || endpoint.Method == "PATCH" {
-		var bodyStr string
-		for _, param := range endpoint.Parameters {
-			if param.In == "body" {
-				if param.Value == "" && param.Required {
-					return "", fmt.Errorf("missing required body parameter: %s", param.Name)
-				}
-				bodyStr = param.Value
-				break
-			}
-		}
-		if bodyStr == "" && endpoint.Body != "" {
-			bodyStr = endpoint.Body
-		}
-		if bodyStr != "" {
-			bodyReader = strings.NewReader(bodyStr)
-		}
+	existing.Title = doc.Title
+	existing.Version = doc.Version
+	existing.CreatedBy = doc.CreatedBy
+	if err := s.documentDAO.Update(ctx, existing); err != nil {
+		return err
 	}
+	*doc = *existing
+	return nil
+}
+
+// DeleteDocument 将文档连同其接口及引用这些接口的工具绑定移入回收站，修订保留到文档被彻底删除
+func (s *swaggerService) DeleteDocument(ctx context.Context, id uint) error {
+	return s.documentDAO.Delete(ctx, id)
+}
 
-	// 5. 发起请求
-	return s.httpClient.DoRequest(ctx, endpoint.Method, accURL, bodyReader)
+func (s *swaggerService) ListRevisions(ctx context.Context, id uint) ([]model.SwaggerDocumentRevision, error) {
+	return s.revisionDAO.List(ctx, id)
+}
+
+func (s *swaggerService) GetRevision(ctx context.Context, id uint, revision int) (*model.SwaggerDocumentRevision, error) {
+	return s.revisionDAO.Get(ctx, id, revision)
+}
+
+// DiffRevisions 将两个修订统一解析为 OpenAPI 3 结构后比较，from 为基准
+func (s *swaggerService) DiffRevisions(ctx context.Context, id uint, from, to int) (*diff.Report, error) {
+	base, err := s.loadRevisionSpec(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.loadRevisionSpec(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+	return diff.Documents(base, target), nil
+}
+
+// loadRevisionSpec 解析文档指定修订的内容
+func (s *swaggerService) loadRevisionSpec(ctx context.Context, id uint, revision int) (*openapi3.T, error) {
+	rev, err := s.revisionDAO.Get(ctx, id, revision)
+	if err != nil {
+		return nil, fmt.Errorf("revision %d of swagger document %d: %w", revision, id, err)
+	}
+	spec, err := s.specParser.ParseFromData([]byte(rev.Content))
+	if err != nil {
+		return nil, fmt.Errorf("parse revision %d of swagger document %d: %w", revision, id, err)
+	}
+	return spec, nil
+}
+
+// RollbackDocument 以指定修订的内容重新导入文档，接口按差异恢复为该修订的接口集合
+// 回滚本身记录为新的修订，历史修订不会被删除
+func (s *swaggerService) RollbackDocument(ctx context.Context, id uint, revision int, overwrite, force bool) (*ImportResult, error) {
+	target, err := s.documentDAO.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rev, err := s.revisionDAO.Get(ctx, id, revision)
+	if err != nil {
+		return nil, fmt.Errorf("revision %d of swagger document %d: %w", revision, id, err)
+	}
+	doc, endpoints, err := s.parseDocument([]byte(rev.Content))
+	if err != nil {
+		return nil, err
+	}
+	return s.applyImport(ctx, target, doc, endpoints, importOptions{
+		overwrite:      overwrite,
+		source:         model.RevisionSourceRollback,
+		rolledBackFrom: revision,
+		force:          force,
+	})
 }
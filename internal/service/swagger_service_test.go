@@ -6,11 +6,16 @@ import (
 	"io"
 	"testing"
 
+	"mcp-manager/internal/dao"
 	"mcp-manager/internal/model"
+	httpclient "mcp-manager/internal/utils/http"
+	"mcp-manager/internal/utils/parser"
 
+	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
@@ -40,7 +45,38 @@ func (m *MockSwaggerParser) Parse(path string) (*openapi3.T, error) {
 	return args.Get(0).(*openapi3.T), args.Error(1)
 }
 
-func (m *MockSwaggerParser) ExtractAPIEndpoints(doc interface{}) []model.APIEndpoint {
+func (m *MockSwaggerParser) ExtractAPIEndpoints(doc *openapi3.T) []model.APIEndpoint {
+	args := m.Called(doc)
+	return args.Get(0).([]model.APIEndpoint)
+}
+
+// MockSwagger2Parser 模拟 Swagger 2.0 解析器
+type MockSwagger2Parser struct {
+	mock.Mock
+}
+
+func (m *MockSwagger2Parser) ParseFromData(data []byte) (*openapi2.T, error) {
+	args := m.Called(data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*openapi2.T), args.Error(1)
+}
+
+func (m *MockSwagger2Parser) Validate(doc *openapi2.T) error {
+	args := m.Called(doc)
+	return args.Error(0)
+}
+
+func (m *MockSwagger2Parser) Parse(path string) (*openapi2.T, error) {
+	args := m.Called(path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*openapi2.T), args.Error(1)
+}
+
+func (m *MockSwagger2Parser) ExtractAPIEndpoints(doc *openapi2.T) []model.APIEndpoint {
 	args := m.Called(doc)
 	return args.Get(0).([]model.APIEndpoint)
 }
@@ -78,14 +114,236 @@ func (m *MockAPIEndpointDAO) List(ctx context.Context, swaggerID uint) ([]model.
 	return args.Get(0).([]model.APIEndpoint), args.Error(1)
 }
 
+func (m *MockAPIEndpointDAO) DeleteBySwaggerID(ctx context.Context, swaggerID uint) error {
+	args := m.Called(ctx, swaggerID)
+	return args.Error(0)
+}
+
+func (m *MockAPIEndpointDAO) ListByIDs(ctx context.Context, ids []uint) ([]model.APIEndpoint, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]model.APIEndpoint), args.Error(1)
+}
+
+func (m *MockAPIEndpointDAO) Search(ctx context.Context, query dao.EndpointQuery) (*dao.EndpointPage, error) {
+	args := m.Called(ctx, query)
+	page, _ := args.Get(0).(*dao.EndpointPage)
+	return page, args.Error(1)
+}
+
+func (m *MockAPIEndpointDAO) ListIDs(ctx context.Context, query dao.EndpointQuery) ([]uint, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockAPIEndpointDAO) Bulk(ctx context.Context, ids []uint, continueOnError bool, apply dao.BulkFunc) ([]dao.BulkItemResult, error) {
+	args := m.Called(ctx, ids, continueOnError, apply)
+	items, _ := args.Get(0).([]dao.BulkItemResult)
+	return items, args.Error(1)
+}
+
+// MockSwaggerDocumentDAO 模拟 SwaggerDocumentDAO
+type MockSwaggerDocumentDAO struct {
+	mock.Mock
+	// Plans 为 Reimport 中计算出的导入计划
+	Plans []*dao.ImportPlan
+}
+
+func (m *MockSwaggerDocumentDAO) Create(ctx context.Context, doc *model.SwaggerDocument) error {
+	args := m.Called(ctx, doc)
+	return args.Error(0)
+}
+
+func (m *MockSwaggerDocumentDAO) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSwaggerDocumentDAO) Update(ctx context.Context, doc *model.SwaggerDocument) error {
+	args := m.Called(ctx, doc)
+	return args.Error(0)
+}
+
+func (m *MockSwaggerDocumentDAO) GetByID(ctx context.Context, id uint) (*model.SwaggerDocument, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SwaggerDocument), args.Error(1)
+}
+
+func (m *MockSwaggerDocumentDAO) GetBySourceURL(ctx context.Context, sourceURL string) (*model.SwaggerDocument, error) {
+	args := m.Called(ctx, sourceURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SwaggerDocument), args.Error(1)
+}
+
+func (m *MockSwaggerDocumentDAO) List(ctx context.Context) ([]model.SwaggerDocument, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.SwaggerDocument), args.Error(1)
+}
+
+func (m *MockSwaggerDocumentDAO) SaveImport(ctx context.Context, doc *model.SwaggerDocument, changes *dao.EndpointChangeSet, revision *model.SwaggerDocumentRevision) error {
+	args := m.Called(ctx, doc, changes, revision)
+	return args.Error(0)
+}
+
+// Reimport 以 On("Reimport") 返回的现状调用 plan，计划记录在 Plans 中，
+// 计划中的文档、接口变更与修订按一次 SaveImport 调用记录
+func (m *MockSwaggerDocumentDAO) Reimport(ctx context.Context, id uint, plan dao.ImportPlanFunc) error {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return args.Error(1)
+	}
+	state := args.Get(0).(*dao.ImportState)
+	p, err := plan(state)
+	if err != nil {
+		return err
+	}
+	m.Plans = append(m.Plans, p)
+	return m.MethodCalled("SaveImport", ctx, state.Document, p.Changes, p.Revision).Error(0)
+}
+
+// MockMCPServerDAO 模拟 MCPServerDAO
+type MockMCPServerDAO struct {
+	mock.Mock
+}
+
+func (m *MockMCPServerDAO) Create(ctx context.Context, server *model.MCPServer) error {
+	args := m.Called(ctx, server)
+	return args.Error(0)
+}
+
+func (m *MockMCPServerDAO) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMCPServerDAO) Update(ctx context.Context, server *model.MCPServer) error {
+	args := m.Called(ctx, server)
+	return args.Error(0)
+}
+
+func (m *MockMCPServerDAO) GetByID(ctx context.Context, id uint) (*model.MCPServer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.MCPServer), args.Error(1)
+}
+
+func (m *MockMCPServerDAO) GetByName(ctx context.Context, name string) (*model.MCPServer, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.MCPServer), args.Error(1)
+}
+
+func (m *MockMCPServerDAO) List(ctx context.Context) ([]model.MCPServer, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.MCPServer), args.Error(1)
+}
+
+func (m *MockMCPServerDAO) ListByEndpointIDs(ctx context.Context, endpointIDs []uint) ([]model.MCPServer, error) {
+	args := m.Called(ctx, endpointIDs)
+	return args.Get(0).([]model.MCPServer), args.Error(1)
+}
+
+// MockSwaggerRevisionDAO 模拟 SwaggerRevisionDAO
+type MockSwaggerRevisionDAO struct {
+	mock.Mock
+}
+
+func (m *MockSwaggerRevisionDAO) Create(ctx context.Context, revision *model.SwaggerDocumentRevision) error {
+	args := m.Called(ctx, revision)
+	return args.Error(0)
+}
+
+func (m *MockSwaggerRevisionDAO) Get(ctx context.Context, swaggerID uint, revision int) (*model.SwaggerDocumentRevision, error) {
+	args := m.Called(ctx, swaggerID, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SwaggerDocumentRevision), args.Error(1)
+}
+
+func (m *MockSwaggerRevisionDAO) Latest(ctx context.Context, swaggerID uint) (*model.SwaggerDocumentRevision, error) {
+	args := m.Called(ctx, swaggerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SwaggerDocumentRevision), args.Error(1)
+}
+
+func (m *MockSwaggerRevisionDAO) List(ctx context.Context, swaggerID uint) ([]model.SwaggerDocumentRevision, error) {
+	args := m.Called(ctx, swaggerID)
+	return args.Get(0).([]model.SwaggerDocumentRevision), args.Error(1)
+}
+
+func (m *MockSwaggerRevisionDAO) DeleteBySwaggerID(ctx context.Context, swaggerID uint) error {
+	args := m.Called(ctx, swaggerID)
+	return args.Error(0)
+}
+
+// MockAuthProfileDAO 模拟 AuthProfileDAO
+type MockAuthProfileDAO struct {
+	mock.Mock
+}
+
+func (m *MockAuthProfileDAO) Create(ctx context.Context, profile *model.AuthProfile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
+
+func (m *MockAuthProfileDAO) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAuthProfileDAO) Update(ctx context.Context, profile *model.AuthProfile) error {
+	args := m.Called(ctx, profile)
+	return args.Error(0)
+}
+
+func (m *MockAuthProfileDAO) GetByID(ctx context.Context, id uint) (*model.AuthProfile, error) {
+	args := m.Called(ctx, id)
+	profile, _ := args.Get(0).(*model.AuthProfile)
+	return profile, args.Error(1)
+}
+
+func (m *MockAuthProfileDAO) GetByName(ctx context.Context, name string) (*model.AuthProfile, error) {
+	args := m.Called(ctx, name)
+	profile, _ := args.Get(0).(*model.AuthProfile)
+	return profile, args.Error(1)
+}
+
+func (m *MockAuthProfileDAO) List(ctx context.Context) ([]model.AuthProfile, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.AuthProfile), args.Error(1)
+}
+
+func (m *MockAuthProfileDAO) GetByDocumentID(ctx context.Context, swaggerID uint) (*model.AuthProfile, error) {
+	args := m.Called(ctx, swaggerID)
+	profile, _ := args.Get(0).(*model.AuthProfile)
+	return profile, args.Error(1)
+}
+
+func (m *MockAuthProfileDAO) BindDocument(ctx context.Context, swaggerID uint, profileID *uint) error {
+	args := m.Called(ctx, swaggerID, profileID)
+	return args.Error(0)
+}
+
 // MockHTTPClient 模拟 HTTPClient
 type MockHTTPClient struct {
 	mock.Mock
 }
 
-func (m *MockHTTPClient) DoRequest(ctx context.Context, method, url string, body io.Reader) (string, error) {
-	args := m.Called(ctx, method, url, body)
-	return args.String(0), args.Error(1)
+func (m *MockHTTPClient) DoRequest(ctx context.Context, method, url string, headers map[string]string, body io.Reader) (*httpclient.Response, error) {
+	args := m.Called(ctx, method, url, headers, body)
+	resp, _ := args.Get(0).(*httpclient.Response)
+	return resp, args.Error(1)
 }
 
 // 测试数据
@@ -107,6 +365,21 @@ var sampleEndpoint = &model.APIEndpoint{
 	Body:    "",
 }
 
+// newTestSwaggerService 使用模拟依赖构造 swaggerService
+func newTestSwaggerService(openapi3Parser *MockSwaggerParser, endpointDAO *MockAPIEndpointDAO, httpClient *MockHTTPClient) *swaggerService {
+	return &swaggerService{
+		swagger2Parser:  new(MockSwagger2Parser),
+		openapi3Parser:  openapi3Parser,
+		openapi31Parser: new(MockSwaggerParser),
+		dao:             endpointDAO,
+		documentDAO:     new(MockSwaggerDocumentDAO),
+		revisionDAO:     new(MockSwaggerRevisionDAO),
+		profileDAO:      new(MockAuthProfileDAO),
+		httpClient:      httpClient,
+		executor:        NewAPIExecutor(httpClient, nil),
+	}
+}
+
 // TestMain 设置测试环境
 func TestMain(m *testing.M) {
 	// 运行测试
@@ -117,12 +390,10 @@ func TestSwaggerService_ParseAndSave(t *testing.T) {
 	mockParser := new(MockSwaggerParser)
 	mockDAO := new(MockAPIEndpointDAO)
 	mockHTTPClient := new(MockHTTPClient)
+	mockDocumentDAO := new(MockSwaggerDocumentDAO)
 
-	service := &swaggerService{
-		parser:     mockParser,
-		dao:        mockDAO,
-		httpClient: mockHTTPClient,
-	}
+	service := newTestSwaggerService(mockParser, mockDAO, mockHTTPClient)
+	service.documentDAO = mockDocumentDAO
 
 	ctx := context.Background()
 	swaggerContent := []byte(`{"openapi": "3.0.0"}`)
@@ -133,15 +404,16 @@ func TestSwaggerService_ParseAndSave(t *testing.T) {
 	mockParser.On("ParseFromData", swaggerContent).Return(expectedDoc, nil)
 	mockParser.On("Validate", expectedDoc).Return(nil)
 	mockParser.On("ExtractAPIEndpoints", expectedDoc).Return(expectedEndpoints)
-	mockDAO.On("Create", ctx, mock.AnythingOfType("*model.APIEndpoint")).Return(nil)
+	mockDocumentDAO.On("SaveImport", ctx, mock.AnythingOfType("*model.SwaggerDocument"), mock.AnythingOfType("*dao.EndpointChangeSet"), mock.Anything).Return(nil)
 
 	// Execute
-	result, err := service.ParseAndSave(ctx, swaggerContent)
+	result, err := service.ParseAndSave(ctx, swaggerContent, "tester")
 
 	// Assertions
 	assert.NoError(t, err)
 	assert.Equal(t, expectedEndpoints, result)
 	mockParser.AssertExpectations(t)
+	mockDocumentDAO.AssertExpectations(t)
 	mockDAO.AssertExpectations(t)
 }
 
@@ -150,20 +422,16 @@ func TestSwaggerService_ParseAndSave_ParseError(t *testing.T) {
 	mockDAO := new(MockAPIEndpointDAO)
 	mockHTTPClient := new(MockHTTPClient)
 
-	service := &swaggerService{
-		parser:     mockParser,
-		dao:        mockDAO,
-		httpClient: mockHTTPClient,
-	}
+	service := newTestSwaggerService(mockParser, mockDAO, mockHTTPClient)
 
 	ctx := context.Background()
-	swaggerContent := []byte(`invalid json`)
+	swaggerContent := []byte(`{"openapi": "3.0.0", "info": 1}`)
 
 	// Mock expectations
 	mockParser.On("ParseFromData", swaggerContent).Return(nil, errors.New("parse error"))
 
 	// Execute
-	result, err := service.ParseAndSave(ctx, swaggerContent)
+	result, err := service.ParseAndSave(ctx, swaggerContent, "tester")
 
 	// Assertions
 	assert.Error(t, err)
@@ -172,16 +440,61 @@ func TestSwaggerService_ParseAndSave_ParseError(t *testing.T) {
 	mockParser.AssertExpectations(t)
 }
 
+func TestSwaggerService_ParseAndSave_Swagger2MentioningOpenAPI3(t *testing.T) {
+	mockParser := new(MockSwaggerParser)
+	mockDAO := new(MockAPIEndpointDAO)
+	mockHTTPClient := new(MockHTTPClient)
+	mockSwagger2Parser := new(MockSwagger2Parser)
+	mockDocumentDAO := new(MockSwaggerDocumentDAO)
+
+	service := newTestSwaggerService(mockParser, mockDAO, mockHTTPClient)
+	service.swagger2Parser = mockSwagger2Parser
+	service.documentDAO = mockDocumentDAO
+
+	ctx := context.Background()
+	// 描述中同时出现 openapi 与 3.，旧的字符串匹配会误判为 OpenAPI 3
+	swaggerContent := []byte("swagger: '2.0'\ninfo:\n  title: Migrating to openapi 3.1 soon\n  version: '1.0'\npaths: {}\n")
+	expectedDoc := &openapi2.T{Swagger: "2.0"}
+
+	// Mock expectations
+	mockSwagger2Parser.On("ParseFromData", mock.Anything).Return(expectedDoc, nil)
+	mockSwagger2Parser.On("Validate", expectedDoc).Return(nil)
+	mockSwagger2Parser.On("ExtractAPIEndpoints", expectedDoc).Return([]model.APIEndpoint{})
+	mockDocumentDAO.On("SaveImport", ctx, mock.AnythingOfType("*model.SwaggerDocument"), mock.AnythingOfType("*dao.EndpointChangeSet"), mock.Anything).Return(nil)
+
+	// Execute
+	_, err := service.ParseAndSave(ctx, swaggerContent, "tester")
+
+	// Assertions
+	assert.NoError(t, err)
+	mockSwagger2Parser.AssertExpectations(t)
+	mockParser.AssertNotCalled(t, "ParseFromData", mock.Anything)
+}
+
+func TestSwaggerService_ParseAndSave_UnsupportedVersion(t *testing.T) {
+	mockParser := new(MockSwaggerParser)
+	mockDAO := new(MockAPIEndpointDAO)
+	mockHTTPClient := new(MockHTTPClient)
+
+	service := newTestSwaggerService(mockParser, mockDAO, mockHTTPClient)
+
+	// Execute
+	result, err := service.ParseAndSave(context.Background(), []byte(`{"swaggerVersion": "1.2", "apis": []}`), "tester")
+
+	// Assertions
+	assert.Nil(t, result)
+	var versionErr *parser.UnsupportedVersionError
+	assert.ErrorAs(t, err, &versionErr)
+	assert.Equal(t, "1.2", versionErr.Version)
+	mockParser.AssertNotCalled(t, "ParseFromData", mock.Anything)
+}
+
 func TestSwaggerService_ListAPIEndpoints(t *testing.T) {
 	mockParser := new(MockSwaggerParser)
 	mockDAO := new(MockAPIEndpointDAO)
 	mockHTTPClient := new(MockHTTPClient)
 
-	service := &swaggerService{
-		parser:     mockParser,
-		dao:        mockDAO,
-		httpClient: mockHTTPClient,
-	}
+	service := newTestSwaggerService(mockParser, mockDAO, mockHTTPClient)
 
 	ctx := context.Background()
 	swaggerID := uint(1)
@@ -199,16 +512,28 @@ func TestSwaggerService_ListAPIEndpoints(t *testing.T) {
 	mockDAO.AssertExpectations(t)
 }
 
+func TestSwaggerService_SearchAPIEndpoints(t *testing.T) {
+	mockDAO := new(MockAPIEndpointDAO)
+	service := newTestSwaggerService(new(MockSwaggerParser), mockDAO, new(MockHTTPClient))
+
+	ctx := context.Background()
+	query := dao.EndpointQuery{SwaggerID: 1, Method: "get", Keyword: "test", Page: 1, Size: 10}
+	expected := &dao.EndpointPage{Items: []model.APIEndpoint{*sampleEndpoint}, Total: 1}
+	mockDAO.On("Search", ctx, query).Return(expected, nil)
+
+	result, err := service.SearchAPIEndpoints(ctx, query)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	mockDAO.AssertExpectations(t)
+}
+
 func TestSwaggerService_GetAPIEndpointByID(t *testing.T) {
 	mockParser := new(MockSwaggerParser)
 	mockDAO := new(MockAPIEndpointDAO)
 	mockHTTPClient := new(MockHTTPClient)
 
-	service := &swaggerService{
-		parser:     mockParser,
-		dao:        mockDAO,
-		httpClient: mockHTTPClient,
-	}
+	service := newTestSwaggerService(mockParser, mockDAO, mockHTTPClient)
 
 	ctx := context.Background()
 	endpointID := uint(1)
@@ -230,11 +555,7 @@ func TestSwaggerService_GetAPIEndpointByID_NotFound(t *testing.T) {
 	mockDAO := new(MockAPIEndpointDAO)
 	mockHTTPClient := new(MockHTTPClient)
 
-	service := &swaggerService{
-		parser:     mockParser,
-		dao:        mockDAO,
-		httpClient: mockHTTPClient,
-	}
+	service := newTestSwaggerService(mockParser, mockDAO, mockHTTPClient)
 
 	ctx := context.Background()
 	endpointID := uint(999)
@@ -257,11 +578,7 @@ func TestSwaggerService_DeleteAPIEndpoint(t *testing.T) {
 	mockDAO := new(MockAPIEndpointDAO)
 	mockHTTPClient := new(MockHTTPClient)
 
-	service := &swaggerService{
-		parser:     mockParser,
-		dao:        mockDAO,
-		httpClient: mockHTTPClient,
-	}
+	service := newTestSwaggerService(mockParser, mockDAO, mockHTTPClient)
 
 	ctx := context.Background()
 	endpointID := uint(1)
@@ -282,11 +599,7 @@ func TestSwaggerService_UpdateAPIEndpoint(t *testing.T) {
 	mockDAO := new(MockAPIEndpointDAO)
 	mockHTTPClient := new(MockHTTPClient)
 
-	service := &swaggerService{
-		parser:     mockParser,
-		dao:        mockDAO,
-		httpClient: mockHTTPClient,
-	}
+	service := newTestSwaggerService(mockParser, mockDAO, mockHTTPClient)
 
 	ctx := context.Background()
 
@@ -301,30 +614,57 @@ func TestSwaggerService_UpdateAPIEndpoint(t *testing.T) {
 	mockDAO.AssertExpectations(t)
 }
 
+func TestSwaggerService_UpdateAPIEndpoint_Invalid(t *testing.T) {
+	mockDAO := new(MockAPIEndpointDAO)
+	service := newTestSwaggerService(new(MockSwaggerParser), mockDAO, new(MockHTTPClient))
+
+	// 不以 / 开头的路径会改变请求的主机，例如 https://api.example.com@evil.example/x
+	endpoint := &model.APIEndpoint{ID: 1, SwaggerID: 1, Path: "@evil.example/x", Method: "GET"}
+	err := service.UpdateAPIEndpoint(context.Background(), endpoint)
+
+	var invalid *EndpointValidationError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, []FieldError{{Field: "path", Message: "must start with /"}}, invalid.Fields)
+	mockDAO.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestSwaggerService_TestAPIEndpoint_PathChangesHost(t *testing.T) {
+	mockDAO := new(MockAPIEndpointDAO)
+	mockHTTPClient := new(MockHTTPClient)
+	service := newTestSwaggerService(new(MockSwaggerParser), mockDAO, mockHTTPClient)
+
+	ctx := context.Background()
+	endpoint := &model.APIEndpoint{ID: 1, SwaggerID: 1, Path: "@evil.example/x", Method: "GET"}
+	mockDAO.On("GetByID", ctx, uint(1)).Return(endpoint, nil)
+
+	_, err := service.TestAPIEndpoint(ctx, endpoint, "https://api.example.com")
+	assert.ErrorContains(t, err, "does not point to the host of base url https://api.example.com")
+	mockHTTPClient.AssertNotCalled(t, "DoRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestSwaggerService_TestAPIEndpoint(t *testing.T) {
 	mockParser := new(MockSwaggerParser)
 	mockDAO := new(MockAPIEndpointDAO)
 	mockHTTPClient := new(MockHTTPClient)
 
-	service := &swaggerService{
-		parser:     mockParser,
-		dao:        mockDAO,
-		httpClient: mockHTTPClient,
-	}
+	service := newTestSwaggerService(mockParser, mockDAO, mockHTTPClient)
+	service.documentDAO.(*MockSwaggerDocumentDAO).On("GetByID", mock.Anything, uint(1)).Return(nil, gorm.ErrRecordNotFound)
 
 	ctx := context.Background()
 	baseURL := "http://localhost:8080"
-	expectedResponse := "success response"
+	expectedResponse := &httpclient.Response{StatusCode: 200, Status: "200 OK", Body: "success response"}
 
 	// Mock expectations - need to be more flexible with body matcher
-	mockHTTPClient.On("DoRequest", ctx, "GET", "http://localhost:8080/test/123?param1=value1", mock.Anything).Return(expectedResponse, nil)
+	mockHTTPClient.On("DoRequest", ctx, "GET", "http://localhost:8080/test/123?param1=value1", mock.Anything, mock.Anything).Return(expectedResponse, nil)
 
 	// Execute
+	mockDAO.On("GetByID", ctx, uint(1)).Return(sampleEndpoint, nil)
 	result, err := service.TestAPIEndpoint(ctx, sampleEndpoint, baseURL)
 
 	// Assertions
 	assert.NoError(t, err)
-	assert.Equal(t, expectedResponse, result)
+	assert.Equal(t, expectedResponse, result.Response)
+	assert.Contains(t, result.ValidationSkipped, "swagger document 1")
 	mockHTTPClient.AssertExpectations(t)
 }
 
@@ -333,15 +673,12 @@ func TestSwaggerService_TestAPIEndpoint_WithBody(t *testing.T) {
 	mockDAO := new(MockAPIEndpointDAO)
 	mockHTTPClient := new(MockHTTPClient)
 
-	service := &swaggerService{
-		parser:     mockParser,
-		dao:        mockDAO,
-		httpClient: mockHTTPClient,
-	}
+	service := newTestSwaggerService(mockParser, mockDAO, mockHTTPClient)
+	service.documentDAO.(*MockSwaggerDocumentDAO).On("GetByID", mock.Anything, uint(1)).Return(nil, gorm.ErrRecordNotFound)
 
 	ctx := context.Background()
 	baseURL := "http://localhost:8080"
-	expectedResponse := "success response"
+	expectedResponse := &httpclient.Response{StatusCode: 200, Status: "200 OK", Body: "success response"}
 
 	// Create endpoint with POST method and body
 	postEndpoint := &model.APIEndpoint{
@@ -354,14 +691,16 @@ func TestSwaggerService_TestAPIEndpoint_WithBody(t *testing.T) {
 	}
 
 	// Mock expectations
-	mockHTTPClient.On("DoRequest", ctx, "POST", "http://localhost:8080/test", mock.Anything).Return(expectedResponse, nil)
+	mockHTTPClient.On("DoRequest", ctx, "POST", "http://localhost:8080/test", mock.Anything, mock.Anything).Return(expectedResponse, nil)
 
 	// Execute
+	mockDAO.On("GetByID", ctx, uint(1)).Return(postEndpoint, nil)
 	result, err := service.TestAPIEndpoint(ctx, postEndpoint, baseURL)
 
 	// Assertions
 	assert.NoError(t, err)
-	assert.Equal(t, expectedResponse, result)
+	assert.Equal(t, expectedResponse, result.Response)
+	assert.Contains(t, result.ValidationSkipped, "swagger document 1")
 	mockHTTPClient.AssertExpectations(t)
 }
 
@@ -370,11 +709,7 @@ func TestSwaggerService_TestAPIEndpoint_MissingRequiredParam(t *testing.T) {
 	mockDAO := new(MockAPIEndpointDAO)
 	mockHTTPClient := new(MockHTTPClient)
 
-	service := &swaggerService{
-		parser:     mockParser,
-		dao:        mockDAO,
-		httpClient: mockHTTPClient,
-	}
+	service := newTestSwaggerService(mockParser, mockDAO, mockHTTPClient)
 
 	ctx := context.Background()
 	baseURL := "http://localhost:8080"
@@ -391,11 +726,12 @@ func TestSwaggerService_TestAPIEndpoint_MissingRequiredParam(t *testing.T) {
 	}
 
 	// Execute
+	mockDAO.On("GetByID", ctx, uint(1)).Return(endpointWithMissingParam, nil)
 	result, err := service.TestAPIEndpoint(ctx, endpointWithMissingParam, baseURL)
 
 	// Assertions
 	assert.Error(t, err)
-	assert.Empty(t, result)
+	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "missing required path parameter: id")
 }
 
@@ -404,24 +740,22 @@ func TestSwaggerService_TestAPIEndpoint_HTTPError(t *testing.T) {
 	mockDAO := new(MockAPIEndpointDAO)
 	mockHTTPClient := new(MockHTTPClient)
 
-	service := &swaggerService{
-		parser:     mockParser,
-		dao:        mockDAO,
-		httpClient: mockHTTPClient,
-	}
+	service := newTestSwaggerService(mockParser, mockDAO, mockHTTPClient)
+	service.documentDAO.(*MockSwaggerDocumentDAO).On("GetByID", mock.Anything, uint(1)).Return(nil, gorm.ErrRecordNotFound)
 
 	ctx := context.Background()
 	baseURL := "http://localhost:8080"
 
 	// Mock expectations
-	mockHTTPClient.On("DoRequest", ctx, "GET", "http://localhost:8080/test/123?param1=value1", mock.Anything).Return("", errors.New("connection failed"))
+	mockHTTPClient.On("DoRequest", ctx, "GET", "http://localhost:8080/test/123?param1=value1", mock.Anything, mock.Anything).Return(nil, errors.New("connection failed"))
 
 	// Execute
+	mockDAO.On("GetByID", ctx, uint(1)).Return(sampleEndpoint, nil)
 	result, err := service.TestAPIEndpoint(ctx, sampleEndpoint, baseURL)
 
 	// Assertions
 	assert.Error(t, err)
-	assert.Empty(t, result)
+	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "connection failed")
 	mockHTTPClient.AssertExpectations(t)
 }
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// defaultFetchTimeout 拉取文档的默认超时时间
+	defaultFetchTimeout = 30 * time.Second
+	// maxSpecSize 拉取文档的最大字节数，与 swagger_documents.content 的容量一致
+	maxSpecSize = 16 << 20
+)
+
+// SpecFetcher 从远程地址拉取 swagger 文档
+type SpecFetcher interface {
+	// Fetch 拉取 sourceURL 的内容，authHeader 非空时作为 Authorization 请求头发送
+	Fetch(ctx context.Context, sourceURL, authHeader string) ([]byte, error)
+}
+
+// httpSpecFetcher 通过 HTTP GET 拉取文档
+type httpSpecFetcher struct {
+	client *http.Client
+}
+
+// NewSpecFetcher 创建一个通过 HTTP 拉取文档的 SpecFetcher，timeout 不大于 0 时使用默认超时
+func NewSpecFetcher(timeout time.Duration) SpecFetcher {
+	if timeout <= 0 {
+		timeout = defaultFetchTimeout
+	}
+	return &httpSpecFetcher{client: &http.Client{Timeout: timeout}}
+}
+
+func (f *httpSpecFetcher) Fetch(ctx context.Context, sourceURL, authHeader string) ([]byte, error) {
+	if err := validateSourceURL(sourceURL); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json, application/yaml;q=0.9, */*;q=0.8")
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s failed: %v", sourceURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("fetch %s failed: unexpected status %s", sourceURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSpecSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s failed: %v", sourceURL, err)
+	}
+	if len(data) > maxSpecSize {
+		return nil, fmt.Errorf("fetch %s failed: document exceeds %d bytes", sourceURL, maxSpecSize)
+	}
+	return data, nil
+}
+
+// validateSourceURL 仅允许 http 与 https 地址
+func validateSourceURL(sourceURL string) error {
+	u, err := url.Parse(sourceURL)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %v", sourceURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url %q: only http and https urls are supported", sourceURL)
+	}
+	return nil
+}
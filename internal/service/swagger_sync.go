@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SyncScheduler 定时重新拉取通过 URL 导入的文档，内容变化时更新其接口
+type SyncScheduler struct {
+	service  SwaggerService
+	interval time.Duration
+}
+
+// NewSyncScheduler 创建一个每隔 interval 同步一次所有 URL 来源文档的调度器
+func NewSyncScheduler(service SwaggerService, interval time.Duration) *SyncScheduler {
+	return &SyncScheduler{service: service, interval: interval}
+}
+
+// Start 在后台运行同步循环直到 ctx 被取消，interval 不大于 0 时不启动
+func (s *SyncScheduler) Start(ctx context.Context) {
+	if s.interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.SyncAll(ctx)
+			}
+		}
+	}()
+}
+
+// SyncAll 依次同步所有带有来源地址的文档，返回内容发生变化的文档数
+// 单个文档同步失败只记录日志，不影响其余文档
+func (s *SyncScheduler) SyncAll(ctx context.Context) int {
+	docs, err := s.service.ListDocuments(ctx)
+	if err != nil {
+		log.Errorf("list swagger documents for sync failed: %v", err)
+		return 0
+	}
+	changed := 0
+	for _, doc := range docs {
+		if doc.SourceURL == "" {
+			continue
+		}
+		if ctx.Err() != nil {
+			return changed
+		}
+		updated, err := s.service.SyncDocument(ctx, doc.ID)
+		if err != nil {
+			log.Warnf("sync swagger document %d from %s failed: %v", doc.ID, doc.SourceURL, err)
+			continue
+		}
+		if updated {
+			changed++
+			log.Infof("swagger document %d re-imported from %s", doc.ID, doc.SourceURL)
+		}
+	}
+	return changed
+}
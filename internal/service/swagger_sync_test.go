@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/utils/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const specV1 = `{"openapi": "3.0.0", "info": {"title": "Pets", "version": "1.0"}, "paths": {
+  "/pets": {"get": {"operationId": "listPets", "responses": {"200": {"description": "OK"}}}}}}`
+
+const specV2 = `{"openapi": "3.0.0", "info": {"title": "Pets", "version": "2.0"}, "paths": {
+  "/pets": {"get": {"operationId": "listPets", "responses": {"200": {"description": "OK"}}}},
+  "/pets/{id}": {"get": {"operationId": "getPet", "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}], "responses": {"200": {"description": "OK"}}}}}}`
+
+// specSource 模拟发布 swagger 文档的服务，记录收到的 Authorization 头
+type specSource struct {
+	*httptest.Server
+	content atomic.Value
+	auth    atomic.Value
+	status  atomic.Int32
+}
+
+func newSpecSource(t *testing.T, content string) *specSource {
+	s := &specSource{}
+	s.content.Store(content)
+	s.auth.Store("")
+	s.status.Store(http.StatusOK)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.auth.Store(r.Header.Get("Authorization"))
+		w.WriteHeader(int(s.status.Load()))
+		_, _ = w.Write([]byte(s.content.Load().(string)))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// saveImport 模拟 SaveImport 为文档与新增的接口分配 ID
+func saveImport(docID uint) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		doc := args.Get(1).(*model.SwaggerDocument)
+		if doc.ID == 0 {
+			doc.ID = docID
+		}
+		changes := args.Get(2).(*dao.EndpointChangeSet)
+		for i := range changes.Create {
+			changes.Create[i].ID = uint(100 + i)
+			changes.Create[i].SwaggerID = doc.ID
+		}
+	}
+}
+
+// newURLTestSwaggerService 使用真实的解析器与拉取器、模拟的 DAO 构造 swaggerService
+func newURLTestSwaggerService(endpointDAO *MockAPIEndpointDAO, documentDAO *MockSwaggerDocumentDAO) *swaggerService {
+	return &swaggerService{
+		swagger2Parser:  parser.NewSwagger2Parser(),
+		openapi3Parser:  parser.NewOpenAPI3Parser(),
+		openapi31Parser: parser.NewOpenAPI31Parser(),
+		specParser:      parser.NewSwaggerParser(),
+		dao:             endpointDAO,
+		documentDAO:     documentDAO,
+		revisionDAO:     new(MockSwaggerRevisionDAO),
+		secrets:         newSecretStore(new(MockSecretDAO), nil),
+		fetcher:         NewSpecFetcher(5 * time.Second),
+		breakingPolicy:  BreakingChangePolicyBlock,
+	}
+}
+
+func TestSwaggerService_ImportFromURL(t *testing.T) {
+	source := newSpecSource(t, specV1)
+	mockDAO := new(MockAPIEndpointDAO)
+	mockDocumentDAO := new(MockSwaggerDocumentDAO)
+	service := newURLTestSwaggerService(mockDAO, mockDocumentDAO)
+
+	ctx := context.Background()
+	secretDAO := new(MockSecretDAO)
+	service.secrets = newSecretStore(secretDAO, testKeyring(t, "", "k1"))
+	var saved *model.Secret
+	secretDAO.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*model.Secret)
+	}).Return(nil)
+	mockDocumentDAO.On("GetBySourceURL", ctx, source.URL+"/v3/api-docs").Return(nil, gorm.ErrRecordNotFound)
+	mockDocumentDAO.On("SaveImport", ctx, mock.AnythingOfType("*model.SwaggerDocument"), mock.AnythingOfType("*dao.EndpointChangeSet"), mock.Anything).
+		Run(saveImport(7)).Return(nil)
+
+	doc, endpoints, err := service.ImportFromURL(ctx, source.URL+"/v3/api-docs", "Bearer token", "tester")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token", source.auth.Load())
+	assert.Equal(t, source.URL+"/v3/api-docs", doc.SourceURL)
+	// 请求头保存为 secret，文档中只记录引用
+	require.NotNil(t, saved)
+	assert.True(t, strings.HasPrefix(saved.Name, "auto.swagger_document.source_auth_header."), saved.Name)
+	assert.NotEmpty(t, saved.Ciphertext)
+	assert.Equal(t, "{{secret:"+saved.Name+"}}", doc.SourceAuthHeader)
+	assert.Equal(t, checksum([]byte(specV1)), doc.Checksum)
+	assert.NotNil(t, doc.LastSyncedAt)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, uint(7), endpoints[0].SwaggerID)
+	mockDocumentDAO.AssertExpectations(t)
+	mockDAO.AssertExpectations(t)
+}
+
+func TestSwaggerService_ImportFromURL_Errors(t *testing.T) {
+	source := newSpecSource(t, "not found")
+	source.status.Store(http.StatusNotFound)
+	service := newURLTestSwaggerService(new(MockAPIEndpointDAO), new(MockSwaggerDocumentDAO))
+
+	_, _, err := service.ImportFromURL(context.Background(), source.URL, "", "tester")
+	assert.ErrorContains(t, err, "unexpected status 404")
+
+	_, _, err = service.ImportFromURL(context.Background(), "file:///etc/passwd", "", "tester")
+	assert.ErrorContains(t, err, "only http and https")
+	// 来源地址由调用方指定，请求头不能引用已保存的 secret
+	_, _, err = service.ImportFromURL(context.Background(), source.URL, "Bearer {{secret:PET_TOKEN}}", "tester")
+	assert.ErrorContains(t, err, "must not contain secret references")
+	// 未配置主密钥时无法保存请求头，拉取前即返回错误
+	_, _, err = service.ImportFromURL(context.Background(), source.URL, "Bearer token", "tester")
+	assert.ErrorIs(t, err, ErrSecretsNotConfigured)
+	assert.Empty(t, source.auth.Load())
+}
+
+func TestSwaggerService_SyncDocument(t *testing.T) {
+	source := newSpecSource(t, specV1)
+	mockDAO := new(MockAPIEndpointDAO)
+	mockDocumentDAO := new(MockSwaggerDocumentDAO)
+	service := newURLTestSwaggerService(mockDAO, mockDocumentDAO)
+
+	ctx := context.Background()
+	keyring := testKeyring(t, "", "k1")
+	secretDAO := new(MockSecretDAO)
+	service.secrets = newSecretStore(secretDAO, keyring)
+	secretDAO.On("ListByNames", ctx, []string{"auto.swagger_document.source_auth_header.1"}).
+		Return([]model.Secret{sealedSecret(t, keyring, 1, "auto.swagger_document.source_auth_header.1", "Bearer token")}, nil)
+	existing := &model.SwaggerDocument{
+		ID:               7,
+		Version:          "1.0",
+		Checksum:         checksum([]byte(specV1)),
+		SourceURL:        source.URL,
+		SourceAuthHeader: "{{secret:auto.swagger_document.source_auth_header.1}}",
+	}
+	mockDocumentDAO.On("GetByID", ctx, uint(7)).Return(existing, nil)
+	mockDocumentDAO.On("Update", ctx, existing).Return(nil)
+	mockDocumentDAO.On("Reimport", ctx, uint(7)).Return(&dao.ImportState{Document: existing, Endpoints: []model.APIEndpoint{{ID: 1, SwaggerID: 7, Method: "GET", Path: "/pets", OperationID: "listPets"}}}, nil)
+
+	// 内容未变化时只记录同步时间
+	changed, err := service.SyncDocument(ctx, 7)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.NotNil(t, existing.LastSyncedAt)
+	assert.Equal(t, "Bearer token", source.auth.Load())
+	mockDocumentDAO.AssertNotCalled(t, "SaveImport", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// 内容变化时按差异重新导入接口
+	source.content.Store(specV2)
+	var changes *dao.EndpointChangeSet
+	mockDocumentDAO.On("SaveImport", ctx, existing, mock.AnythingOfType("*dao.EndpointChangeSet"), mock.Anything).Run(func(args mock.Arguments) {
+		changes = args.Get(2).(*dao.EndpointChangeSet)
+	}).Return(nil).Once()
+	changed, err = service.SyncDocument(ctx, 7)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "2.0", existing.Version)
+	assert.Equal(t, checksum([]byte(specV2)), existing.Checksum)
+	assert.Empty(t, existing.SyncError)
+	require.NotNil(t, changes)
+	assert.Len(t, changes.Create, 1)
+	assert.Empty(t, changes.Delete)
+	mockDocumentDAO.AssertExpectations(t)
+
+	// 拉取失败时保留原有内容并记录失败原因
+	source.status.Store(http.StatusInternalServerError)
+	changed, err = service.SyncDocument(ctx, 7)
+	assert.Error(t, err)
+	assert.False(t, changed)
+	assert.Contains(t, existing.SyncError, "unexpected status 500")
+	assert.Equal(t, checksum([]byte(specV2)), existing.Checksum)
+}
+
+func TestSwaggerService_SyncDocument_NoSourceURL(t *testing.T) {
+	mockDocumentDAO := new(MockSwaggerDocumentDAO)
+	service := newURLTestSwaggerService(new(MockAPIEndpointDAO), mockDocumentDAO)
+
+	ctx := context.Background()
+	mockDocumentDAO.On("GetByID", ctx, uint(1)).Return(&model.SwaggerDocument{ID: 1}, nil)
+
+	_, err := service.SyncDocument(ctx, 1)
+	assert.ErrorContains(t, err, "not imported from a url")
+}
+
+func TestSyncScheduler_SyncAll(t *testing.T) {
+	source := newSpecSource(t, specV2)
+	mockDAO := new(MockAPIEndpointDAO)
+	mockDocumentDAO := new(MockSwaggerDocumentDAO)
+	service := newURLTestSwaggerService(mockDAO, mockDocumentDAO)
+
+	ctx := context.Background()
+	synced := &model.SwaggerDocument{ID: 1, SourceURL: source.URL, Checksum: checksum([]byte(specV1))}
+	mockDocumentDAO.On("List", ctx).Return([]model.SwaggerDocument{{ID: 1, SourceURL: source.URL}, {ID: 2}}, nil)
+	mockDocumentDAO.On("GetByID", ctx, uint(1)).Return(synced, nil)
+	mockDocumentDAO.On("SaveImport", ctx, synced, mock.AnythingOfType("*dao.EndpointChangeSet"), mock.Anything).Return(nil)
+	mockDocumentDAO.On("Reimport", ctx, uint(1)).Return(&dao.ImportState{Document: synced, Endpoints: []model.APIEndpoint{}}, nil)
+
+	assert.Equal(t, 1, NewSyncScheduler(service, time.Minute).SyncAll(ctx))
+	// 没有来源地址的文档不参与同步
+	mockDocumentDAO.AssertNotCalled(t, "GetByID", ctx, uint(2))
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"mcp-manager/internal/model"
+	httpclient "mcp-manager/internal/utils/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// ErrUntrustedBaseURL 表示接口引用了 secret，而测试的 base_url 既不是文档声明的服务器，也不在配置的 secrets.trusted_base_urls 中
+var ErrUntrustedBaseURL = errors.New("base_url is neither a server of the swagger document nor in secrets.trusted_base_urls, secrets of the endpoint are not sent to it")
+
+// EndpointTestResult 为接口测试的结果，包含上游响应以及响应与文档中声明的是否一致
+type EndpointTestResult struct {
+	*httpclient.Response
+	// Violations 为响应不符合文档声明的地方，为空表示响应符合约定
+	Violations []SchemaViolation `json:"violations"`
+	// ValidationSkipped 不为空时表示未校验响应及其原因，如接口不属于任何文档
+	ValidationSkipped string `json:"validation_skipped,omitempty"`
+}
+
+// RequestValidationError 表示请求参数不符合文档中声明的类型、枚举、格式等约束，请求未被发送
+type RequestValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *RequestValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+// SchemaViolation 为一处不符合文档声明的地方
+type SchemaViolation struct {
+	Path    string `json:"path"`    // 响应体中的 JSON Pointer，如 /items/0/id，状态码与响应头的问题为空
+	Message string `json:"message"` // 不符合的原因
+}
+
+// endpointDocument 返回 endpoint 所属的文档，文档不存在时返回的 error 说明原因
+func (s *swaggerService) endpointDocument(ctx context.Context, endpoint *model.APIEndpoint) (*model.SwaggerDocument, error) {
+	if endpoint.SwaggerID == 0 {
+		return nil, fmt.Errorf("endpoint does not belong to a swagger document")
+	}
+	doc, err := s.documentDAO.GetByID(ctx, endpoint.SwaggerID)
+	if err != nil {
+		return nil, fmt.Errorf("swagger document %d: %w", endpoint.SwaggerID, err)
+	}
+	return doc, nil
+}
+
+// containsBaseURL 判断 baseURL 是否为 servers 之一，忽略末尾的 /
+func containsBaseURL(servers []string, baseURL string) bool {
+	baseURL = strings.TrimRight(baseURL, "/")
+	for _, server := range servers {
+		if server != "" && strings.TrimRight(server, "/") == baseURL {
+			return true
+		}
+	}
+	return false
+}
+
+// trustedBaseURL 判断 baseURL 是否为 doc 声明的服务器或配置中信任的地址
+// MCP Server 的上游地址可由任意调用方设置，不作为信任的依据
+func (s *swaggerService) trustedBaseURL(doc *model.SwaggerDocument, baseURL string) bool {
+	if doc != nil && containsBaseURL(doc.Servers, baseURL) {
+		return true
+	}
+	return containsBaseURL(s.trustedBaseURLs, baseURL)
+}
+
+// mergeTestValues 返回以 submitted 中的参数取值、请求头与请求体替换后的 stored 副本，submitted 中未提交的部分保持保存的配置
+// 提交的路径或方法与保存的不同、或提交的取值引用了保存的配置中没有的 secret 时返回 *RequestValidationError，
+// 以免调用方借助接口测试将任意 secret 发送出去
+func mergeTestValues(stored, submitted *model.APIEndpoint) (*model.APIEndpoint, error) {
+	var invalid []FieldError
+	if submitted.Path != "" && submitted.Path != stored.Path {
+		invalid = append(invalid, FieldError{Field: "path", Message: "must be the path of the stored endpoint, update the endpoint before testing"})
+	}
+	if submitted.Method != "" && !strings.EqualFold(submitted.Method, stored.Method) {
+		invalid = append(invalid, FieldError{Field: "method", Message: "must be the method of the stored endpoint, update the endpoint before testing"})
+	}
+	// 提交的取值与保存的完全相同时允许引用 secret，页面会原样提交接口的完整配置
+	checkRefs := func(field, value, storedValue string) {
+		if value != storedValue && model.SecretRefPattern.MatchString(value) {
+			invalid = append(invalid, FieldError{Field: field, Message: "secret references are not allowed"})
+		}
+	}
+
+	merged := *stored
+	if submitted.Parameters != nil {
+		storedValues := make(map[string]string, len(stored.Parameters))
+		for _, param := range stored.Parameters {
+			storedValues[param.In+"."+param.Name] = param.Value
+		}
+		for _, param := range submitted.Parameters {
+			field := param.In + "." + param.Name
+			checkRefs(field, param.Value, storedValues[field])
+		}
+		merged.Parameters = submitted.Parameters
+	}
+	if submitted.Headers != nil {
+		for name, value := range submitted.Headers {
+			checkRefs("header."+name, value, stored.Headers[name])
+		}
+		merged.Headers = submitted.Headers
+	}
+	if submitted.Body != "" {
+		checkRefs("body", submitted.Body, stored.Body)
+		merged.Body = submitted.Body
+	}
+	if len(invalid) > 0 {
+		return nil, &RequestValidationError{Fields: invalid}
+	}
+	return &merged, nil
+}
+
+// endpointHasSecretRefs 判断接口的参数取值、请求头或请求体中是否引用了 secret
+func endpointHasSecretRefs(endpoint *model.APIEndpoint) bool {
+	for _, param := range endpoint.Parameters {
+		if model.SecretRefPattern.MatchString(param.Value) {
+			return true
+		}
+	}
+	for _, v := range endpoint.Headers {
+		if model.SecretRefPattern.MatchString(v) {
+			return true
+		}
+	}
+	return model.SecretRefPattern.MatchString(endpoint.Body)
+}
+
+// endpointRoute 返回 endpoint 在所属文档 doc 中对应的操作，操作不存在时返回的 error 说明原因
+func (s *swaggerService) endpointRoute(doc *model.SwaggerDocument, endpoint *model.APIEndpoint) (*routers.Route, error) {
+	spec, err := s.specParser.ParseFromData([]byte(doc.Content))
+	if err != nil {
+		return nil, fmt.Errorf("parse swagger document %d: %w", endpoint.SwaggerID, err)
+	}
+	method := strings.ToUpper(endpoint.Method)
+	pathItem := spec.Paths.Find(endpoint.Path)
+	if pathItem == nil || pathItem.GetOperation(method) == nil {
+		return nil, fmt.Errorf("operation %s %s is not declared in swagger document %d", method, endpoint.Path, endpoint.SwaggerID)
+	}
+	return &routers.Route{
+		Spec:      spec,
+		Path:      endpoint.Path,
+		PathItem:  pathItem,
+		Method:    method,
+		Operation: pathItem.GetOperation(method),
+	}, nil
+}
+
+// validateRequest 按操作声明的参数与请求体校验 req，不符合时返回 *RequestValidationError
+// 鉴权信息不在校验范围内，由上游自行校验
+func validateRequest(ctx context.Context, route *routers.Route, endpoint *model.APIEndpoint, req *APIRequest) error {
+	var body io.Reader
+	if req.Body != "" {
+		body = strings.NewReader(req.Body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, body)
+	if err != nil {
+		return err
+	}
+	for k, v := range req.Headers {
+		httpReq.Header.Set(k, v)
+	}
+	pathParams := make(map[string]string)
+	for _, param := range endpoint.Parameters {
+		if param.In == "path" {
+			pathParams[param.Name] = param.Value
+		}
+	}
+	input := &openapi3filter.RequestValidationInput{
+		Request:    httpReq,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}
+	if err := openapi3filter.ValidateRequest(ctx, input); err != nil {
+		if fields := withoutSecretRefs(requestFieldErrors(err), endpoint, req); len(fields) > 0 {
+			return &RequestValidationError{Fields: fields}
+		}
+	}
+	return nil
+}
+
+// withoutSecretRefs 去掉取值为 secret 引用的参数、请求头与请求体字段的校验错误
+// 引用在发送时才替换为明文，校验的是引用本身而不是实际取值，明文也不应出现在校验结果中
+func withoutSecretRefs(fields []FieldError, endpoint *model.APIEndpoint, req *APIRequest) []FieldError {
+	refs := make(map[string]bool)
+	for _, param := range endpoint.Parameters {
+		if model.SecretRefPattern.MatchString(param.Value) {
+			refs[strings.ToLower(param.In+"."+param.Name)] = true
+		}
+	}
+	for k, v := range req.Headers {
+		if model.SecretRefPattern.MatchString(v) {
+			refs[strings.ToLower("header."+k)] = true
+		}
+	}
+	var body interface{}
+	if model.SecretRefPattern.MatchString(req.Body) {
+		_ = json.Unmarshal([]byte(req.Body), &body)
+	}
+
+	kept := make([]FieldError, 0, len(fields))
+	for _, f := range fields {
+		if refs[strings.ToLower(f.Field)] {
+			continue
+		}
+		if pointer, ok := strings.CutPrefix(f.Field, "body"); ok && body != nil && secretRefAt(body, pointer) {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// secretRefAt 判断 doc 中 JSON Pointer 指向的值是否为包含 secret 引用的字符串
+func secretRefAt(doc interface{}, pointer string) bool {
+	if pointer != "" {
+		for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			switch v := doc.(type) {
+			case map[string]interface{}:
+				doc = v[token]
+			case []interface{}:
+				i, err := strconv.Atoi(token)
+				if err != nil || i < 0 || i >= len(v) {
+					return false
+				}
+				doc = v[i]
+			default:
+				return false
+			}
+		}
+	}
+	s, ok := doc.(string)
+	return ok && model.SecretRefPattern.MatchString(s)
+}
+
+// requestFieldErrors 展开 openapi3filter 返回的错误，参数的字段名形如 query.limit，请求体的字段名形如 body/items/0
+func requestFieldErrors(err error) []FieldError {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var out []FieldError
+		for _, item := range e {
+			out = append(out, requestFieldErrors(item)...)
+		}
+		return out
+	case *openapi3filter.RequestError:
+		field := ""
+		switch {
+		case e.Parameter != nil:
+			field = e.Parameter.In + "." + e.Parameter.Name
+		case e.RequestBody != nil:
+			field = "body"
+		}
+		if e.Err == nil {
+			return []FieldError{{Field: field, Message: e.Reason}}
+		}
+		var out []FieldError
+		for _, v := range schemaViolations(e.Err, "") {
+			out = append(out, FieldError{Field: field + v.Path, Message: v.Message})
+		}
+		return out
+	default:
+		return []FieldError{{Message: err.Error()}}
+	}
+}
+
+// validateResponse 按操作声明的响应校验 resp，返回所有不符合的地方
+// 未声明的状态码视为不符合；文档未声明 default 响应时，只有声明过的状态码才会被接受
+func validateResponse(ctx context.Context, route *routers.Route, resp *httpclient.Response) []SchemaViolation {
+	req, err := http.NewRequestWithContext(ctx, route.Method, resp.URL, nil)
+	if err != nil {
+		return []SchemaViolation{{Message: err.Error()}}
+	}
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{Request: req, Route: route},
+		Status:                 resp.StatusCode,
+		Header:                 resp.Headers,
+		Body:                   io.NopCloser(strings.NewReader(resp.Body)),
+		Options:                &openapi3filter.Options{IncludeResponseStatus: true, MultiError: true},
+	}
+	if input.Header == nil {
+		input.Header = http.Header{}
+	}
+	if err := openapi3filter.ValidateResponse(ctx, input); err != nil {
+		return schemaViolations(err, "")
+	}
+	return []SchemaViolation{}
+}
+
+// schemaViolations 展开 openapi3filter 返回的错误，reason 为外层错误的说明
+func schemaViolations(err error, reason string) []SchemaViolation {
+	prefix := func(msg string) string {
+		if reason == "" {
+			return msg
+		}
+		return reason + ": " + msg
+	}
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var out []SchemaViolation
+		for _, item := range e {
+			out = append(out, schemaViolations(item, reason)...)
+		}
+		return out
+	case *openapi3filter.ResponseError:
+		if e.Err == nil {
+			return []SchemaViolation{{Message: prefix(e.Reason)}}
+		}
+		return schemaViolations(e.Err, e.Reason)
+	case *openapi3.SchemaError:
+		path := ""
+		if pointer := e.JSONPointer(); len(pointer) > 0 {
+			path = "/" + strings.Join(pointer, "/")
+		}
+		return []SchemaViolation{{Path: path, Message: prefix(e.Reason)}}
+	default:
+		return []SchemaViolation{{Message: prefix(err.Error())}}
+	}
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"mcp-manager/internal/model"
+	httpclient "mcp-manager/internal/utils/http"
+	"mcp-manager/internal/utils/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const contractSpec = `{"openapi": "3.0.0", "info": {"title": "Pets", "version": "1.0"}, "paths": {
+  "/pets/{id}": {"get": {"operationId": "getPet",
+    "parameters": [
+      {"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}},
+      {"name": "fields", "in": "query", "schema": {"type": "string", "enum": ["basic", "full"]}},
+      {"name": "X-Trace", "in": "header", "schema": {"type": "string", "pattern": "^[a-f0-9]+$"}}],
+    "responses": {
+      "200": {"description": "OK", "content": {"application/json": {"schema": {
+        "type": "object", "required": ["id", "name"],
+        "properties": {"id": {"type": "integer"}, "name": {"type": "string"}, "tags": {"type": "array", "items": {"type": "string"}}}}}}},
+      "404": {"description": "Not found"}}}},
+  "/pets": {"post": {"operationId": "createPet",
+    "requestBody": {"required": true, "content": {"application/json": {"schema": {
+      "type": "object", "required": ["name"],
+      "properties": {"name": {"type": "string", "maxLength": 10}, "birthday": {"type": "string", "format": "date"}}}}}},
+    "responses": {"201": {"description": "Created"}}}}}}`
+
+var petEndpoint = &model.APIEndpoint{
+	ID:        3,
+	SwaggerID: 7,
+	Path:      "/pets/{id}",
+	Method:    "GET",
+	Parameters: model.APIParameters{
+		{Name: "id", In: "path", Required: true, Type: "integer", Value: "1"},
+	},
+}
+
+// newContractService 构造以 contractSpec 为文档、上游返回 resp 的 swaggerService
+func newContractService(resp *httpclient.Response) *swaggerService {
+	httpClient := new(MockHTTPClient)
+	httpClient.On("DoRequest", mock.Anything, "GET", "http://pets.test/pets/1", mock.Anything, mock.Anything).Return(resp, nil)
+	s := newTestSwaggerService(new(MockSwaggerParser), new(MockAPIEndpointDAO), httpClient)
+	s.specParser = parser.NewSwaggerParser()
+	s.documentDAO.(*MockSwaggerDocumentDAO).On("GetByID", mock.Anything, uint(7)).
+		Return(&model.SwaggerDocument{ID: 7, Content: contractSpec, Servers: model.StringList{"http://pets.test"}}, nil)
+	storeEndpoint(s, petEndpoint)
+	return s
+}
+
+// storeEndpoint 使 s 按 endpoint.ID 查询到 endpoint，返回 endpoint
+func storeEndpoint(s *swaggerService, endpoint *model.APIEndpoint) *model.APIEndpoint {
+	s.dao.(*MockAPIEndpointDAO).On("GetByID", mock.Anything, endpoint.ID).Return(endpoint, nil)
+	return endpoint
+}
+
+func jsonResponse(status int, body string) *httpclient.Response {
+	return &httpclient.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Headers:    http.Header{"Content-Type": []string{"application/json"}},
+		Body:       body,
+		URL:        "http://pets.test/pets/1",
+	}
+}
+
+func TestSwaggerService_TestAPIEndpoint_ResponseMatchesSchema(t *testing.T) {
+	s := newContractService(jsonResponse(200, `{"id": 1, "name": "Rex", "tags": ["dog"]}`))
+
+	result, err := s.TestAPIEndpoint(context.Background(), petEndpoint, "http://pets.test")
+	require.NoError(t, err)
+	assert.Empty(t, result.ValidationSkipped)
+	assert.NotNil(t, result.Violations)
+	assert.Empty(t, result.Violations)
+}
+
+func TestSwaggerService_TestAPIEndpoint_ResponseViolations(t *testing.T) {
+	s := newContractService(jsonResponse(200, `{"id": "one", "tags": [1]}`))
+
+	result, err := s.TestAPIEndpoint(context.Background(), petEndpoint, "http://pets.test")
+	require.NoError(t, err)
+	paths := make([]string, len(result.Violations))
+	for i, v := range result.Violations {
+		paths[i] = v.Path
+		assert.Contains(t, v.Message, "response body doesn't match schema")
+	}
+	assert.ElementsMatch(t, []string{"/id", "/name", "/tags/0"}, paths)
+}
+
+func TestSwaggerService_TestAPIEndpoint_UndeclaredStatus(t *testing.T) {
+	s := newContractService(jsonResponse(500, `{"error": "boom"}`))
+
+	result, err := s.TestAPIEndpoint(context.Background(), petEndpoint, "http://pets.test")
+	require.NoError(t, err)
+	assert.Equal(t, 500, result.StatusCode)
+	require.Len(t, result.Violations, 1)
+	assert.Equal(t, "status is not supported", result.Violations[0].Message)
+}
+
+func TestSwaggerService_TestAPIEndpoint_UndeclaredOperation(t *testing.T) {
+	s := newContractService(jsonResponse(200, `{}`))
+	endpoint := *petEndpoint
+	endpoint.ID = 4
+	endpoint.Method = "DELETE"
+	s.httpClient.(*MockHTTPClient).On("DoRequest", mock.Anything, "DELETE", mock.Anything, mock.Anything, mock.Anything).Return(jsonResponse(204, ""), nil)
+
+	result, err := s.TestAPIEndpoint(context.Background(), storeEndpoint(s, &endpoint), "http://pets.test")
+	require.NoError(t, err)
+	assert.Contains(t, result.ValidationSkipped, "DELETE /pets/{id} is not declared")
+	assert.Nil(t, result.Violations)
+}
+
+func TestSwaggerService_TestAPIEndpoint_InvalidRequestNotSent(t *testing.T) {
+	s := newContractService(jsonResponse(200, `{}`))
+	endpoint := *petEndpoint
+	endpoint.ID = 4
+	endpoint.Parameters = model.APIParameters{
+		{Name: "id", In: "path", Required: true, Type: "integer", Value: "abc"},
+		{Name: "fields", In: "query", Type: "string", Value: "all"},
+		{Name: "X-Trace", In: "header", Type: "string", Value: "XYZ"},
+	}
+
+	result, err := s.TestAPIEndpoint(context.Background(), storeEndpoint(s, &endpoint), "http://pets.test")
+	assert.Nil(t, result)
+	var invalid *RequestValidationError
+	require.ErrorAs(t, err, &invalid)
+	fields := make([]string, len(invalid.Fields))
+	for i, f := range invalid.Fields {
+		fields[i] = f.Field
+	}
+	assert.ElementsMatch(t, []string{"path.id", "query.fields", "header.X-Trace"}, fields)
+	s.httpClient.(*MockHTTPClient).AssertNotCalled(t, "DoRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSwaggerService_TestAPIEndpoint_InvalidBody(t *testing.T) {
+	s := newContractService(jsonResponse(200, `{}`))
+	endpoint := &model.APIEndpoint{
+		ID:        4,
+		SwaggerID: 7,
+		Path:      "/pets",
+		Method:    "POST",
+		Body:      `{"name": "a very long name", "birthday": "yesterday"}`,
+	}
+
+	_, err := s.TestAPIEndpoint(context.Background(), storeEndpoint(s, endpoint), "http://pets.test")
+	var invalid *RequestValidationError
+	require.ErrorAs(t, err, &invalid)
+	fields := make([]string, len(invalid.Fields))
+	for i, f := range invalid.Fields {
+		fields[i] = f.Field
+	}
+	assert.ElementsMatch(t, []string{"body/name", "body/birthday"}, fields)
+
+	// 符合约束的请求体正常发送
+	endpoint.Body = `{"name": "Rex", "birthday": "2020-01-02"}`
+	s.httpClient.(*MockHTTPClient).On("DoRequest", mock.Anything, "POST", "http://pets.test/pets", mock.Anything, mock.Anything).
+		Return(&httpclient.Response{StatusCode: 201, Status: "201 Created", URL: "http://pets.test/pets"}, nil)
+	result, err := s.TestAPIEndpoint(context.Background(), endpoint, "http://pets.test")
+	require.NoError(t, err)
+	assert.Equal(t, 201, result.StatusCode)
+	assert.Empty(t, result.Violations)
+}
+
+func TestSwaggerService_TestAPIEndpoint_SecretRefsNotValidated(t *testing.T) {
+	ctx := context.Background()
+	keyring := testKeyring(t, "", "k1")
+	secretDAO := new(MockSecretDAO)
+	secretDAO.On("ListByNames", ctx, []string{"FIELDS", "TRACE"}).Return([]model.Secret{
+		sealedSecret(t, keyring, 1, "FIELDS", "full"),
+		sealedSecret(t, keyring, 2, "TRACE", "abc123"),
+	}, nil)
+	s := newContractService(jsonResponse(200, `{"id": 1, "name": "Rex"}`))
+	s.executor = NewAPIExecutor(s.httpClient, newSecretStore(secretDAO, keyring))
+	s.httpClient.(*MockHTTPClient).On("DoRequest", ctx, "GET", "http://pets.test/pets/1?fields=full", map[string]string{"X-Trace": "abc123"}, nil).
+		Return(jsonResponse(200, `{"id": 1, "name": "Rex"}`), nil)
+
+	// 引用本身不满足枚举与 pattern 约束，但取值为引用的字段不做校验
+	endpoint := *petEndpoint
+	endpoint.ID = 4
+	endpoint.Parameters = model.APIParameters{
+		{Name: "id", In: "path", Required: true, Type: "integer", Value: "1"},
+		{Name: "fields", In: "query", Type: "string", Value: "{{secret:FIELDS}}"},
+		{Name: "X-Trace", In: "header", Type: "string", Value: "{{secret:TRACE}}"},
+	}
+	result, err := s.TestAPIEndpoint(ctx, storeEndpoint(s, &endpoint), "http://pets.test")
+	require.NoError(t, err)
+	assert.Equal(t, 200, result.StatusCode)
+
+	// 其他字段仍按文档校验
+	endpoint.Parameters[0].Value = "abc"
+	_, err = s.TestAPIEndpoint(ctx, &endpoint, "http://pets.test")
+	var invalid *RequestValidationError
+	require.ErrorAs(t, err, &invalid)
+	require.Len(t, invalid.Fields, 1)
+	assert.Equal(t, "path.id", invalid.Fields[0].Field)
+}
+
+func TestSwaggerService_TestAPIEndpoint_SecretsOnlyForTrustedBaseURL(t *testing.T) {
+	ctx := context.Background()
+	keyring := testKeyring(t, "", "k1")
+	secretDAO := new(MockSecretDAO)
+	secretDAO.On("ListByNames", ctx, []string{"TRACE"}).Return([]model.Secret{sealedSecret(t, keyring, 1, "TRACE", "abc123")}, nil)
+	s := newContractService(jsonResponse(200, `{"id": 1, "name": "Rex"}`))
+	s.executor = NewAPIExecutor(s.httpClient, newSecretStore(secretDAO, keyring))
+	s.trustedBaseURLs = []string{"http://staging.pets.test/"}
+	s.httpClient.(*MockHTTPClient).On("DoRequest", ctx, "GET", "http://staging.pets.test/pets/1", map[string]string{"X-Trace": "abc123"}, nil).
+		Return(jsonResponse(200, `{"id": 1, "name": "Rex"}`), nil)
+
+	endpoint := *petEndpoint
+	endpoint.ID = 4
+	endpoint.Headers = model.StringMap{"X-Trace": "{{secret:TRACE}}"}
+	stored := storeEndpoint(s, &endpoint)
+
+	// 配置中信任的地址可以解密 secret
+	result, err := s.TestAPIEndpoint(ctx, stored, "http://staging.pets.test")
+	require.NoError(t, err)
+	assert.Equal(t, 200, result.StatusCode)
+
+	// 其他地址不解密 secret，请求不会被发送
+	_, err = s.TestAPIEndpoint(ctx, stored, "http://attacker.test")
+	assert.ErrorIs(t, err, ErrUntrustedBaseURL)
+	s.httpClient.(*MockHTTPClient).AssertNumberOfCalls(t, "DoRequest", 1)
+	secretDAO.AssertNumberOfCalls(t, "ListByNames", 1)
+}
+
+func TestSwaggerService_TestAPIEndpoint_SubmittedValues(t *testing.T) {
+	ctx := context.Background()
+	s := newContractService(jsonResponse(200, `{"id": 2, "name": "Rex"}`))
+	s.httpClient.(*MockHTTPClient).On("DoRequest", ctx, "GET", "http://pets.test/pets/2?fields=full", map[string]string{"X-Trace": "abc"}, nil).
+		Return(jsonResponse(200, `{"id": 2, "name": "Rex"}`), nil)
+
+	// 提交的参数取值与请求头替换保存的配置
+	submitted := *petEndpoint
+	submitted.Parameters = model.APIParameters{
+		{Name: "id", In: "path", Required: true, Type: "integer", Value: "2"},
+		{Name: "fields", In: "query", Type: "string", Value: "full"},
+	}
+	submitted.Headers = model.StringMap{"X-Trace": "abc"}
+	result, err := s.TestAPIEndpoint(ctx, &submitted, "http://pets.test")
+	require.NoError(t, err)
+	assert.Equal(t, 200, result.StatusCode)
+	s.httpClient.(*MockHTTPClient).AssertCalled(t, "DoRequest", ctx, "GET", "http://pets.test/pets/2?fields=full", map[string]string{"X-Trace": "abc"}, nil)
+}
+
+func TestSwaggerService_TestAPIEndpoint_SubmittedSecretRefs(t *testing.T) {
+	ctx := context.Background()
+	s := newContractService(jsonResponse(200, `{}`))
+	secretDAO := new(MockSecretDAO)
+	s.executor = NewAPIExecutor(s.httpClient, newSecretStore(secretDAO, testKeyring(t, "", "k1")))
+	stored := *petEndpoint
+	stored.ID = 4
+	stored.Headers = model.StringMap{"X-Trace": "{{secret:TRACE}}"}
+	storeEndpoint(s, &stored)
+
+	// 保存的配置中没有的引用，以及移动到其他字段的引用都会被拒绝
+	submitted := stored
+	submitted.Parameters = model.APIParameters{
+		{Name: "id", In: "path", Required: true, Type: "integer", Value: "1"},
+		{Name: "fields", In: "query", Type: "string", Value: "{{secret:TRACE}}"},
+	}
+	submitted.Headers = model.StringMap{"X-Trace": "{{secret:TRACE}}", "X-Key": "{{secret:API_KEY}}"}
+	submitted.Path = "/pets"
+	_, err := s.TestAPIEndpoint(ctx, &submitted, "http://pets.test")
+	var invalid *RequestValidationError
+	require.ErrorAs(t, err, &invalid)
+	assert.ElementsMatch(t, []FieldError{
+		{Field: "path", Message: "must be the path of the stored endpoint, update the endpoint before testing"},
+		{Field: "query.fields", Message: "secret references are not allowed"},
+		{Field: "header.X-Key", Message: "secret references are not allowed"},
+	}, invalid.Fields)
+	s.httpClient.(*MockHTTPClient).AssertNotCalled(t, "DoRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	secretDAO.AssertNotCalled(t, "ListByNames", mock.Anything, mock.Anything)
+}
+
+func TestWithoutSecretRefs_Body(t *testing.T) {
+	fields := []FieldError{{Field: "body/name"}, {Field: "body/tags/0"}, {Field: "body/birthday"}}
+	req := &APIRequest{Body: `{"name": "{{secret:NAME}}", "tags": ["{{secret:TAG}}"], "birthday": "yesterday"}`}
+
+	kept := withoutSecretRefs(fields, &model.APIEndpoint{}, req)
+	assert.Equal(t, []FieldError{{Field: "body/birthday"}}, kept)
+}
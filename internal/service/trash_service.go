@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/model"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TrashService 定义回收站的业务接口
+type TrashService interface {
+	// List 查询回收站中的文档与单独删除的接口
+	List(ctx context.Context) (*TrashListing, error)
+	// RestoreDocument 恢复文档及随其删除的接口与工具绑定
+	RestoreDocument(ctx context.Context, id uint) error
+	// RestoreEndpoint 恢复接口及随其删除的工具绑定
+	RestoreEndpoint(ctx context.Context, id uint) error
+	// PurgeDocument 彻底删除回收站中的文档
+	PurgeDocument(ctx context.Context, id uint) error
+	// PurgeEndpoint 彻底删除回收站中的接口
+	PurgeEndpoint(ctx context.Context, id uint) error
+	// PurgeExpired 彻底删除超过保留时长的文档与接口，返回删除的文档数与接口数
+	PurgeExpired(ctx context.Context) (documents, endpoints int64, err error)
+}
+
+// TrashListing 为回收站的内容
+type TrashListing struct {
+	Documents []TrashedDocument `json:"documents"`
+	Endpoints []TrashedEndpoint `json:"endpoints"` // 单独删除的接口，随文档删除的接口计入文档的 endpoint_count
+}
+
+// TrashedDocument 为回收站中的文档
+type TrashedDocument struct {
+	model.SwaggerDocument
+	EndpointCount int64      `json:"endpoint_count"` // 随文档删除的接口数
+	PurgeAt       *time.Time `json:"purge_at"`       // 预计彻底删除的时间，不自动清理时为空
+}
+
+// TrashedEndpoint 为回收站中的接口
+type TrashedEndpoint struct {
+	model.APIEndpoint
+	PurgeAt *time.Time `json:"purge_at"` // 预计彻底删除的时间，不自动清理时为空
+}
+
+// trashService 实现 TrashService 接口
+type trashService struct {
+	dao       dao.TrashDAO
+	retention time.Duration
+}
+
+// NewTrashService 创建一个新的 TrashService 实例，retention 不大于 0 时不自动清理
+func NewTrashService(trashDAO dao.TrashDAO, retention time.Duration) TrashService {
+	if trashDAO == nil {
+		trashDAO = dao.NewTrashDAO(nil)
+	}
+	return &trashService{dao: trashDAO, retention: retention}
+}
+
+func (s *trashService) List(ctx context.Context) (*TrashListing, error) {
+	docs, err := s.dao.ListDocuments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	counts, err := s.dao.CountEndpoints(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := s.dao.ListEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	listing := &TrashListing{
+		Documents: make([]TrashedDocument, len(docs)),
+		Endpoints: make([]TrashedEndpoint, len(endpoints)),
+	}
+	for i, doc := range docs {
+		listing.Documents[i] = TrashedDocument{SwaggerDocument: doc, EndpointCount: counts[doc.ID], PurgeAt: s.purgeAt(doc.DeletedAt.Time)}
+	}
+	for i, endpoint := range endpoints {
+		listing.Endpoints[i] = TrashedEndpoint{APIEndpoint: endpoint, PurgeAt: s.purgeAt(endpoint.DeletedAt.Time)}
+	}
+	return listing, nil
+}
+
+// purgeAt 返回在 deletedAt 移入回收站的记录预计被彻底删除的时间
+func (s *trashService) purgeAt(deletedAt time.Time) *time.Time {
+	if s.retention <= 0 {
+		return nil
+	}
+	at := deletedAt.Add(s.retention)
+	return &at
+}
+
+func (s *trashService) RestoreDocument(ctx context.Context, id uint) error {
+	return s.dao.RestoreDocument(ctx, id)
+}
+
+func (s *trashService) RestoreEndpoint(ctx context.Context, id uint) error {
+	return s.dao.RestoreEndpoint(ctx, id)
+}
+
+func (s *trashService) PurgeDocument(ctx context.Context, id uint) error {
+	return s.dao.PurgeDocument(ctx, id)
+}
+
+func (s *trashService) PurgeEndpoint(ctx context.Context, id uint) error {
+	return s.dao.PurgeEndpoint(ctx, id)
+}
+
+func (s *trashService) PurgeExpired(ctx context.Context) (documents, endpoints int64, err error) {
+	if s.retention <= 0 {
+		return 0, 0, nil
+	}
+	return s.dao.PurgeBefore(ctx, time.Now().Add(-s.retention))
+}
+
+// PurgeScheduler 定时彻底删除回收站中超过保留时长的文档与接口
+type PurgeScheduler struct {
+	service  TrashService
+	interval time.Duration
+}
+
+// NewPurgeScheduler 创建一个每隔 interval 清理一次回收站的调度器
+func NewPurgeScheduler(service TrashService, interval time.Duration) *PurgeScheduler {
+	return &PurgeScheduler{service: service, interval: interval}
+}
+
+// Start 启动时先清理一次，之后在后台定时清理直到 ctx 被取消，interval 不大于 0 时不启动
+func (s *PurgeScheduler) Start(ctx context.Context) {
+	if s.interval <= 0 {
+		return
+	}
+	go func() {
+		s.purge(ctx)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.purge(ctx)
+			}
+		}
+	}()
+}
+
+func (s *PurgeScheduler) purge(ctx context.Context) {
+	documents, endpoints, err := s.service.PurgeExpired(ctx)
+	if err != nil {
+		log.Errorf("purge trash failed: %v", err)
+		return
+	}
+	if documents > 0 || endpoints > 0 {
+		log.Infof("purged %d documents and %d endpoints from the trash", documents, endpoints)
+	}
+}
@@ -1,30 +1,53 @@
 package testutil
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+
 	log "github.com/sirupsen/logrus"
+	"mcp-manager/internal/migrate"
 	"mcp-manager/internal/model"
 	"mcp-manager/pkg/config"
 	"mcp-manager/pkg/logger"
 )
 
 // init 初始化测试环境，包括 config、logger、db
+// 默认使用 cfg/cfg_test.yaml 中的内存 SQLite，可通过 MCP_MANAGER_TEST_CFG 指定其他配置（如真实的 MySQL）
 func init() {
 	// 加载配置
-	configPath := "/Users/wanghao/Desktop/github/go/mcp-manager/cfg/cfg_local.yaml"
+	configPath := os.Getenv("MCP_MANAGER_TEST_CFG")
+	if configPath == "" {
+		_, file, _, _ := runtime.Caller(0)
+		configPath = filepath.Join(filepath.Dir(file), "..", "..", "cfg", "cfg_test.yaml")
+	}
 	err := config.Init(configPath, nil)
 	if err != nil {
 		log.Fatalf("init config failed: %v", err)
 	}
 
-	// 初始化日志
-	err = logger.InitDebugLogger(config.LogLevel(), config.LogPath())
+	// 初始化日志，测试日志写到临时目录
+	logPath := config.LogPath()
+	if logPath == "" {
+		logPath = filepath.Join(os.TempDir(), "mcp-manager-test_%Y%m%d.log")
+	}
+	err = logger.InitDebugLogger(config.LogLevel(), logPath)
 	if err != nil {
 		log.Fatalf("init logger failed: %v", err)
 	}
 
-	// 初始化数据库（如需真实数据库）
+	// 初始化数据库
 	err = model.InitDBs()
 	if err != nil {
 		log.Fatalf("init db failed: %v", err)
 	}
+	conn, err := model.GetMcpManagerDB()
+	if err != nil {
+		log.Fatalf("get db failed: %v", err)
+	}
+	err = migrate.Run(context.Background(), conn)
+	if err != nil {
+		log.Fatalf("migrate db failed: %v", err)
+	}
 }
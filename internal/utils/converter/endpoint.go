@@ -0,0 +1,328 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"mcp-manager/internal/model"
+)
+
+// ParameterInBody is the APIParameter location that carries the request body.
+const ParameterInBody = "body"
+
+// originalBodyNameExtension holds the name of the Swagger 2.0 body parameter a request body was converted from.
+const originalBodyNameExtension = "x-originalParamName"
+
+// maxSampleDepth bounds the expansion of nested schemas when generating a sample body.
+const maxSampleDepth = 8
+
+// newEndpoint extracts an APIEndpoint from an OpenAPI 3 operation.
+// References must already be resolved, which the kin-openapi loader and openapi2conv do.
+func newEndpoint(path, method string, pathItem *openapi3.PathItem, op *openapi3.Operation) model.APIEndpoint {
+	endpoint := model.APIEndpoint{
+		Path:        path,
+		Method:      strings.ToUpper(method),
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: op.OperationID,
+		Tags:        strings.Join(op.Tags, ","),
+		Parameters:  model.APIParameters{},
+		Headers:     model.StringMap{},
+		InputSchema: MarshalInputSchema(pathItem, op),
+	}
+
+	for _, param := range mergeParameters(pathItem, op) {
+		endpoint.Parameters = append(endpoint.Parameters, model.APIParameter{
+			Name:     param.Name,
+			In:       param.In,
+			Required: param.Required || param.In == openapi3.ParameterInPath,
+			Type:     parameterType(param),
+			Value:    parameterValue(param),
+		})
+	}
+
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		body := op.RequestBody.Value
+		if mediaType, media := preferredContent(body.Content); media != nil {
+			endpoint.Headers["Content-Type"] = mediaType
+			name := BodyProperty
+			if original, ok := body.Extensions[originalBodyNameExtension].(string); ok && original != "" {
+				name = original
+			}
+			endpoint.Body = sampleBody(media)
+			endpoint.Parameters = append(endpoint.Parameters, model.APIParameter{
+				Name:     name,
+				In:       ParameterInBody,
+				Required: body.Required,
+				Type:     schemaType(media.Schema),
+			})
+		}
+	}
+
+	if accept := acceptedMediaType(op.Responses); accept != "" {
+		endpoint.Headers["Accept"] = accept
+	}
+	endpoint.Responses = marshalResponses(op.Responses)
+	return endpoint
+}
+
+// sortEndpoints orders endpoints by path and method, since documents keep both in maps.
+func sortEndpoints(endpoints []model.APIEndpoint) {
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+}
+
+// parameterType returns the JSON type of a parameter, string when it is not declared.
+func parameterType(param *openapi3.Parameter) string {
+	schema := param.Schema
+	if schema == nil {
+		if _, media := preferredContent(param.Content); media != nil {
+			schema = media.Schema
+		}
+	}
+	if t := schemaType(schema); t != "" {
+		return t
+	}
+	return openapi3.TypeString
+}
+
+// parameterValue returns the default or example value of a parameter as the string sent on the wire.
+func parameterValue(param *openapi3.Parameter) string {
+	if param.Example != nil {
+		return valueString(param.Example)
+	}
+	if param.Schema != nil && param.Schema.Value != nil {
+		if param.Schema.Value.Default != nil {
+			return valueString(param.Schema.Value.Default)
+		}
+		if param.Schema.Value.Example != nil {
+			return valueString(param.Schema.Value.Example)
+		}
+	}
+	return ""
+}
+
+// schemaType returns the first non-null type declared by a schema.
+func schemaType(ref *openapi3.SchemaRef) string {
+	if ref == nil || ref.Value == nil || ref.Value.Type == nil {
+		return ""
+	}
+	for _, t := range ref.Value.Type.Slice() {
+		if t != openapi3.TypeNull {
+			return t
+		}
+	}
+	return ""
+}
+
+// valueString renders a decoded JSON value; strings are used verbatim, everything else is JSON encoded.
+func valueString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// preferredContent returns the media type picked by preferredMediaType together with its name.
+func preferredContent(content openapi3.Content) (string, *openapi3.MediaType) {
+	media := preferredMediaType(content)
+	if media == nil {
+		return "", nil
+	}
+	for name, m := range content {
+		if m == media {
+			return name, media
+		}
+	}
+	return "", nil
+}
+
+// acceptedMediaType returns the preferred media type of the first successful response.
+func acceptedMediaType(responses *openapi3.Responses) string {
+	if responses == nil {
+		return ""
+	}
+	codes := make([]string, 0, responses.Len())
+	for code := range responses.Map() {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		if ref := responses.Value(code); ref != nil && ref.Value != nil {
+			if name, _ := preferredContent(ref.Value.Content); name != "" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// responseSummary is the stored description of a single response.
+type responseSummary struct {
+	Description string                            `json:"description,omitempty"`
+	Headers     map[string]map[string]interface{} `json:"headers,omitempty"`
+	Content     map[string]map[string]interface{} `json:"content,omitempty"`
+}
+
+// marshalResponses encodes the responses of an operation keyed by status code,
+// with the JSON Schema of every header and media type.
+func marshalResponses(responses *openapi3.Responses) model.JSONText {
+	if responses == nil || responses.Len() == 0 {
+		return "{}"
+	}
+	out := make(map[string]responseSummary, responses.Len())
+	for code, ref := range responses.Map() {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		summary := responseSummary{}
+		if ref.Value.Description != nil {
+			summary.Description = *ref.Value.Description
+		}
+		for name, header := range ref.Value.Headers {
+			if header == nil || header.Value == nil {
+				continue
+			}
+			if summary.Headers == nil {
+				summary.Headers = make(map[string]map[string]interface{})
+			}
+			prop := schemaToJSONSchema(header.Value.Schema, directionResponse, 0, map[*openapi3.Schema]bool{})
+			setString(prop, "description", header.Value.Description)
+			summary.Headers[name] = prop
+		}
+		for mediaType, media := range ref.Value.Content {
+			if media == nil {
+				continue
+			}
+			if summary.Content == nil {
+				summary.Content = make(map[string]map[string]interface{})
+			}
+			summary.Content[mediaType] = schemaToJSONSchema(media.Schema, directionResponse, 0, map[*openapi3.Schema]bool{})
+		}
+		out[code] = summary
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "{}"
+	}
+	return model.JSONText(b)
+}
+
+// sampleBody returns the declared example of a request body, or a skeleton generated from its schema.
+func sampleBody(media *openapi3.MediaType) string {
+	var sample interface{}
+	switch {
+	case media.Example != nil:
+		sample = media.Example
+	case len(media.Examples) > 0:
+		names := make([]string, 0, len(media.Examples))
+		for name := range media.Examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if ex := media.Examples[names[0]]; ex != nil && ex.Value != nil {
+			sample = ex.Value.Value
+		}
+	}
+	if sample == nil {
+		if media.Schema == nil {
+			return ""
+		}
+		sample = sampleValue(media.Schema, 0, map[*openapi3.Schema]bool{})
+	}
+	if s, ok := sample.(string); ok {
+		return s
+	}
+	b, err := json.MarshalIndent(sample, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// sampleValue builds a placeholder value for a schema, preferring its example, default and first enum value.
+// readOnly properties are skipped, since they are never sent in a request.
+func sampleValue(ref *openapi3.SchemaRef, depth int, visiting map[*openapi3.Schema]bool) interface{} {
+	if ref == nil || ref.Value == nil {
+		return nil
+	}
+	s := ref.Value
+	switch {
+	case s.Example != nil:
+		return s.Example
+	case s.Default != nil:
+		return s.Default
+	case len(s.Enum) > 0:
+		return s.Enum[0]
+	}
+	if visiting[s] || depth > maxSampleDepth {
+		return nil
+	}
+	visiting[s] = true
+	defer delete(visiting, s)
+
+	if len(s.AllOf) > 0 {
+		merged := make(map[string]interface{})
+		for _, item := range s.AllOf {
+			if m, ok := sampleValue(item, depth+1, visiting).(map[string]interface{}); ok {
+				for k, v := range m {
+					merged[k] = v
+				}
+			}
+		}
+		for k, v := range sampleProperties(s, depth, visiting) {
+			merged[k] = v
+		}
+		return merged
+	}
+	for _, alternatives := range []openapi3.SchemaRefs{s.OneOf, s.AnyOf} {
+		if len(alternatives) > 0 {
+			return sampleValue(alternatives[0], depth+1, visiting)
+		}
+	}
+
+	switch schemaType(ref) {
+	case openapi3.TypeObject:
+		return sampleProperties(s, depth, visiting)
+	case openapi3.TypeArray:
+		if item := sampleValue(s.Items, depth+1, visiting); item != nil {
+			return []interface{}{item}
+		}
+		return []interface{}{}
+	case openapi3.TypeInteger, openapi3.TypeNumber:
+		return 0
+	case openapi3.TypeBoolean:
+		return false
+	case openapi3.TypeString:
+		return ""
+	}
+	if len(s.Properties) > 0 {
+		return sampleProperties(s, depth, visiting)
+	}
+	return nil
+}
+
+func sampleProperties(s *openapi3.Schema, depth int, visiting map[*openapi3.Schema]bool) map[string]interface{} {
+	out := make(map[string]interface{}, len(s.Properties))
+	for name, prop := range s.Properties {
+		if prop != nil && prop.Value != nil && prop.Value.ReadOnly {
+			continue
+		}
+		out[name] = sampleValue(prop, depth+1, visiting)
+	}
+	return out
+}
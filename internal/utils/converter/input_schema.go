@@ -0,0 +1,340 @@
+package converter
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// BodyProperty is the input schema property that carries the request body.
+const BodyProperty = "body"
+
+// ParameterKey identifies a parameter by its location and name.
+type ParameterKey struct {
+	In   string
+	Name string
+}
+
+// ArgumentNames returns the tool argument name of every parameter.
+// Request body parameters map to BodyProperty. A name shared by parameters in different locations,
+// or equal to BodyProperty, is prefixed with the location ("path_id", "query_id") so that no argument
+// overwrites another.
+func ArgumentNames(keys []ParameterKey) []string {
+	counts := make(map[string]int, len(keys))
+	for _, k := range keys {
+		if k.In != ParameterInBody {
+			counts[k.Name]++
+		}
+	}
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		switch {
+		case k.In == ParameterInBody:
+			names[i] = BodyProperty
+		case counts[k.Name] > 1 || k.Name == BodyProperty:
+			names[i] = k.In + "_" + k.Name
+		default:
+			names[i] = k.Name
+		}
+	}
+	return names
+}
+
+// maxSchemaDepth bounds the expansion of deeply nested or recursive schemas.
+const maxSchemaDepth = 16
+
+// schemaDirection selects which of the readOnly and writeOnly properties a converted schema keeps.
+type schemaDirection int
+
+const (
+	// directionRequest drops readOnly properties, which only appear in responses.
+	directionRequest schemaDirection = iota
+	// directionResponse drops writeOnly properties, which only appear in requests.
+	directionResponse
+)
+
+// BuildInputSchema merges the path-level and operation-level parameters and the request body of an
+// operation into a single JSON Schema object, suitable as an MCP tool inputSchema.
+// Path, query and header parameters become top-level properties named by ArgumentNames;
+// the request body becomes the "body" property.
+// References must already be resolved, which the kin-openapi loader does when loading a document.
+func BuildInputSchema(pathItem *openapi3.PathItem, op *openapi3.Operation) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	params := mergeParameters(pathItem, op)
+	keys := make([]ParameterKey, len(params))
+	for i, param := range params {
+		keys[i] = ParameterKey{In: param.In, Name: param.Name}
+	}
+	names := ArgumentNames(keys)
+	for i, param := range params {
+		if param.In == openapi3.ParameterInCookie {
+			continue
+		}
+		properties[names[i]] = parameterSchema(param)
+		if param.Required || param.In == openapi3.ParameterInPath {
+			required = append(required, names[i])
+		}
+	}
+
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		body := op.RequestBody.Value
+		if media := preferredMediaType(body.Content); media != nil && media.Schema != nil {
+			prop := schemaToJSONSchema(media.Schema, directionRequest, 0, map[*openapi3.Schema]bool{})
+			if body.Description != "" {
+				if _, ok := prop["description"]; !ok {
+					prop["description"] = body.Description
+				}
+			}
+			properties[BodyProperty] = prop
+			if body.Required {
+				required = append(required, BodyProperty)
+			}
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+// MarshalInputSchema encodes the input schema of an operation.
+func MarshalInputSchema(pathItem *openapi3.PathItem, op *openapi3.Operation) []byte {
+	b, err := json.Marshal(BuildInputSchema(pathItem, op))
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// mergeParameters returns the path-level parameters overridden by the operation-level ones with the same name and location.
+func mergeParameters(pathItem *openapi3.PathItem, op *openapi3.Operation) []*openapi3.Parameter {
+	var merged []*openapi3.Parameter
+	index := make(map[string]int)
+	add := func(refs openapi3.Parameters) {
+		for _, ref := range refs {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			key := ref.Value.In + ":" + ref.Value.Name
+			if i, ok := index[key]; ok {
+				merged[i] = ref.Value
+				continue
+			}
+			index[key] = len(merged)
+			merged = append(merged, ref.Value)
+		}
+	}
+	if pathItem != nil {
+		add(pathItem.Parameters)
+	}
+	add(op.Parameters)
+	return merged
+}
+
+// parameterSchema converts a parameter into a property schema, keeping its description.
+func parameterSchema(param *openapi3.Parameter) map[string]interface{} {
+	var prop map[string]interface{}
+	switch {
+	case param.Schema != nil:
+		prop = schemaToJSONSchema(param.Schema, directionRequest, 0, map[*openapi3.Schema]bool{})
+	case len(param.Content) > 0:
+		if media := preferredMediaType(param.Content); media != nil && media.Schema != nil {
+			prop = schemaToJSONSchema(media.Schema, directionRequest, 0, map[*openapi3.Schema]bool{})
+		}
+	}
+	if prop == nil {
+		prop = map[string]interface{}{"type": "string"}
+	}
+	if param.Description != "" {
+		prop["description"] = param.Description
+	}
+	if param.Deprecated {
+		prop["deprecated"] = true
+	}
+	if param.Example != nil {
+		if _, ok := prop["examples"]; !ok {
+			prop["examples"] = []interface{}{param.Example}
+		}
+	}
+	return prop
+}
+
+// preferredMediaType picks application/json, then any JSON media type, then the first declared one.
+func preferredMediaType(content openapi3.Content) *openapi3.MediaType {
+	if len(content) == 0 {
+		return nil
+	}
+	if media, ok := content["application/json"]; ok {
+		return media
+	}
+	keys := make([]string, 0, len(content))
+	for k := range content {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if strings.Contains(k, "json") {
+			return content[k]
+		}
+	}
+	return content[keys[0]]
+}
+
+// schemaToJSONSchema converts an OpenAPI 3.0 schema into an inlined JSON Schema.
+// OpenAPI-only keywords are translated (nullable becomes a "null" type, example becomes examples),
+// properties not sent in dir are dropped and recursive references are cut off with a plain object schema.
+func schemaToJSONSchema(ref *openapi3.SchemaRef, dir schemaDirection, depth int, visiting map[*openapi3.Schema]bool) map[string]interface{} {
+	out := make(map[string]interface{})
+	if ref == nil || ref.Value == nil {
+		return out
+	}
+	s := ref.Value
+	if visiting[s] || depth > maxSchemaDepth {
+		out["type"] = "object"
+		if s.Description != "" {
+			out["description"] = s.Description
+		}
+		return out
+	}
+	visiting[s] = true
+	defer delete(visiting, s)
+
+	if s.Type != nil && len(*s.Type) > 0 {
+		types := append([]string(nil), s.Type.Slice()...)
+		if s.Nullable && !s.Type.Includes(openapi3.TypeNull) {
+			types = append(types, openapi3.TypeNull)
+		}
+		if len(types) == 1 {
+			out["type"] = types[0]
+		} else {
+			out["type"] = types
+		}
+	}
+	setString(out, "title", s.Title)
+	setString(out, "description", s.Description)
+	setString(out, "format", s.Format)
+	setString(out, "pattern", s.Pattern)
+	if len(s.Enum) > 0 {
+		out["enum"] = s.Enum
+	}
+	if s.Default != nil {
+		out["default"] = s.Default
+	}
+	if s.Example != nil {
+		out["examples"] = []interface{}{s.Example}
+	}
+	if s.Deprecated {
+		out["deprecated"] = true
+	}
+
+	// number
+	if s.Min != nil {
+		if s.ExclusiveMin {
+			out["exclusiveMinimum"] = *s.Min
+		} else {
+			out["minimum"] = *s.Min
+		}
+	}
+	if s.Max != nil {
+		if s.ExclusiveMax {
+			out["exclusiveMaximum"] = *s.Max
+		} else {
+			out["maximum"] = *s.Max
+		}
+	}
+	if s.MultipleOf != nil {
+		out["multipleOf"] = *s.MultipleOf
+	}
+
+	// string
+	if s.MinLength > 0 {
+		out["minLength"] = s.MinLength
+	}
+	if s.MaxLength != nil {
+		out["maxLength"] = *s.MaxLength
+	}
+
+	// array
+	if s.Items != nil {
+		out["items"] = schemaToJSONSchema(s.Items, dir, depth+1, visiting)
+	}
+	if s.MinItems > 0 {
+		out["minItems"] = s.MinItems
+	}
+	if s.MaxItems != nil {
+		out["maxItems"] = *s.MaxItems
+	}
+	if s.UniqueItems {
+		out["uniqueItems"] = true
+	}
+
+	// object
+	if len(s.Properties) > 0 {
+		props := make(map[string]interface{}, len(s.Properties))
+		skipped := make(map[string]bool)
+		for name, propRef := range s.Properties {
+			if propRef != nil && propRef.Value != nil {
+				if (dir == directionRequest && propRef.Value.ReadOnly) || (dir == directionResponse && propRef.Value.WriteOnly) {
+					skipped[name] = true
+					continue
+				}
+			}
+			props[name] = schemaToJSONSchema(propRef, dir, depth+1, visiting)
+		}
+		out["properties"] = props
+		var required []string
+		for _, name := range s.Required {
+			if !skipped[name] {
+				required = append(required, name)
+			}
+		}
+		if len(required) > 0 {
+			out["required"] = required
+		}
+	} else if len(s.Required) > 0 {
+		out["required"] = s.Required
+	}
+	if s.AdditionalProperties.Has != nil {
+		out["additionalProperties"] = *s.AdditionalProperties.Has
+	} else if s.AdditionalProperties.Schema != nil {
+		out["additionalProperties"] = schemaToJSONSchema(s.AdditionalProperties.Schema, dir, depth+1, visiting)
+	}
+	if s.MinProps > 0 {
+		out["minProperties"] = s.MinProps
+	}
+	if s.MaxProps != nil {
+		out["maxProperties"] = *s.MaxProps
+	}
+
+	// composition
+	for keyword, refs := range map[string]openapi3.SchemaRefs{"allOf": s.AllOf, "oneOf": s.OneOf, "anyOf": s.AnyOf} {
+		if len(refs) == 0 {
+			continue
+		}
+		items := make([]interface{}, 0, len(refs))
+		for _, item := range refs {
+			items = append(items, schemaToJSONSchema(item, dir, depth+1, visiting))
+		}
+		out[keyword] = items
+	}
+	if s.Not != nil {
+		out["not"] = schemaToJSONSchema(s.Not, dir, depth+1, visiting)
+	}
+	return out
+}
+
+func setString(m map[string]interface{}, key, value string) {
+	if value != "" {
+		m[key] = value
+	}
+}
@@ -0,0 +1,182 @@
+package converter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstoreOpenAPI3 = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: integer
+          format: int64
+    put:
+      operationId: updatePet
+      parameters:
+        - name: status
+          in: query
+          description: filter by status
+          schema:
+            type: string
+            enum: [available, sold]
+        - name: session
+          in: cookie
+          schema:
+            type: string
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        "200":
+          description: OK
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+          readOnly: true
+        name:
+          type: string
+          maxLength: 32
+        tag:
+          type: string
+          nullable: true
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Pet'
+`
+
+const petstoreSwagger2 = `{
+  "swagger": "2.0",
+  "info": {"title": "Pets", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "post": {
+        "operationId": "addPet",
+        "parameters": [
+          {"name": "X-Trace", "in": "header", "type": "string"},
+          {"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}
+        ],
+        "responses": {"200": {"description": "OK"}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {"type": "object", "properties": {"name": {"type": "string", "enum": ["a", "b"]}}}
+  }
+}`
+
+func decodeSchema(t *testing.T, raw []byte) map[string]interface{} {
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &schema))
+	return schema
+}
+
+func TestOpenAPI3Converter_InputSchema(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(petstoreOpenAPI3))
+	require.NoError(t, err)
+
+	endpoints := NewOpenAPI3Converter().ConvertToAPIEndpoint(doc)
+	require.Len(t, endpoints, 1)
+	schema := decodeSchema(t, endpoints[0].InputSchema)
+
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, []interface{}{"body", "petId"}, schema["required"])
+	props := schema["properties"].(map[string]interface{})
+
+	// path 级参数合并进来，cookie 参数被忽略
+	assert.Equal(t, map[string]interface{}{"type": "integer", "format": "int64"}, props["petId"])
+	assert.NotContains(t, props, "session")
+	assert.Equal(t, map[string]interface{}{
+		"type":        "string",
+		"enum":        []interface{}{"available", "sold"},
+		"description": "filter by status",
+	}, props["status"])
+
+	body := props["body"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"name"}, body["required"])
+	bodyProps := body["properties"].(map[string]interface{})
+	assert.NotContains(t, bodyProps, "id")
+	assert.Equal(t, float64(32), bodyProps["name"].(map[string]interface{})["maxLength"])
+	assert.Equal(t, []interface{}{"string", "null"}, bodyProps["tag"].(map[string]interface{})["type"])
+	// 递归引用被截断
+	items := bodyProps["children"].(map[string]interface{})["items"].(map[string]interface{})
+	assert.Equal(t, "object", items["type"])
+	assert.NotContains(t, items, "properties")
+}
+
+func TestSwagger2Converter_InputSchema(t *testing.T) {
+	var doc openapi2.T
+	require.NoError(t, json.Unmarshal([]byte(petstoreSwagger2), &doc))
+
+	endpoints := NewSwagger2Converter().ConvertToAPIEndpoint(&doc)
+	require.Len(t, endpoints, 1)
+	schema := decodeSchema(t, endpoints[0].InputSchema)
+
+	assert.Equal(t, []interface{}{"body"}, schema["required"])
+	props := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string"}, props["X-Trace"])
+	body := props["body"].(map[string]interface{})
+	name := body["properties"].(map[string]interface{})["name"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"a", "b"}, name["enum"])
+}
+
+func TestBuildInputSchema_CollidingNames(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.3
+info: {title: Items, version: "1.0"}
+paths:
+  /items/{id}:
+    put:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+        - {name: id, in: query, schema: {type: string}}
+        - {name: body, in: query, schema: {type: boolean}}
+      requestBody:
+        content:
+          application/json:
+            schema: {type: object}
+      responses:
+        "200": {description: OK}
+`))
+	require.NoError(t, err)
+
+	endpoints := NewOpenAPI3Converter().ConvertToAPIEndpoint(doc)
+	require.Len(t, endpoints, 1)
+	schema := decodeSchema(t, endpoints[0].InputSchema)
+
+	props := schema["properties"].(map[string]interface{})
+	assert.Len(t, props, 4)
+	assert.Equal(t, "integer", props["path_id"].(map[string]interface{})["type"])
+	assert.Equal(t, "string", props["query_id"].(map[string]interface{})["type"])
+	assert.Equal(t, "boolean", props["query_body"].(map[string]interface{})["type"])
+	assert.Equal(t, "object", props["body"].(map[string]interface{})["type"])
+	assert.Equal(t, []interface{}{"path_id"}, schema["required"])
+}
+
+func TestArgumentNames(t *testing.T) {
+	assert.Equal(t, []string{"id", "verbose", "body"}, ArgumentNames([]ParameterKey{
+		{In: "path", Name: "id"}, {In: "query", Name: "verbose"}, {In: "body", Name: "pet"},
+	}))
+}
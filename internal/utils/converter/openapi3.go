@@ -5,7 +5,7 @@ import (
 	"mcp-manager/internal/model"
 )
 
-// openapi3Converter implements the APIENdpointConverter interface for OpenAPI 3.0 documents.
+// openapi3Converter implements the APIEndpointConverter interface for OpenAPI 3.0 documents.
 type openapi3Converter struct{}
 
 // NewOpenAPI3Converter creates a new instance of openapi3Converter.
@@ -13,19 +13,17 @@ func NewOpenAPI3Converter() APIEndpointConverter[*openapi3.T] {
 	return &openapi3Converter{}
 }
 
-// ConvertToAPIEndpoint converts the OpenAPI 3.0 document to a slice of APIEndpoint models.
+// ConvertToAPIEndpoint converts the OpenAPI 3.0 document to a slice of APIEndpoint models, ordered by path and method.
 func (p *openapi3Converter) ConvertToAPIEndpoint(openapiDoc *openapi3.T) []model.APIEndpoint {
 	var endpoints []model.APIEndpoint
+	if openapiDoc.Paths == nil {
+		return endpoints
+	}
 	for path, pathItem := range openapiDoc.Paths.Map() {
 		for method, operation := range pathItem.Operations() {
-			endpoints = append(endpoints, model.APIEndpoint{
-				Path:        path,
-				Method:      method,
-				Summary:     operation.Summary,
-				Description: operation.Description,
-				OperationID: operation.OperationID,
-			})
+			endpoints = append(endpoints, newEndpoint(path, method, pathItem, operation))
 		}
 	}
+	sortEndpoints(endpoints)
 	return endpoints
 }
@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"mcp-manager/internal/model"
+)
+
+// SecuritySchemes lists the security schemes declared by a document, sorted by name.
+// Swagger 2.0 securityDefinitions are available once the document is converted by openapi2conv.
+func SecuritySchemes(doc *openapi3.T) []model.SecurityScheme {
+	if doc == nil || doc.Components == nil {
+		return []model.SecurityScheme{}
+	}
+	schemes := make([]model.SecurityScheme, 0, len(doc.Components.SecuritySchemes))
+	for name, ref := range doc.Components.SecuritySchemes {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		schemes = append(schemes, securityScheme(name, ref.Value))
+	}
+	sort.Slice(schemes, func(i, j int) bool { return schemes[i].Name < schemes[j].Name })
+	return schemes
+}
+
+// securityScheme maps a declared scheme to the AuthProfile settings it corresponds to.
+func securityScheme(name string, s *openapi3.SecurityScheme) model.SecurityScheme {
+	scheme := model.SecurityScheme{Name: name, SchemeType: s.Type, Description: s.Description}
+	switch s.Type {
+	case "apiKey":
+		scheme.Type = model.AuthTypeAPIKey
+		scheme.In = s.In
+		scheme.ParamName = s.Name
+	case "http":
+		switch strings.ToLower(s.Scheme) {
+		case "basic":
+			scheme.Type = model.AuthTypeBasic
+		case "bearer":
+			scheme.Type = model.AuthTypeBearer
+		default:
+			scheme.Unsupported = "unsupported http authentication scheme: " + s.Scheme
+		}
+	case "oauth2":
+		if s.Flows == nil || s.Flows.ClientCredentials == nil {
+			scheme.Unsupported = "only the oauth2 client credentials flow is supported"
+			break
+		}
+		flow := s.Flows.ClientCredentials
+		scheme.Type = model.AuthTypeOAuth2ClientCredentials
+		scheme.TokenURL = flow.TokenURL
+		scheme.Scopes = make([]string, 0, len(flow.Scopes))
+		for scope := range flow.Scopes {
+			scheme.Scopes = append(scheme.Scopes, scope)
+		}
+		sort.Strings(scheme.Scopes)
+	default:
+		scheme.Unsupported = "unsupported security scheme type: " + s.Type
+	}
+	return scheme
+}
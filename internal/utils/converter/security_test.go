@@ -0,0 +1,105 @@
+package converter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mcp-manager/internal/model"
+)
+
+const securedOpenAPI3 = `
+openapi: 3.0.3
+info:
+  title: Secured
+  version: "1.0"
+paths: {}
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: query
+      name: api_key
+      description: key issued by the portal
+    basicAuth:
+      type: http
+      scheme: Basic
+    bearerAuth:
+      type: http
+      scheme: bearer
+      bearerFormat: JWT
+    digestAuth:
+      type: http
+      scheme: digest
+    machine:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://auth.example.com/token
+          scopes:
+            write: modify data
+            read: read data
+    user:
+      type: oauth2
+      flows:
+        authorizationCode:
+          authorizationUrl: https://auth.example.com/authorize
+          tokenUrl: https://auth.example.com/token
+          scopes: {}
+`
+
+const securedSwagger2 = `{
+  "swagger": "2.0",
+  "info": {"title": "Secured", "version": "1.0"},
+  "paths": {},
+  "securityDefinitions": {
+    "key": {"type": "apiKey", "in": "header", "name": "X-API-Key"},
+    "basic": {"type": "basic"},
+    "machine": {"type": "oauth2", "flow": "application", "tokenUrl": "https://auth.example.com/token", "scopes": {"read": "read data"}}
+  }
+}`
+
+func TestSecuritySchemes_OpenAPI3(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(securedOpenAPI3))
+	require.NoError(t, err)
+
+	schemes := SecuritySchemes(doc)
+	require.Len(t, schemes, 6)
+	assert.Equal(t, model.SecurityScheme{
+		Name: "apiKey", SchemeType: "apiKey", Description: "key issued by the portal",
+		Type: model.AuthTypeAPIKey, In: model.AuthInQuery, ParamName: "api_key",
+	}, schemes[0])
+	assert.Equal(t, model.AuthTypeBasic, schemes[1].Type, "the http scheme name is case-insensitive")
+	assert.Equal(t, model.AuthTypeBearer, schemes[2].Type)
+	assert.Empty(t, schemes[3].Type)
+	assert.Contains(t, schemes[3].Unsupported, "digest")
+	assert.Equal(t, model.AuthTypeOAuth2ClientCredentials, schemes[4].Type)
+	assert.Equal(t, "https://auth.example.com/token", schemes[4].TokenURL)
+	assert.Equal(t, []string{"read", "write"}, schemes[4].Scopes)
+	assert.Empty(t, schemes[5].Type)
+	assert.NotEmpty(t, schemes[5].Unsupported)
+}
+
+func TestSecuritySchemes_Swagger2(t *testing.T) {
+	var doc openapi2.T
+	require.NoError(t, json.Unmarshal([]byte(securedSwagger2), &doc))
+	v3Doc, err := openapi2conv.ToV3(&doc)
+	require.NoError(t, err)
+
+	schemes := SecuritySchemes(v3Doc)
+	require.Len(t, schemes, 3)
+	assert.Equal(t, model.AuthTypeBasic, schemes[0].Type)
+	assert.Equal(t, model.AuthTypeAPIKey, schemes[1].Type)
+	assert.Equal(t, model.AuthInHeader, schemes[1].In)
+	assert.Equal(t, "X-API-Key", schemes[1].ParamName)
+	assert.Equal(t, model.AuthTypeOAuth2ClientCredentials, schemes[2].Type)
+	assert.Equal(t, []string{"read"}, schemes[2].Scopes)
+}
+
+func TestSecuritySchemes_None(t *testing.T) {
+	assert.Empty(t, SecuritySchemes(&openapi3.T{}))
+}
@@ -1,8 +1,13 @@
 package converter
 
 import (
+	"strings"
+
 	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
 	"mcp-manager/internal/model"
+
+	log "github.com/sirupsen/logrus"
 )
 
 type swagger2Converter struct{}
@@ -12,19 +17,61 @@ func NewSwagger2Converter() APIEndpointConverter[*openapi2.T] {
 	return &swagger2Converter{}
 }
 
-// ConvertToAPIEndpoint converts the OpenAPI 2.0 document to a slice of APIEndpoint models.
+// ConvertToAPIEndpoint converts the OpenAPI 2.0 document to a slice of APIEndpoint models, ordered by path and method.
+// Endpoints are extracted from the equivalent OpenAPI 3 document, in which body and formData parameters
+// have become request bodies, consumes/produces have become media types and #/definitions references are resolved.
+// When the document cannot be converted only the fields available on the 2.0 operation are filled.
 func (p *swagger2Converter) ConvertToAPIEndpoint(swaggerDoc *openapi2.T) []model.APIEndpoint {
+	v3Doc, err := openapi2conv.ToV3(swaggerDoc)
+	if err != nil {
+		log.Warnf("convert swagger2 document to openapi3 failed, only basic endpoint fields are extracted: %v", err)
+	}
 	var endpoints []model.APIEndpoint
 	for path, pathItem := range swaggerDoc.Paths {
 		for method, operation := range pathItem.Operations() {
-			endpoints = append(endpoints, model.APIEndpoint{
-				Path:        path,
-				Method:      method,
-				Summary:     operation.Summary,
-				Description: operation.Description,
-				OperationID: operation.OperationID,
-			})
+			if v3Doc != nil {
+				if v3PathItem := v3Doc.Paths.Value(path); v3PathItem != nil {
+					if v3Operation := v3PathItem.GetOperation(method); v3Operation != nil {
+						endpoints = append(endpoints, newEndpoint(path, method, v3PathItem, v3Operation))
+						continue
+					}
+				}
+			}
+			endpoints = append(endpoints, basicSwagger2Endpoint(path, method, pathItem, operation))
 		}
 	}
+	sortEndpoints(endpoints)
 	return endpoints
 }
+
+// basicSwagger2Endpoint extracts the fields of an endpoint that need no conversion to OpenAPI 3.
+func basicSwagger2Endpoint(path, method string, pathItem *openapi2.PathItem, op *openapi2.Operation) model.APIEndpoint {
+	endpoint := model.APIEndpoint{
+		Path:        path,
+		Method:      strings.ToUpper(method),
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: op.OperationID,
+		Tags:        strings.Join(op.Tags, ","),
+		Parameters:  model.APIParameters{},
+		Responses:   "{}",
+	}
+	params := append(openapi2.Parameters{}, pathItem.Parameters...)
+	params = append(params, op.Parameters...)
+	for _, param := range params {
+		if param == nil || param.Ref != "" {
+			continue
+		}
+		t := ""
+		if param.Type != nil && len(param.Type.Slice()) > 0 {
+			t = param.Type.Slice()[0]
+		}
+		endpoint.Parameters = append(endpoint.Parameters, model.APIParameter{
+			Name:     param.Name,
+			In:       param.In,
+			Required: param.Required || param.In == "path",
+			Type:     t,
+		})
+	}
+	return endpoint
+}
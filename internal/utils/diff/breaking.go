@@ -0,0 +1,358 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Severity tells whether a change can break existing clients of an API.
+type Severity string
+
+const (
+	SeverityBreaking    Severity = "breaking"
+	SeverityNonBreaking Severity = "non-breaking"
+)
+
+// Kinds of a Change.
+const (
+	KindOperationAdded      = "operation-added"
+	KindOperationRemoved    = "operation-removed"
+	KindParameterAdded      = "parameter-added"
+	KindParameterRemoved    = "parameter-removed"
+	KindParameterRequired   = "parameter-became-required"
+	KindRequestBodyRequired = "request-body-became-required"
+	KindPropertyAdded       = "property-added"
+	KindPropertyRemoved     = "property-removed"
+	KindPropertyRequired    = "property-became-required"
+	KindTypeChanged         = "type-changed"
+	KindEnumNarrowed        = "enum-narrowed"
+	KindEnumWidened         = "enum-widened"
+	KindResponseAdded       = "response-added"
+	KindResponseRemoved     = "response-removed"
+)
+
+// Change is a single classified change between two documents.
+type Change struct {
+	Severity  Severity  `json:"severity"`
+	Kind      string    `json:"kind"`
+	Operation Operation `json:"operation"`          // Operation of the target document, or of the base one when it was removed
+	Location  string    `json:"location,omitempty"` // Where in the operation the change is, e.g. "query parameter limit" or "response 200 body.items[].id"
+	Message   string    `json:"message"`
+}
+
+// Analysis is the list of classified changes between a base and a target document.
+type Analysis struct {
+	Changes []Change `json:"changes"`
+}
+
+// Breaking returns the breaking changes of the analysis.
+func (a *Analysis) Breaking() []Change {
+	var out []Change
+	for _, c := range a.Changes {
+		if c.Severity == SeverityBreaking {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// HasBreaking reports whether any change of the analysis is breaking.
+func (a *Analysis) HasBreaking() bool {
+	for _, c := range a.Changes {
+		if c.Severity == SeverityBreaking {
+			return true
+		}
+	}
+	return false
+}
+
+// direction tells whether a schema is sent by clients or received by them, which decides how its changes are classified:
+// clients break when a request accepts less than before and when a response returns less, or other, values than before.
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+// analyzer collects the changes of one operation.
+type analyzer struct {
+	analysis *Analysis
+	op       Operation
+	visited  map[[2]*openapi3.Schema]bool
+}
+
+func (a *analyzer) add(severity Severity, kind, location, format string, args ...any) {
+	a.analysis.Changes = append(a.analysis.Changes, Change{
+		Severity:  severity,
+		Kind:      kind,
+		Operation: a.op,
+		Location:  location,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+// Analyze classifies the changes from base to target that affect clients of the operations of base: removed operations,
+// new required parameters, request bodies and request properties, removed response properties and responses, type changes
+// and enums that accept fewer values in requests or return more values in responses are breaking.
+// Schemas are compared by value, so changes of shared component schemas are reported on every operation that uses them.
+func Analyze(base, target *openapi3.T) *Analysis {
+	analysis := &Analysis{}
+	pairs, added, removed := matchOperations(operations(base), operations(target))
+	for _, o := range removed {
+		a := &analyzer{analysis: analysis, op: o.Operation}
+		a.add(SeverityBreaking, KindOperationRemoved, "", "operation %s %s was removed", o.Method, o.Path)
+	}
+	for _, p := range pairs {
+		a := &analyzer{analysis: analysis, op: p.target.Operation, visited: make(map[[2]*openapi3.Schema]bool)}
+		a.operation(p.base, p.target)
+	}
+	for _, o := range added {
+		a := &analyzer{analysis: analysis, op: o.Operation}
+		a.add(SeverityNonBreaking, KindOperationAdded, "", "operation %s %s was added", o.Method, o.Path)
+	}
+	return analysis
+}
+
+func (a *analyzer) operation(base, target operation) {
+	baseParams := parameters(base)
+	targetParams := parameters(target)
+	for _, key := range sortedKeys(targetParams) {
+		after := targetParams[key]
+		location := after.In + " parameter " + after.Name
+		before, ok := baseParams[key]
+		switch {
+		case !ok && after.Required:
+			a.add(SeverityBreaking, KindParameterAdded, location, "required %s was added", location)
+		case !ok:
+			a.add(SeverityNonBreaking, KindParameterAdded, location, "optional %s was added", location)
+		default:
+			if after.Required && !before.Required {
+				a.add(SeverityBreaking, KindParameterRequired, location, "%s became required", location)
+			}
+			a.schema(location, schemaValue(before.Schema), schemaValue(after.Schema), request)
+		}
+	}
+	for _, key := range sortedKeys(baseParams) {
+		if _, ok := targetParams[key]; ok {
+			continue
+		}
+		before := baseParams[key]
+		location := before.In + " parameter " + before.Name
+		a.add(SeverityNonBreaking, KindParameterRemoved, location, "%s was removed", location)
+	}
+
+	baseBody, baseRequired := requestBodySchema(base.op)
+	targetBody, targetRequired := requestBodySchema(target.op)
+	if targetRequired && !baseRequired {
+		a.add(SeverityBreaking, KindRequestBodyRequired, "request body", "request body became required")
+	}
+	a.schema("request body", schemaValue(baseBody), schemaValue(targetBody), request)
+
+	baseResponses := responseSchemas(base.op)
+	targetResponses := responseSchemas(target.op)
+	for _, status := range sortedKeys(targetResponses) {
+		location := "response " + status
+		before, ok := baseResponses[status]
+		if !ok {
+			a.add(SeverityNonBreaking, KindResponseAdded, location, "%s was added", location)
+			continue
+		}
+		a.schema(location+" body", schemaValue(before), schemaValue(targetResponses[status]), response)
+	}
+	for _, status := range sortedKeys(baseResponses) {
+		if _, ok := targetResponses[status]; ok {
+			continue
+		}
+		location := "response " + status
+		// Clients rely on the success responses they parse, removing an error response only narrows what they may receive
+		if strings.HasPrefix(status, "2") || status == "default" {
+			a.add(SeverityBreaking, KindResponseRemoved, location, "%s was removed", location)
+		} else {
+			a.add(SeverityNonBreaking, KindResponseRemoved, location, "%s was removed", location)
+		}
+	}
+}
+
+// schema compares two versions of a schema found at location. A schema missing on either side is not compared:
+// a body that appears or disappears as a whole is reported through the requiredness of the body instead.
+func (a *analyzer) schema(location string, before, after *openapi3.Schema, dir direction) {
+	if before == nil || after == nil {
+		return
+	}
+	key := [2]*openapi3.Schema{before, after}
+	if a.visited[key] {
+		return
+	}
+	a.visited[key] = true
+
+	beforeTypes, afterTypes := types(before), types(after)
+	if len(beforeTypes) > 0 && len(afterTypes) > 0 && !sameStrings(beforeTypes, afterTypes) {
+		a.add(SeverityBreaking, KindTypeChanged, location, "type of %s changed from %s to %s",
+			location, strings.Join(beforeTypes, "|"), strings.Join(afterTypes, "|"))
+		return
+	}
+
+	a.enum(location, before.Enum, after.Enum, dir)
+
+	beforeProps, afterProps := properties(before), properties(after)
+	beforeRequired, afterRequired := required(before), required(after)
+	for _, name := range sortedKeys(afterProps) {
+		propLocation := location + "." + name
+		if _, ok := beforeProps[name]; !ok {
+			if dir == request && afterRequired[name] {
+				a.add(SeverityBreaking, KindPropertyAdded, propLocation, "required property %s was added", propLocation)
+			} else {
+				a.add(SeverityNonBreaking, KindPropertyAdded, propLocation, "property %s was added", propLocation)
+			}
+			continue
+		}
+		if dir == request && afterRequired[name] && !beforeRequired[name] {
+			a.add(SeverityBreaking, KindPropertyRequired, propLocation, "property %s became required", propLocation)
+		}
+		a.schema(propLocation, schemaValue(beforeProps[name]), schemaValue(afterProps[name]), dir)
+	}
+	for _, name := range sortedKeys(beforeProps) {
+		if _, ok := afterProps[name]; ok {
+			continue
+		}
+		propLocation := location + "." + name
+		if dir == response {
+			a.add(SeverityBreaking, KindPropertyRemoved, propLocation, "response property %s was removed", propLocation)
+		} else {
+			a.add(SeverityNonBreaking, KindPropertyRemoved, propLocation, "property %s was removed", propLocation)
+		}
+	}
+
+	a.schema(location+"[]", schemaValue(before.Items), schemaValue(after.Items), dir)
+}
+
+// enum reports values removed from or added to an enum. An enum added to a schema that had none narrows it,
+// an enum removed from a schema widens it.
+func (a *analyzer) enum(location string, before, after []any, dir direction) {
+	if len(before) == 0 && len(after) == 0 {
+		return
+	}
+	removed := missingValues(before, after)
+	added := missingValues(after, before)
+	narrowed := len(removed) > 0 || (len(before) == 0 && len(after) > 0)
+	widened := len(added) > 0 || (len(before) > 0 && len(after) == 0)
+
+	if narrowed {
+		severity := SeverityNonBreaking
+		if dir == request {
+			severity = SeverityBreaking
+		}
+		if len(removed) > 0 {
+			a.add(severity, KindEnumNarrowed, location, "values %s are no longer allowed in %s", strings.Join(removed, ", "), location)
+		} else {
+			a.add(severity, KindEnumNarrowed, location, "%s is restricted to an enum", location)
+		}
+	}
+	if widened {
+		severity := SeverityNonBreaking
+		if dir == response {
+			severity = SeverityBreaking
+		}
+		if len(added) > 0 {
+			a.add(severity, KindEnumWidened, location, "values %s were added to %s", strings.Join(added, ", "), location)
+		} else {
+			a.add(severity, KindEnumWidened, location, "%s is no longer restricted to an enum", location)
+		}
+	}
+}
+
+func schemaValue(ref *openapi3.SchemaRef) *openapi3.Schema {
+	if ref == nil {
+		return nil
+	}
+	return ref.Value
+}
+
+// types returns the sorted types of a schema, taken from its allOf members when it declares none.
+func types(s *openapi3.Schema) []string {
+	if s.Type != nil && len(*s.Type) > 0 {
+		out := append([]string(nil), s.Type.Slice()...)
+		sort.Strings(out)
+		return out
+	}
+	for _, ref := range s.AllOf {
+		if v := schemaValue(ref); v != nil {
+			if out := types(v); len(out) > 0 {
+				return out
+			}
+		}
+	}
+	return nil
+}
+
+// properties returns the properties of a schema including those of its allOf members.
+func properties(s *openapi3.Schema) openapi3.Schemas {
+	out := make(openapi3.Schemas, len(s.Properties))
+	for _, ref := range s.AllOf {
+		if v := schemaValue(ref); v != nil {
+			for name, prop := range properties(v) {
+				out[name] = prop
+			}
+		}
+	}
+	for name, prop := range s.Properties {
+		out[name] = prop
+	}
+	return out
+}
+
+// required returns the required properties of a schema including those of its allOf members.
+func required(s *openapi3.Schema) map[string]bool {
+	out := make(map[string]bool, len(s.Required))
+	for _, ref := range s.AllOf {
+		if v := schemaValue(ref); v != nil {
+			for name := range required(v) {
+				out[name] = true
+			}
+		}
+	}
+	for _, name := range s.Required {
+		out[name] = true
+	}
+	return out
+}
+
+// missingValues returns the JSON encoding of the values of from that are not in to.
+func missingValues(from, to []any) []string {
+	present := make(map[string]bool, len(to))
+	for _, v := range to {
+		present[encodeValue(v)] = true
+	}
+	var out []string
+	for _, v := range from {
+		if e := encodeValue(v); !present[e] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func encodeValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
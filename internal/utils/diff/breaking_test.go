@@ -0,0 +1,208 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const breakingBase = `
+openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: limit, in: query, schema: {type: integer}}
+        - {name: status, in: query, schema: {type: string, enum: [available, pending, sold]}}
+        - {name: sort, in: query, schema: {type: string}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+        "400": {description: Bad request}
+    post:
+      operationId: createPet
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/NewPet'}
+      responses:
+        "201": {description: Created}
+  /pets/{id}:
+    delete:
+      operationId: deletePet
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        "204": {description: Deleted}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id: {type: string}
+        name: {type: string}
+        kind: {type: string, enum: [cat, dog]}
+    NewPet:
+      type: object
+      properties:
+        name: {type: string}
+`
+
+const breakingTarget = `
+openapi: 3.0.3
+info: {title: Pets, version: "2.0"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: limit, in: query, required: true, schema: {type: string}}
+        - {name: status, in: query, schema: {type: string, enum: [available, sold]}}
+        - {name: owner, in: query, schema: {type: string}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/NewPet'}
+      responses:
+        "201": {description: Created}
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        "200": {description: OK}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+        kind: {type: string, enum: [cat, dog, bird]}
+        age: {type: integer}
+    NewPet:
+      type: object
+      required: [name, kind]
+      properties:
+        name: {type: string}
+        kind: {type: string}
+`
+
+// kinds indexes the changes of an analysis by kind and location
+func kinds(a *Analysis) map[string]Severity {
+	out := make(map[string]Severity)
+	for _, c := range a.Changes {
+		out[c.Operation.OperationID+" "+c.Kind+" "+c.Location] = c.Severity
+	}
+	return out
+}
+
+func TestAnalyze(t *testing.T) {
+	analysis := Analyze(load(t, breakingBase), load(t, breakingTarget))
+	got := kinds(analysis)
+
+	expected := map[string]Severity{
+		"deletePet operation-removed ":                             SeverityBreaking,
+		"getPet operation-added ":                                  SeverityNonBreaking,
+		"listPets parameter-became-required query parameter limit": SeverityBreaking,
+		"listPets type-changed query parameter limit":              SeverityBreaking,
+		"listPets enum-narrowed query parameter status":            SeverityBreaking,
+		"listPets parameter-added query parameter owner":           SeverityNonBreaking,
+		"listPets parameter-removed query parameter sort":          SeverityNonBreaking,
+		"listPets property-removed response 200 body[].id":         SeverityBreaking,
+		"listPets property-added response 200 body[].age":          SeverityNonBreaking,
+		"listPets enum-widened response 200 body[].kind":           SeverityBreaking,
+		"listPets response-removed response 400":                   SeverityNonBreaking,
+		"createPet request-body-became-required request body":      SeverityBreaking,
+		"createPet property-became-required request body.name":     SeverityBreaking,
+		"createPet property-added request body.kind":               SeverityBreaking,
+	}
+	assert.Equal(t, expected, got)
+	assert.True(t, analysis.HasBreaking())
+	assert.Len(t, analysis.Breaking(), 9)
+}
+
+func TestAnalyze_NonBreaking(t *testing.T) {
+	analysis := Analyze(load(t, breakingTarget), load(t, breakingTarget))
+	assert.Empty(t, analysis.Changes)
+	assert.False(t, analysis.HasBreaking())
+
+	// A wider request enum and a removed response enum value do not break clients
+	base := `
+openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: status, in: query, schema: {type: string, enum: [available]}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: object, properties: {status: {type: string, enum: [available, sold]}}}
+`
+	target := `
+openapi: 3.0.3
+info: {title: Pets, version: "1.1"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: status, in: query, schema: {type: string, enum: [available, sold]}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: object, properties: {status: {type: string, enum: [available]}}}
+`
+	analysis = Analyze(load(t, base), load(t, target))
+	require.Len(t, analysis.Changes, 2)
+	assert.False(t, analysis.HasBreaking())
+	assert.Equal(t, KindEnumWidened, analysis.Changes[0].Kind)
+	assert.Equal(t, KindEnumNarrowed, analysis.Changes[1].Kind)
+}
+
+func TestAnalyze_RecursiveSchema(t *testing.T) {
+	spec := `
+openapi: 3.0.3
+info: {title: Tree, version: "1.0"}
+paths:
+  /nodes:
+    get:
+      operationId: listNodes
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Node'}
+components:
+  schemas:
+    Node:
+      type: object
+      properties:
+        children: {type: array, items: {$ref: '#/components/schemas/Node'}}
+`
+	analysis := Analyze(load(t, spec), load(t, spec))
+	assert.Empty(t, analysis.Changes)
+}
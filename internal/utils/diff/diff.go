@@ -0,0 +1,353 @@
+// Package diff computes structural differences between two OpenAPI 3 documents.
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Report is the structural difference between a base and a target document.
+type Report struct {
+	AddedOperations   []Operation       `json:"added_operations"`
+	RemovedOperations []Operation       `json:"removed_operations"`
+	ChangedOperations []OperationChange `json:"changed_operations"`
+	AddedSchemas      []string          `json:"added_schemas"`
+	RemovedSchemas    []string          `json:"removed_schemas"`
+	ChangedSchemas    []SchemaChange    `json:"changed_schemas"`
+}
+
+// Empty reports whether the documents have no structural difference.
+func (r *Report) Empty() bool {
+	return len(r.AddedOperations) == 0 && len(r.RemovedOperations) == 0 && len(r.ChangedOperations) == 0 &&
+		len(r.AddedSchemas) == 0 && len(r.RemovedSchemas) == 0 && len(r.ChangedSchemas) == 0
+}
+
+// Operation identifies an operation of a document.
+type Operation struct {
+	OperationID string `json:"operation_id,omitempty"`
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+}
+
+// OperationChange describes an operation present in both documents whose parameters, request body or responses differ.
+// Operations are matched by operationId first and by method and path otherwise, so Path and Method are those of the target.
+type OperationChange struct {
+	Operation
+	BasePath          string            `json:"base_path,omitempty"`   // Path in the base document, when it changed
+	BaseMethod        string            `json:"base_method,omitempty"` // Method in the base document, when it changed
+	AddedParameters   []Parameter       `json:"added_parameters,omitempty"`
+	RemovedParameters []Parameter       `json:"removed_parameters,omitempty"`
+	ChangedParameters []ParameterChange `json:"changed_parameters,omitempty"`
+	RequestBody       *SchemaChange     `json:"request_body,omitempty"`
+	Responses         []ResponseChange  `json:"responses,omitempty"`
+}
+
+// Parameter is a parameter of an operation, including the parameters inherited from its path item.
+type Parameter struct {
+	Name     string              `json:"name"`
+	In       string              `json:"in"`
+	Required bool                `json:"required"`
+	Schema   *openapi3.SchemaRef `json:"schema,omitempty"`
+}
+
+// ParameterChange describes a parameter whose requiredness or schema changed.
+type ParameterChange struct {
+	Name   string    `json:"name"`
+	In     string    `json:"in"`
+	Before Parameter `json:"before"`
+	After  Parameter `json:"after"`
+}
+
+// SchemaChange holds both versions of a schema. Before is nil when the schema was added and After when it was removed.
+type SchemaChange struct {
+	Name     string              `json:"name,omitempty"`
+	Required *RequiredChange     `json:"required,omitempty"` // Set for request bodies whose requiredness changed
+	Before   *openapi3.SchemaRef `json:"before"`
+	After    *openapi3.SchemaRef `json:"after"`
+}
+
+// RequiredChange describes a change of the requiredness of a request body.
+type RequiredChange struct {
+	Before bool `json:"before"`
+	After  bool `json:"after"`
+}
+
+// ResponseChange describes a response added, removed or whose schema changed.
+type ResponseChange struct {
+	Status string              `json:"status"`
+	Before *openapi3.SchemaRef `json:"before"`
+	After  *openapi3.SchemaRef `json:"after"`
+}
+
+// operation is an operation of a document together with its path item.
+type operation struct {
+	Operation
+	item *openapi3.PathItem
+	op   *openapi3.Operation
+}
+
+// Documents compares base with target.
+func Documents(base, target *openapi3.T) *Report {
+	report := &Report{}
+	pairs, added, removed := matchOperations(operations(base), operations(target))
+	for _, o := range added {
+		report.AddedOperations = append(report.AddedOperations, o.Operation)
+	}
+	for _, p := range pairs {
+		if change, changed := compareOperations(p.base, p.target); changed {
+			report.ChangedOperations = append(report.ChangedOperations, change)
+		}
+	}
+	for _, o := range removed {
+		report.RemovedOperations = append(report.RemovedOperations, o.Operation)
+	}
+
+	baseSchemas := componentSchemas(base)
+	targetSchemas := componentSchemas(target)
+	for _, name := range sortedKeys(targetSchemas) {
+		before, ok := baseSchemas[name]
+		switch {
+		case !ok:
+			report.AddedSchemas = append(report.AddedSchemas, name)
+		case !sameSchema(before, targetSchemas[name]):
+			report.ChangedSchemas = append(report.ChangedSchemas, SchemaChange{Name: name, Before: before, After: targetSchemas[name]})
+		}
+	}
+	for _, name := range sortedKeys(baseSchemas) {
+		if _, ok := targetSchemas[name]; !ok {
+			report.RemovedSchemas = append(report.RemovedSchemas, name)
+		}
+	}
+	return report
+}
+
+// operations returns the operations of doc ordered by path and method.
+func operations(doc *openapi3.T) []operation {
+	var ops []operation
+	if doc == nil || doc.Paths == nil {
+		return ops
+	}
+	for path, item := range doc.Paths.Map() {
+		for method, op := range item.Operations() {
+			ops = append(ops, operation{
+				Operation: Operation{OperationID: op.OperationID, Method: strings.ToUpper(method), Path: path},
+				item:      item,
+				op:        op,
+			})
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+		return ops[i].Method < ops[j].Method
+	})
+	return ops
+}
+
+// operationPair is an operation of the base document matched with one of the target document.
+type operationPair struct {
+	base, target operation
+}
+
+// matchOperations matches the operations of target with those of base by operationId first and by method and path otherwise.
+// Pairs and added operations follow the order of targetOps, removed operations the order of baseOps.
+func matchOperations(baseOps, targetOps []operation) (pairs []operationPair, added, removed []operation) {
+	matched := make([]bool, len(baseOps))
+	byOperationID := make(map[string]int, len(baseOps))
+	byRoute := make(map[string]int, len(baseOps))
+	for i, o := range baseOps {
+		if o.OperationID != "" {
+			if _, ok := byOperationID[o.OperationID]; !ok {
+				byOperationID[o.OperationID] = i
+			}
+		}
+		byRoute[o.Method+" "+o.Path] = i
+	}
+	match := func(o operation) (int, bool) {
+		if i, ok := byOperationID[o.OperationID]; ok && o.OperationID != "" && !matched[i] {
+			return i, true
+		}
+		if i, ok := byRoute[o.Method+" "+o.Path]; ok && !matched[i] {
+			return i, true
+		}
+		return 0, false
+	}
+
+	for _, o := range targetOps {
+		i, ok := match(o)
+		if !ok {
+			added = append(added, o)
+			continue
+		}
+		matched[i] = true
+		pairs = append(pairs, operationPair{base: baseOps[i], target: o})
+	}
+	for i, o := range baseOps {
+		if !matched[i] {
+			removed = append(removed, o)
+		}
+	}
+	return pairs, added, removed
+}
+
+// compareOperations returns the change between two matched operations and whether there is any.
+func compareOperations(base, target operation) (OperationChange, bool) {
+	change := OperationChange{Operation: target.Operation}
+	if base.Path != target.Path {
+		change.BasePath = base.Path
+	}
+	if base.Method != target.Method {
+		change.BaseMethod = base.Method
+	}
+
+	baseParams := parameters(base)
+	targetParams := parameters(target)
+	for _, key := range sortedKeys(targetParams) {
+		after := targetParams[key]
+		before, ok := baseParams[key]
+		switch {
+		case !ok:
+			change.AddedParameters = append(change.AddedParameters, after)
+		case before.Required != after.Required || !sameSchema(before.Schema, after.Schema):
+			change.ChangedParameters = append(change.ChangedParameters, ParameterChange{Name: after.Name, In: after.In, Before: before, After: after})
+		}
+	}
+	for _, key := range sortedKeys(baseParams) {
+		if _, ok := targetParams[key]; !ok {
+			change.RemovedParameters = append(change.RemovedParameters, baseParams[key])
+		}
+	}
+
+	baseBody, baseRequired := requestBodySchema(base.op)
+	targetBody, targetRequired := requestBodySchema(target.op)
+	if !sameSchema(baseBody, targetBody) || baseRequired != targetRequired {
+		change.RequestBody = &SchemaChange{Before: baseBody, After: targetBody}
+		if baseRequired != targetRequired {
+			change.RequestBody.Required = &RequiredChange{Before: baseRequired, After: targetRequired}
+		}
+	}
+
+	baseResponses := responseSchemas(base.op)
+	targetResponses := responseSchemas(target.op)
+	for _, status := range sortedKeys(targetResponses) {
+		before, ok := baseResponses[status]
+		if !ok || !sameSchema(before, targetResponses[status]) {
+			change.Responses = append(change.Responses, ResponseChange{Status: status, Before: before, After: targetResponses[status]})
+		}
+	}
+	for _, status := range sortedKeys(baseResponses) {
+		if _, ok := targetResponses[status]; !ok {
+			change.Responses = append(change.Responses, ResponseChange{Status: status, Before: baseResponses[status]})
+		}
+	}
+
+	changed := change.BasePath != "" || change.BaseMethod != "" ||
+		len(change.AddedParameters) > 0 || len(change.RemovedParameters) > 0 || len(change.ChangedParameters) > 0 ||
+		change.RequestBody != nil || len(change.Responses) > 0
+	return change, changed
+}
+
+// parameters returns the parameters of an operation keyed by location and name; operation parameters override path item ones.
+func parameters(o operation) map[string]Parameter {
+	out := make(map[string]Parameter)
+	for _, params := range []openapi3.Parameters{o.item.Parameters, o.op.Parameters} {
+		for _, ref := range params {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			p := ref.Value
+			schema := p.Schema
+			if schema == nil {
+				for _, media := range p.Content {
+					if media != nil {
+						schema = media.Schema
+						break
+					}
+				}
+			}
+			out[p.In+":"+p.Name] = Parameter{
+				Name:     p.Name,
+				In:       p.In,
+				Required: p.Required || p.In == openapi3.ParameterInPath,
+				Schema:   schema,
+			}
+		}
+	}
+	return out
+}
+
+// requestBodySchema returns the schema of the preferred media type of the request body and whether the body is required.
+func requestBodySchema(op *openapi3.Operation) (*openapi3.SchemaRef, bool) {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return nil, false
+	}
+	body := op.RequestBody.Value
+	return contentSchema(body.Content), body.Required
+}
+
+// responseSchemas returns the schema of the preferred media type of every response keyed by status code.
+// Responses without content are kept with a nil schema, so added and removed responses are still reported.
+func responseSchemas(op *openapi3.Operation) map[string]*openapi3.SchemaRef {
+	out := make(map[string]*openapi3.SchemaRef)
+	if op.Responses == nil {
+		return out
+	}
+	for status, ref := range op.Responses.Map() {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		out[status] = contentSchema(ref.Value.Content)
+	}
+	return out
+}
+
+// contentSchema returns the schema of the JSON media type, or of the first media type in name order.
+func contentSchema(content openapi3.Content) *openapi3.SchemaRef {
+	if len(content) == 0 {
+		return nil
+	}
+	if media := content.Get("application/json"); media != nil {
+		return media.Schema
+	}
+	for _, name := range sortedKeys(content) {
+		if media := content[name]; media != nil && media.Schema != nil {
+			return media.Schema
+		}
+	}
+	return nil
+}
+
+func componentSchemas(doc *openapi3.T) openapi3.Schemas {
+	if doc == nil || doc.Components == nil {
+		return openapi3.Schemas{}
+	}
+	return doc.Components.Schemas
+}
+
+// sameSchema compares two schemas by their JSON encoding, in which references are kept as $ref
+// and object keys are sorted.
+func sameSchema(a, b *openapi3.SchemaRef) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return string(ja) == string(jb)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,143 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baseSpec = `
+openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: limit, in: query, schema: {type: integer}}
+        - {name: sort, in: query, schema: {type: string}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+    post:
+      operationId: createPet
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        "201": {description: Created}
+  /pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    delete:
+      operationId: deletePet
+      responses:
+        "204": {description: Deleted}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+    Error:
+      type: object
+`
+
+const targetSpec = `
+openapi: 3.0.3
+info: {title: Pets, version: "2.0"}
+paths:
+  /animals:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: limit, in: query, required: true, schema: {type: integer}}
+        - {name: owner, in: query, schema: {type: string}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+        "404": {description: Not found}
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        "201": {description: Created}
+  /pets/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      operationId: getPet
+      responses:
+        "200": {description: OK}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+    Owner:
+      type: object
+`
+
+func load(t *testing.T, data string) *openapi3.T {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(data))
+	require.NoError(t, err)
+	return doc
+}
+
+func TestDocuments(t *testing.T) {
+	report := Documents(load(t, baseSpec), load(t, targetSpec))
+
+	assert.Equal(t, []Operation{{OperationID: "getPet", Method: "GET", Path: "/pets/{id}"}}, report.AddedOperations)
+	assert.Equal(t, []Operation{{OperationID: "deletePet", Method: "DELETE", Path: "/pets/{id}"}}, report.RemovedOperations)
+	require.Len(t, report.ChangedOperations, 2)
+
+	// 按 operationId 匹配到路径变化的操作
+	list := report.ChangedOperations[0]
+	assert.Equal(t, "listPets", list.OperationID)
+	assert.Equal(t, "/animals", list.Path)
+	assert.Equal(t, "/pets", list.BasePath)
+	require.Len(t, list.AddedParameters, 1)
+	assert.Equal(t, "owner", list.AddedParameters[0].Name)
+	require.Len(t, list.RemovedParameters, 1)
+	assert.Equal(t, "sort", list.RemovedParameters[0].Name)
+	require.Len(t, list.ChangedParameters, 1)
+	assert.Equal(t, "limit", list.ChangedParameters[0].Name)
+	assert.False(t, list.ChangedParameters[0].Before.Required)
+	assert.True(t, list.ChangedParameters[0].After.Required)
+	assert.Nil(t, list.RequestBody)
+	require.Len(t, list.Responses, 1)
+	assert.Equal(t, "404", list.Responses[0].Status)
+	assert.Nil(t, list.Responses[0].Before)
+
+	create := report.ChangedOperations[1]
+	assert.Equal(t, "createPet", create.OperationID)
+	require.NotNil(t, create.RequestBody)
+	assert.Equal(t, &RequiredChange{Before: false, After: true}, create.RequestBody.Required)
+
+	assert.Equal(t, []string{"Owner"}, report.AddedSchemas)
+	assert.Equal(t, []string{"Error"}, report.RemovedSchemas)
+	require.Len(t, report.ChangedSchemas, 1)
+	assert.Equal(t, "Pet", report.ChangedSchemas[0].Name)
+	assert.False(t, report.Empty())
+}
+
+func TestDocuments_Identical(t *testing.T) {
+	report := Documents(load(t, baseSpec), load(t, baseSpec))
+	assert.True(t, report.Empty())
+}
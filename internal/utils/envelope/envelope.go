@@ -0,0 +1,168 @@
+// Package envelope implements envelope encryption with AES-256-GCM.
+//
+// Every value is encrypted with its own random data key, and the data key is encrypted (wrapped)
+// with a master key from a Keyring. Rotating the master key only re-wraps the data keys,
+// the encrypted values are left untouched.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// KeySize is the size in bytes of master keys and data keys.
+const KeySize = 32
+
+// ErrNoKeys is returned by NewKeyring when no master key is configured.
+var ErrNoKeys = errors.New("no master key configured")
+
+// Sealed is an encrypted value together with its wrapped data key.
+type Sealed struct {
+	KeyID      string // ID of the master key that wrapped the data key
+	WrappedKey []byte // Nonce followed by the data key encrypted with the master key
+	Ciphertext []byte // Nonce followed by the value encrypted with the data key
+}
+
+// Keyring holds the master keys by ID. New values are wrapped with the active key,
+// the other keys are kept to open values sealed before a rotation.
+type Keyring struct {
+	active string
+	keys   map[string][]byte
+}
+
+// NewKeyring creates a Keyring from base64 encoded 32-byte master keys.
+// active may be empty when exactly one key is configured.
+func NewKeyring(keys map[string]string, active string) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+	k := &Keyring{active: active, keys: make(map[string][]byte, len(keys))}
+	for id, encoded := range keys {
+		if id == "" {
+			return nil, errors.New("master key id must not be empty")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("master key %s is not valid base64: %w", id, err)
+		}
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("master key %s must be %d bytes, got %d", id, KeySize, len(key))
+		}
+		k.keys[id] = key
+		if active == "" && len(keys) == 1 {
+			k.active = id
+		}
+	}
+	if k.active == "" {
+		return nil, errors.New("the active master key must be set when several keys are configured")
+	}
+	if _, ok := k.keys[k.active]; !ok {
+		return nil, fmt.Errorf("active master key %s is not configured", k.active)
+	}
+	return k, nil
+}
+
+// ActiveKeyID returns the ID of the key new values are wrapped with.
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+// Seal encrypts plaintext with a new data key wrapped by the active master key.
+func (k *Keyring) Seal(plaintext []byte) (*Sealed, error) {
+	dataKey := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+	ciphertext, err := encrypt(dataKey, plaintext, nil)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := encrypt(k.keys[k.active], dataKey, []byte(k.active))
+	if err != nil {
+		return nil, err
+	}
+	return &Sealed{KeyID: k.active, WrappedKey: wrapped, Ciphertext: ciphertext}, nil
+}
+
+// Open decrypts a sealed value.
+func (k *Keyring) Open(s *Sealed) ([]byte, error) {
+	dataKey, err := k.unwrap(s)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := decrypt(dataKey, s.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+// Rewrap wraps the data key of s with the active master key in place.
+// It reports false when s is already wrapped by the active key.
+func (k *Keyring) Rewrap(s *Sealed) (bool, error) {
+	if s.KeyID == k.active {
+		return false, nil
+	}
+	dataKey, err := k.unwrap(s)
+	if err != nil {
+		return false, err
+	}
+	wrapped, err := encrypt(k.keys[k.active], dataKey, []byte(k.active))
+	if err != nil {
+		return false, err
+	}
+	s.KeyID, s.WrappedKey = k.active, wrapped
+	return true, nil
+}
+
+// unwrap decrypts the data key of s with the master key it was wrapped by.
+// The key ID is bound as additional data, so a wrapped key cannot be moved to another master key.
+func (k *Keyring) unwrap(s *Sealed) ([]byte, error) {
+	key, ok := k.keys[s.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("master key %s is not configured", s.KeyID)
+	}
+	dataKey, err := decrypt(key, s.WrappedKey, []byte(s.KeyID))
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key with master key %s: %w", s.KeyID, err)
+	}
+	return dataKey, nil
+}
+
+// encrypt seals plaintext with AES-GCM and prepends the random nonce.
+func encrypt(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// decrypt opens a value produced by encrypt.
+func decrypt(key, data, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
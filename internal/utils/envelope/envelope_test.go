@@ -0,0 +1,90 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, KeySize))
+}
+
+func TestKeyring_SealOpen(t *testing.T) {
+	k, err := NewKeyring(map[string]string{"k1": testKey(1)}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "k1", k.ActiveKeyID(), "a single key is active by default")
+
+	a, err := k.Seal([]byte("s3cret"))
+	require.NoError(t, err)
+	b, err := k.Seal([]byte("s3cret"))
+	require.NoError(t, err)
+	assert.Equal(t, "k1", a.KeyID)
+	assert.NotContains(t, string(a.Ciphertext), "s3cret")
+	assert.NotEqual(t, a.Ciphertext, b.Ciphertext, "every value has its own data key and nonce")
+
+	plaintext, err := k.Open(a)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", string(plaintext))
+
+	a.Ciphertext[len(a.Ciphertext)-1] ^= 1
+	_, err = k.Open(a)
+	assert.Error(t, err, "tampered values are rejected")
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	old, err := NewKeyring(map[string]string{"k1": testKey(1)}, "k1")
+	require.NoError(t, err)
+	sealed, err := old.Seal([]byte("token"))
+	require.NoError(t, err)
+	ciphertext := append([]byte(nil), sealed.Ciphertext...)
+
+	rotated, err := NewKeyring(map[string]string{"k1": testKey(1), "k2": testKey(2)}, "k2")
+	require.NoError(t, err)
+	plaintext, err := rotated.Open(sealed)
+	require.NoError(t, err, "values sealed before the rotation can still be opened")
+	assert.Equal(t, "token", string(plaintext))
+
+	changed, err := rotated.Rewrap(sealed)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "k2", sealed.KeyID)
+	assert.Equal(t, ciphertext, sealed.Ciphertext, "only the data key is re-wrapped")
+	changed, err = rotated.Rewrap(sealed)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// 旧主密钥移除后仍可解密
+	current, err := NewKeyring(map[string]string{"k2": testKey(2)}, "")
+	require.NoError(t, err)
+	plaintext, err = current.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "token", string(plaintext))
+
+	_, err = old.Open(sealed)
+	assert.ErrorContains(t, err, "master key k2 is not configured")
+}
+
+func TestKeyring_WrappedKeyBoundToKeyID(t *testing.T) {
+	k, err := NewKeyring(map[string]string{"k1": testKey(1), "k2": testKey(1)}, "k1")
+	require.NoError(t, err)
+	sealed, err := k.Seal([]byte("v"))
+	require.NoError(t, err)
+	sealed.KeyID = "k2"
+	_, err = k.Open(sealed)
+	assert.Error(t, err)
+}
+
+func TestNewKeyring_Invalid(t *testing.T) {
+	_, err := NewKeyring(nil, "")
+	assert.ErrorIs(t, err, ErrNoKeys)
+	_, err = NewKeyring(map[string]string{"k1": "c2hvcnQ="}, "")
+	assert.ErrorContains(t, err, "must be 32 bytes")
+	_, err = NewKeyring(map[string]string{"k1": testKey(1), "k2": testKey(2)}, "")
+	assert.Error(t, err, "the active key is ambiguous")
+	_, err = NewKeyring(map[string]string{"k1": testKey(1)}, "k3")
+	assert.ErrorContains(t, err, "k3")
+}
@@ -2,14 +2,52 @@ package httpclient
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptrace"
+	"sync"
 	"time"
 )
 
 // HTTPClient 封装 http 访问能力，便于 mock 和扩展
 type HTTPClient interface {
-	DoRequest(ctx context.Context, method, url string, body io.Reader) (string, error)
+	// DoRequest 发送请求并返回完整的响应，非 2xx 状态码不视为错误，由调用方根据 StatusCode 判断
+	DoRequest(ctx context.Context, method, url string, headers map[string]string, body io.Reader) (*Response, error)
+}
+
+// Response 为一次请求的结构化结果
+type Response struct {
+	StatusCode  int         `json:"status_code"`  // 响应状态码
+	Status      string      `json:"status"`       // 响应状态行，如 200 OK
+	Headers     http.Header `json:"headers"`      // 响应头
+	Body        string      `json:"body"`         // 响应体
+	ContentType string      `json:"content_type"` // 响应的 Content-Type
+	Size        int64       `json:"size"`         // 响应体的字节数
+	URL         string      `json:"url"`          // 跟随重定向后最终请求的地址
+	Timing      Timing      `json:"timing"`       // 各阶段耗时
+}
+
+// Timing 为请求各阶段的耗时，复用连接时 DNS、Connect、TLS 为 0
+type Timing struct {
+	DNS     time.Duration // DNS 解析
+	Connect time.Duration // 建立 TCP 连接
+	TLS     time.Duration // TLS 握手
+	TTFB    time.Duration // 从开始请求到收到响应首字节
+	Total   time.Duration // 从开始请求到读完响应体
+}
+
+// MarshalJSON 以毫秒输出各阶段耗时
+func (t Timing) MarshalJSON() ([]byte, error) {
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+	return json.Marshal(map[string]float64{
+		"dns_ms":     ms(t.DNS),
+		"connect_ms": ms(t.Connect),
+		"tls_ms":     ms(t.TLS),
+		"ttfb_ms":    ms(t.TTFB),
+		"total_ms":   ms(t.Total),
+	})
 }
 
 // HTTPClientOption 用于自定义 http client 配置
@@ -43,21 +81,62 @@ func WithTransport(transport http.RoundTripper) HTTPClientOption {
 	}
 }
 
-func (c *DefaultHTTPClient) DoRequest(ctx context.Context, method, url string, body io.Reader) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
+func (c *DefaultHTTPClient) DoRequest(ctx context.Context, method, url string, headers map[string]string, body io.Reader) (*Response, error) {
+	// 发生重定向时各阶段记录的是最后一次请求的耗时
+	// trace 回调可能在其他 goroutine 中并发执行（如同时尝试多个地址建连），读写计时需要加锁
+	var (
+		mu                               sync.Mutex
+		timing                           Timing
+		dnsStart, connectStart, tlsStart time.Time
+	)
+	locked := func(f func()) {
+		mu.Lock()
+		defer mu.Unlock()
+		f()
+	}
+	start := time.Now()
+	trace := &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { locked(func() { dnsStart = time.Now() }) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { locked(func() { timing.DNS = time.Since(dnsStart) }) },
+		ConnectStart:         func(string, string) { locked(func() { connectStart = time.Now() }) },
+		ConnectDone:          func(string, string, error) { locked(func() { timing.Connect = time.Since(connectStart) }) },
+		TLSHandshakeStart:    func() { locked(func() { tlsStart = time.Now() }) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { locked(func() { timing.TLS = time.Since(tlsStart) }) },
+		GotFirstResponseByte: func() { locked(func() { timing.TTFB = time.Since(start) }) },
+	}
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), method, url, body)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
 	}
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 	respBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(respBytes), nil
+	var result Timing
+	locked(func() {
+		timing.Total = time.Since(start)
+		result = timing
+	})
+
+	return &Response{
+		StatusCode:  resp.StatusCode,
+		Status:      resp.Status,
+		Headers:     resp.Header,
+		Body:        string(respBytes),
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        int64(len(respBytes)),
+		URL:         resp.Request.URL.String(),
+		Timing:      result,
+	}, nil
 }
 
 // DefaultTransport 返回带有合理默认配置的 http.Transport
@@ -0,0 +1,60 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultHTTPClient_DoRequest(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusFound)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request", r.Header.Get("X-Request"))
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := NewHTTPClient(WithTimeout(5))
+
+	// 跟随重定向，返回最终地址、响应头与耗时
+	resp, err := client.DoRequest(context.Background(), "GET", server.URL+"/old", map[string]string{"X-Request": "1"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "200 OK", resp.Status)
+	assert.Equal(t, `{"ok":true}`, resp.Body)
+	assert.Equal(t, int64(len(resp.Body)), resp.Size)
+	assert.Equal(t, "application/json", resp.ContentType)
+	assert.Equal(t, "1", resp.Headers.Get("X-Request"))
+	assert.Equal(t, server.URL+"/new", resp.URL)
+	assert.Positive(t, resp.Timing.TTFB)
+	assert.GreaterOrEqual(t, resp.Timing.Total, resp.Timing.TTFB)
+
+	// 非 2xx 状态码不视为错误
+	resp, err = client.DoRequest(context.Background(), "GET", server.URL+"/fail", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+	assert.Equal(t, "boom\n", resp.Body)
+}
+
+func TestTiming_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(Timing{DNS: 1500 * time.Microsecond, Total: 20 * time.Millisecond})
+	require.NoError(t, err)
+	var got map[string]float64
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, 1.5, got["dns_ms"])
+	assert.Equal(t, 20.0, got["total_ms"])
+	assert.Contains(t, got, "ttfb_ms")
+}
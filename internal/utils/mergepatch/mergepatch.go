@@ -0,0 +1,54 @@
+// Package mergepatch applies JSON Merge Patch documents (RFC 7386).
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Apply applies patch to the JSON document doc and returns the patched document.
+// Objects in the patch are merged recursively, null removes a member and any other value,
+// arrays included, replaces the target value.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var target interface{}
+	if len(bytes.TrimSpace(doc)) > 0 {
+		if err := json.Unmarshal(doc, &target); err != nil {
+			return nil, fmt.Errorf("invalid document: %w", err)
+		}
+	}
+	var p interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	return json.Marshal(merge(target, p))
+}
+
+// Fields returns the top-level members set by patch, including the ones set to null.
+// It returns an error when patch is not a JSON object.
+func Fields(patch []byte) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("merge patch must be a JSON object")
+	}
+	return fields, nil
+}
+
+func merge(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = merge(t[k], v)
+	}
+	return t
+}
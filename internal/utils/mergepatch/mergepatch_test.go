@@ -0,0 +1,53 @@
+package mergepatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	// 取自 RFC 7386 附录 A
+	cases := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tc := range cases {
+		got, err := Apply([]byte(tc.doc), []byte(tc.patch))
+		require.NoError(t, err)
+		assert.JSONEq(t, tc.want, string(got), "doc %s patch %s", tc.doc, tc.patch)
+	}
+}
+
+func TestApply_InvalidPatch(t *testing.T) {
+	_, err := Apply([]byte(`{}`), []byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestFields(t *testing.T) {
+	fields, err := Fields([]byte(`{"a":1,"b":null}`))
+	require.NoError(t, err)
+	assert.Len(t, fields, 2)
+	assert.Contains(t, fields, "b")
+
+	_, err = Fields([]byte(`[1]`))
+	assert.Error(t, err)
+	_, err = Fields([]byte(`null`))
+	assert.Error(t, err)
+}
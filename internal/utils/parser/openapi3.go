@@ -6,6 +6,8 @@ import (
 	"io/ioutil"
 
 	"github.com/getkin/kin-openapi/openapi3"
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/utils/converter"
 )
 
 // OpenAPI3Parser 定义了 OpenAPI 3.0 解析器的接口实现
@@ -158,3 +160,8 @@ func (p *OpenAPI3Parser) Validate(doc *openapi3.T) error {
 	}
 	return nil
 }
+
+// ExtractAPIEndpoints 提取文档中的所有接口，包括标签、参数（含 path 级参数）、响应、请求头与请求体
+func (p *OpenAPI3Parser) ExtractAPIEndpoints(doc *openapi3.T) []model.APIEndpoint {
+	return converter.NewOpenAPI3Converter().ConvertToAPIEndpoint(doc)
+}
@@ -0,0 +1,454 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// downgradedOpenAPIVersion 为 3.1 文档降级后声明的版本
+const downgradedOpenAPIVersion = "3.0.3"
+
+// unsupportedSchemaKeywords 为 3.0 Schema 无法表达的 JSON Schema 2020-12 关键字
+// 降级时改名为 x-jsonschema-* 扩展保留，不参与校验
+var unsupportedSchemaKeywords = []string{
+	"$schema", "$id", "$anchor", "$dynamicAnchor", "$dynamicRef", "$comment", "$defs",
+	"if", "then", "else", "dependentRequired", "dependentSchemas",
+	"unevaluatedItems", "unevaluatedProperties", "propertyNames",
+	"contains", "minContains", "maxContains", "patternProperties", "prefixItems", "contentSchema",
+}
+
+// operationMethods 为 Path Item 中表示 Operation 的字段
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// OpenAPI31Parser 解析 OpenAPI 3.1 文档
+// kin-openapi 仅支持 3.0，因此先把文档降级为等价的 3.0 文档，再交给 OpenAPI3Parser 解析与校验
+type OpenAPI31Parser struct {
+	*OpenAPI3Parser
+}
+
+// NewOpenAPI31Parser 创建一个新的 OpenAPI 3.1 解析器
+func NewOpenAPI31Parser() Parser[*openapi3.T] {
+	return &OpenAPI31Parser{OpenAPI3Parser: &OpenAPI3Parser{}}
+}
+
+// Parse 解析 OpenAPI 3.1 文档
+func (p *OpenAPI31Parser) Parse(path string) (*openapi3.T, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read swagger file: %v", err)
+	}
+	return p.ParseFromData(data)
+}
+
+// ParseFromData 通过字节数据解析 OpenAPI 3.1 文档，支持 JSON 与 YAML
+func (p *OpenAPI31Parser) ParseFromData(data []byte) (*openapi3.T, error) {
+	spec, err := DetectVersion(data)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(spec.JSON, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse swagger data: %v", err)
+	}
+	if err := DowngradeOpenAPI31(doc); err != nil {
+		return nil, err
+	}
+	downgraded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to downgrade openapi 3.1 document: %v", err)
+	}
+	return p.OpenAPI3Parser.ParseFromData(downgraded)
+}
+
+// DowngradeOpenAPI31 将解码后的 OpenAPI 3.1 文档原地改写为 OpenAPI 3.0 文档
+// 可以等价表达的结构会被转换（type 数组、const、数值形式的 exclusiveMinimum 等），
+// 无法表达的结构改名为 x- 扩展保留（webhooks 等）；$defs 提升到 components.schemas 并改写引用
+func DowngradeOpenAPI31(doc map[string]interface{}) error {
+	if err := hoistDefs(doc); err != nil {
+		return err
+	}
+	if v, ok := doc["openapi"].(string); !ok || strings.HasPrefix(v, "3.1") {
+		doc["openapi"] = downgradedOpenAPIVersion
+	}
+	renameKey(doc, "webhooks", "x-webhooks")
+	renameKey(doc, "jsonSchemaDialect", "x-jsonSchemaDialect")
+	if _, ok := doc["paths"]; !ok {
+		doc["paths"] = map[string]interface{}{}
+	}
+
+	if info, ok := doc["info"].(map[string]interface{}); ok {
+		renameKey(info, "summary", "x-summary")
+		if license, ok := info["license"].(map[string]interface{}); ok {
+			renameKey(license, "identifier", "x-identifier")
+		}
+	}
+
+	// 3.1 中 responses 可省略，3.0 要求必填
+	if paths, ok := doc["paths"].(map[string]interface{}); ok {
+		for _, item := range paths {
+			pathItem, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, method := range operationMethods {
+				op, ok := pathItem[method].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if _, ok := op["responses"]; !ok {
+					op["responses"] = map[string]interface{}{"default": map[string]interface{}{"description": ""}}
+				}
+			}
+		}
+	}
+
+	mutualTLS := make(map[string]bool)
+	if components, ok := doc["components"].(map[string]interface{}); ok {
+		renameKey(components, "pathItems", "x-pathItems")
+		if schemes, ok := components["securitySchemes"].(map[string]interface{}); ok {
+			for name, v := range schemes {
+				if scheme, ok := v.(map[string]interface{}); ok && scheme["type"] == "mutualTLS" {
+					mutualTLS[name] = true
+					delete(schemes, name)
+				}
+			}
+		}
+		if schemas, ok := components["schemas"].(map[string]interface{}); ok {
+			for name, schema := range schemas {
+				schemas[name] = downgradeSchema(schema)
+			}
+		}
+	}
+	downgradeNode(doc, mutualTLS)
+	return nil
+}
+
+// defsHolder 记录一个包含 $defs 的对象及其 JSON Pointer
+type defsHolder struct {
+	pointer string
+	node    map[string]interface{}
+}
+
+// hoistDefs 将文档中所有 $defs 下的 Schema 提升到 components.schemas，并把指向它们的本地引用改写为新位置
+// 3.0 没有 $defs，kin-openapi 也无法解析指向 $defs 内部的引用；无法改写的本地 $defs 引用返回错误
+func hoistDefs(doc map[string]interface{}) error {
+	var holders []defsHolder
+	collectDefs(doc, "#", false, &holders)
+	if len(holders) == 0 {
+		return checkDefsRefs(doc)
+	}
+
+	components, ok := doc["components"].(map[string]interface{})
+	if !ok {
+		components = map[string]interface{}{}
+		doc["components"] = components
+	}
+	schemas, ok := components["schemas"].(map[string]interface{})
+	if !ok {
+		schemas = map[string]interface{}{}
+		components["schemas"] = schemas
+	}
+
+	moved := make(map[string]string)
+	for _, holder := range holders {
+		defs := holder.node["$defs"].(map[string]interface{})
+		names := make([]string, 0, len(defs))
+		for name := range defs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			target := name
+			for i := 2; schemas[target] != nil; i++ {
+				target = fmt.Sprintf("%s_%d", name, i)
+			}
+			schemas[target] = defs[name]
+			moved[holder.pointer+"/$defs/"+escapePointer(name)] = "#/components/schemas/" + escapePointer(target)
+		}
+	}
+	// 嵌套的 $defs 随外层一起移动，统一在改写完成后删除
+	for _, holder := range holders {
+		delete(holder.node, "$defs")
+	}
+
+	pointers := make([]string, 0, len(moved))
+	for pointer := range moved {
+		pointers = append(pointers, pointer)
+	}
+	// 优先匹配最长的指针，使嵌套 $defs 的引用指向各自的新位置
+	sort.Slice(pointers, func(i, j int) bool { return len(pointers[i]) > len(pointers[j]) })
+	rewriteRefs(doc, func(ref string) string {
+		for _, pointer := range pointers {
+			if ref == pointer || strings.HasPrefix(ref, pointer+"/") {
+				return moved[pointer] + strings.TrimPrefix(ref, pointer)
+			}
+		}
+		return ref
+	})
+	return checkDefsRefs(doc)
+}
+
+// collectDefs 按 JSON Pointer 收集包含 $defs 的对象，跳过示例与扩展等不是规范对象的位置
+// named 表示 node 的键是属性名等名称，其中的 "$defs" 不是关键字
+func collectDefs(node interface{}, pointer string, named bool, holders *[]defsHolder) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if defs, ok := n["$defs"].(map[string]interface{}); ok && !named && len(defs) > 0 {
+			*holders = append(*holders, defsHolder{pointer: pointer, node: n})
+		}
+		for k, v := range n {
+			if !named && (k == "example" || k == "examples" || k == "enum" || k == "const" || k == "default" || strings.HasPrefix(k, "x-")) {
+				continue
+			}
+			collectDefs(v, pointer+"/"+escapePointer(k), !named && (k == "properties" || k == "patternProperties" || k == "$defs"), holders)
+		}
+	case []interface{}:
+		for i, item := range n {
+			collectDefs(item, fmt.Sprintf("%s/%d", pointer, i), false, holders)
+		}
+	}
+}
+
+// rewriteRefs 以 rewrite 改写文档中所有字符串形式的 $ref
+func rewriteRefs(node interface{}, rewrite func(string) string) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := n["$ref"].(string); ok {
+			n["$ref"] = rewrite(ref)
+		}
+		for _, v := range n {
+			rewriteRefs(v, rewrite)
+		}
+	case []interface{}:
+		for _, item := range n {
+			rewriteRefs(item, rewrite)
+		}
+	}
+}
+
+// checkDefsRefs 检查是否还有指向 $defs 的本地引用，这类引用在 3.0 文档中无法解析
+func checkDefsRefs(doc map[string]interface{}) error {
+	var unresolved string
+	rewriteRefs(doc, func(ref string) string {
+		if unresolved == "" && strings.HasPrefix(ref, "#") && strings.Contains(ref, "/$defs/") {
+			unresolved = ref
+		}
+		return ref
+	})
+	if unresolved != "" {
+		return fmt.Errorf("unsupported openapi 3.1 reference %q: the referenced $defs schema does not exist", unresolved)
+	}
+	return nil
+}
+
+// escapePointer 按 RFC 6901 转义 JSON Pointer 中的单个片段
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// downgradeNode 遍历文档中的非 Schema 对象，转换其中的 Schema、引用与安全要求
+func downgradeNode(node interface{}, mutualTLS map[string]bool) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if _, ok := n["$ref"]; ok {
+			// 3.0 的引用对象不允许 summary / description 等兄弟字段
+			for k := range n {
+				if k != "$ref" {
+					delete(n, k)
+				}
+			}
+			return
+		}
+		if security, ok := n["security"].([]interface{}); ok && len(mutualTLS) > 0 {
+			n["security"] = removeSecurityRequirements(security, mutualTLS)
+		}
+		for k, v := range n {
+			switch {
+			case k == "schema":
+				n[k] = downgradeSchema(v)
+			case k == "schemas", k == "example", k == "examples", strings.HasPrefix(k, "x-"):
+				// components.schemas 已单独处理，示例与扩展的内容不是规范对象
+			default:
+				downgradeNode(v, mutualTLS)
+			}
+		}
+	case []interface{}:
+		for _, item := range n {
+			downgradeNode(item, mutualTLS)
+		}
+	}
+}
+
+// removeSecurityRequirements 移除引用 mutualTLS 方案的安全要求
+func removeSecurityRequirements(security []interface{}, mutualTLS map[string]bool) []interface{} {
+	kept := make([]interface{}, 0, len(security))
+	for _, item := range security {
+		requirement, ok := item.(map[string]interface{})
+		if !ok {
+			kept = append(kept, item)
+			continue
+		}
+		removed := false
+		for name := range requirement {
+			if mutualTLS[name] {
+				delete(requirement, name)
+				removed = true
+			}
+		}
+		// 仅包含 mutualTLS 的要求整体移除，避免变成表示可匿名访问的空要求
+		if removed && len(requirement) == 0 {
+			continue
+		}
+		kept = append(kept, requirement)
+	}
+	return kept
+}
+
+// downgradeSchema 将 JSON Schema 2020-12 风格的 Schema 转换为 3.0 Schema
+func downgradeSchema(v interface{}) interface{} {
+	s, ok := v.(map[string]interface{})
+	if !ok {
+		// 3.1 允许布尔 Schema，3.0 中以空 Schema 表示 true
+		if b, isBool := v.(bool); isBool && b {
+			return map[string]interface{}{}
+		}
+		return v
+	}
+
+	// $ref 的兄弟字段在 3.0 中会被忽略，改写为 allOf 以保留 description 等信息
+	if ref, ok := s["$ref"]; ok && len(s) > 1 {
+		delete(s, "$ref")
+		allOf, _ := s["allOf"].([]interface{})
+		s["allOf"] = append([]interface{}{map[string]interface{}{"$ref": ref}}, allOf...)
+	}
+
+	downgradeType(s)
+	if c, ok := s["const"]; ok {
+		if _, hasEnum := s["enum"]; !hasEnum {
+			s["enum"] = []interface{}{c}
+		}
+		delete(s, "const")
+	}
+	for exclusive, bound := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+		if n, ok := s[exclusive].(float64); ok {
+			s[bound] = n
+			s[exclusive] = true
+		}
+	}
+	if examples, ok := s["examples"].([]interface{}); ok {
+		if _, hasExample := s["example"]; !hasExample && len(examples) > 0 {
+			s["example"] = examples[0]
+		}
+		delete(s, "examples")
+	}
+	if encoding, ok := s["contentEncoding"].(string); ok {
+		if _, hasFormat := s["format"]; !hasFormat && strings.EqualFold(encoding, "base64") {
+			s["format"] = "byte"
+		}
+		delete(s, "contentEncoding")
+	}
+	if _, ok := s["contentMediaType"]; ok {
+		if _, hasFormat := s["format"]; !hasFormat && s["type"] == "string" {
+			s["format"] = "binary"
+		}
+		delete(s, "contentMediaType")
+	}
+	if prefixItems, ok := s["prefixItems"].([]interface{}); ok && len(prefixItems) > 0 {
+		if _, hasItems := s["items"]; !hasItems {
+			if len(prefixItems) == 1 {
+				s["items"] = prefixItems[0]
+			} else {
+				s["items"] = map[string]interface{}{"anyOf": prefixItems}
+			}
+		}
+	}
+	if items, ok := s["items"].(bool); ok {
+		if items {
+			s["items"] = map[string]interface{}{}
+		} else {
+			delete(s, "items")
+		}
+	}
+	if _, hasItems := s["items"]; !hasItems && s["type"] == "array" {
+		s["items"] = map[string]interface{}{}
+	}
+	for _, k := range unsupportedSchemaKeywords {
+		renameKey(s, k, "x-jsonschema-"+strings.TrimPrefix(k, "$"))
+	}
+
+	// 递归处理子 Schema
+	if props, ok := s["properties"].(map[string]interface{}); ok {
+		for name, prop := range props {
+			props[name] = downgradeSchema(prop)
+		}
+	}
+	for _, k := range []string{"items", "additionalProperties", "not"} {
+		if sub, ok := s[k].(map[string]interface{}); ok {
+			s[k] = downgradeSchema(sub)
+		}
+	}
+	for _, k := range []string{"allOf", "oneOf", "anyOf"} {
+		if subs, ok := s[k].([]interface{}); ok {
+			for i, sub := range subs {
+				subs[i] = downgradeSchema(sub)
+			}
+		}
+	}
+	return s
+}
+
+// downgradeType 将 type 数组转换为单一类型加 nullable，多个非 null 类型转换为 anyOf
+func downgradeType(s map[string]interface{}) {
+	var types []interface{}
+	switch t := s["type"].(type) {
+	case string:
+		if t != "null" {
+			return
+		}
+		types = []interface{}{t}
+	case []interface{}:
+		types = t
+	default:
+		return
+	}
+
+	var nonNull []interface{}
+	for _, t := range types {
+		if t == "null" {
+			s["nullable"] = true
+			continue
+		}
+		nonNull = append(nonNull, t)
+	}
+	switch len(nonNull) {
+	case 0:
+		delete(s, "type")
+	case 1:
+		s["type"] = nonNull[0]
+	default:
+		delete(s, "type")
+		variants := make([]interface{}, 0, len(nonNull))
+		for _, t := range nonNull {
+			variants = append(variants, map[string]interface{}{"type": t})
+		}
+		if _, hasAnyOf := s["anyOf"]; hasAnyOf {
+			allOf, _ := s["allOf"].([]interface{})
+			s["allOf"] = append(allOf, map[string]interface{}{"anyOf": variants})
+		} else {
+			s["anyOf"] = variants
+		}
+	}
+}
+
+// renameKey 将 m 中的 from 字段改名为 to
+func renameKey(m map[string]interface{}, from, to string) {
+	if v, ok := m[from]; ok {
+		m[to] = v
+		delete(m, from)
+	}
+}
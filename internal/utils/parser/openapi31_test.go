@@ -0,0 +1,210 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstoreOpenAPI31 = `
+openapi: 3.1.0
+jsonSchemaDialect: https://json-schema.org/draft/2020-12/schema
+info:
+  title: Pets
+  summary: Pet store
+  version: "1.0"
+  license:
+    name: MIT
+    identifier: MIT
+paths:
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/PetId'
+        description: overridden description
+    get:
+      operationId: getPet
+      security:
+        - mtls: []
+        - apiKey: []
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+                description: the pet
+    delete:
+      operationId: deletePet
+webhooks:
+  newPet:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+components:
+  securitySchemes:
+    mtls:
+      type: mutualTLS
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+  parameters:
+    PetId:
+      name: petId
+      in: path
+      required: true
+      schema:
+        type: integer
+        exclusiveMinimum: 0
+  schemas:
+    Pet:
+      $schema: https://json-schema.org/draft/2020-12/schema
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          examples: [Rex]
+        tag:
+          type: [string, "null"]
+        kind:
+          const: dog
+        weight:
+          type: [integer, number]
+        photo:
+          type: string
+          contentEncoding: base64
+        tags:
+          type: array
+          prefixItems:
+            - type: string
+        meta:
+          $defs:
+            id:
+              type: string
+          type: object
+`
+
+func TestOpenAPI31Parser_ParseFromData(t *testing.T) {
+	doc, err := NewOpenAPI31Parser().ParseFromData([]byte(petstoreOpenAPI31))
+	require.NoError(t, err)
+
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.Equal(t, "Pet store", doc.Info.Extensions["x-summary"])
+	assert.Contains(t, doc.Extensions, "x-webhooks")
+	assert.NotContains(t, doc.Components.SecuritySchemes, "mtls")
+
+	pet := doc.Components.Schemas["Pet"].Value
+	props := pet.Properties
+	assert.Equal(t, "Rex", props["name"].Value.Example)
+	assert.True(t, props["tag"].Value.Type.Is(openapi3.TypeString))
+	assert.True(t, props["tag"].Value.Nullable)
+	assert.Equal(t, []interface{}{"dog"}, props["kind"].Value.Enum)
+	require.Len(t, props["weight"].Value.AnyOf, 2)
+	assert.Equal(t, "byte", props["photo"].Value.Format)
+	assert.True(t, props["tags"].Value.Items.Value.Type.Is(openapi3.TypeString))
+	// $defs 提升到 components.schemas
+	assert.NotContains(t, props["meta"].Value.Extensions, "x-jsonschema-defs")
+	assert.True(t, doc.Components.Schemas["id"].Value.Type.Is(openapi3.TypeString))
+
+	petID := doc.Components.Parameters["PetId"].Value.Schema.Value
+	assert.Equal(t, float64(0), *petID.Min)
+	assert.True(t, petID.ExclusiveMin)
+
+	get := doc.Paths.Find("/pets/{petId}").Get
+	// 仅包含 mutualTLS 的安全要求被整体移除
+	assert.Len(t, *get.Security, 1)
+	// $ref 的兄弟字段通过 allOf 保留
+	schema := get.Responses.Status(200).Value.Content["application/json"].Schema.Value
+	assert.Equal(t, "the pet", schema.Description)
+	require.Len(t, schema.AllOf, 1)
+	assert.Equal(t, "#/components/schemas/Pet", schema.AllOf[0].Ref)
+
+	// 3.1 中可省略的 responses 被补齐
+	assert.NotNil(t, doc.Paths.Find("/pets/{petId}").Delete.Responses.Default())
+}
+
+func TestOpenAPI31Parser_UsesDetectedVersion(t *testing.T) {
+	spec, err := DetectVersion([]byte(petstoreOpenAPI31))
+	require.NoError(t, err)
+	assert.True(t, spec.IsOpenAPI31())
+
+	spec, err = DetectVersion([]byte(`{"openapi": "3.0.3"}`))
+	require.NoError(t, err)
+	assert.False(t, spec.IsOpenAPI31())
+}
+
+func TestOpenAPI31Parser_HoistsDefs(t *testing.T) {
+	doc, err := NewOpenAPI31Parser().ParseFromData([]byte(`
+openapi: 3.1.0
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    Tag:
+      type: integer
+    Pet:
+      type: object
+      properties:
+        $defs:
+          type: string
+        owner:
+          $ref: '#/components/schemas/Pet/$defs/Owner'
+        tag:
+          $ref: '#/components/schemas/Pet/$defs/Tag'
+        address:
+          $ref: '#/components/schemas/Pet/$defs/Owner/$defs/Address'
+      $defs:
+        Tag:
+          type: string
+        Owner:
+          type: object
+          properties:
+            name:
+              type: string
+            address:
+              $ref: '#/components/schemas/Pet/$defs/Owner/$defs/Address'
+          $defs:
+            Address:
+              type: string
+`))
+	require.NoError(t, err)
+
+	props := doc.Components.Schemas["Pet"].Value.Properties
+	// 名为 $defs 的属性不是关键字，保持不变
+	assert.True(t, props["$defs"].Value.Type.Is(openapi3.TypeString))
+	assert.Equal(t, "#/components/schemas/Owner", props["owner"].Ref)
+	// 与已有 Schema 重名时追加序号
+	assert.Equal(t, "#/components/schemas/Tag_2", props["tag"].Ref)
+	assert.True(t, props["tag"].Value.Type.Is(openapi3.TypeString))
+	assert.Equal(t, "#/components/schemas/Address", props["address"].Ref)
+	assert.Equal(t, "#/components/schemas/Address", props["owner"].Value.Properties["address"].Ref)
+}
+
+func TestOpenAPI31Parser_UnresolvedDefsRef(t *testing.T) {
+	_, err := NewOpenAPI31Parser().ParseFromData([]byte(`
+openapi: 3.1.0
+info: {title: Pets, version: "1.0"}
+paths: {}
+components:
+  schemas:
+    Pet:
+      $ref: '#/$defs/Missing'
+`))
+	assert.ErrorContains(t, err, `unsupported openapi 3.1 reference "#/$defs/Missing"`)
+}
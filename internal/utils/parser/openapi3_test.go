@@ -1,7 +1,12 @@
 package parser
 
 import (
+	"encoding/json"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mcp-manager/internal/model"
 )
 
 func TestSwaggerParser(t *testing.T) {
@@ -9,7 +14,7 @@ func TestSwaggerParser(t *testing.T) {
 	p := NewOpenAPI3Parser()
 
 	// 测试解析
-	doc, err := p.Parse("/Users/wanghao/Desktop/project/go/mcp-manager/example/test.yaml")
+	doc, err := p.Parse("../../../example/test.yaml")
 	if err != nil {
 		t.Fatalf("Failed to parse swagger file: %v", err)
 	}
@@ -50,3 +55,102 @@ func TestSwaggerParser(t *testing.T) {
 	t.Logf("API Version: %s", version)
 	t.Logf("Number of defined paths: %d", pathCount)
 }
+
+// findEndpoint 按方法与路径查找接口
+func findEndpoint(t *testing.T, endpoints []model.APIEndpoint, method, path string) model.APIEndpoint {
+	t.Helper()
+	for _, e := range endpoints {
+		if e.Method == method && e.Path == path {
+			return e
+		}
+	}
+	t.Fatalf("endpoint %s %s not found", method, path)
+	return model.APIEndpoint{}
+}
+
+// findParameter 按名称查找接口参数
+func findParameter(t *testing.T, endpoint model.APIEndpoint, name string) model.APIParameter {
+	t.Helper()
+	for _, p := range endpoint.Parameters {
+		if p.Name == name {
+			return p
+		}
+	}
+	t.Fatalf("parameter %s not found in %s %s", name, endpoint.Method, endpoint.Path)
+	return model.APIParameter{}
+}
+
+// assertExtractedEndpoints 校验 test-openapi3.json 与 test-swagger.json 共有的接口信息
+func assertExtractedEndpoints(t *testing.T, endpoints []model.APIEndpoint, bodyName string) {
+	require.Len(t, endpoints, 3)
+	// 按路径与方法排序
+	assert.Equal(t, "/users", endpoints[0].Path)
+	assert.Equal(t, "GET", endpoints[0].Method)
+	assert.Equal(t, "/users/{id}", endpoints[2].Path)
+
+	list := findEndpoint(t, endpoints, "GET", "/users")
+	assert.Equal(t, "getUsers", list.OperationID)
+	assert.Equal(t, "Users", list.Tags)
+	limit := findParameter(t, list, "limit")
+	assert.Equal(t, "query", limit.In)
+	assert.Equal(t, "integer", limit.Type)
+	assert.Equal(t, "20", limit.Value)
+	assert.Equal(t, "application/json", list.Headers["Accept"])
+
+	var responses map[string]struct {
+		Description string                            `json:"description"`
+		Headers     map[string]map[string]interface{} `json:"headers"`
+		Content     map[string]map[string]interface{} `json:"content"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(list.Responses), &responses))
+	require.Contains(t, responses, "200")
+	assert.Equal(t, "Success", responses["200"].Description)
+	assert.Equal(t, "integer", responses["200"].Headers["X-Total-Count"]["type"])
+	assert.Equal(t, "array", responses["200"].Content["application/json"]["type"])
+
+	create := findEndpoint(t, endpoints, "POST", "/users")
+	assert.Equal(t, "application/json", create.Headers["Content-Type"])
+	body := findParameter(t, create, bodyName)
+	assert.Equal(t, "body", body.In)
+	assert.True(t, body.Required)
+	assert.Equal(t, "object", body.Type)
+	var sample map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(create.Body), &sample))
+	assert.Contains(t, sample, "name")
+	assert.Contains(t, sample, "email")
+
+	get := findEndpoint(t, endpoints, "GET", "/users/{id}")
+	assert.Equal(t, "Users,Admin", get.Tags)
+	// path 级参数合并到每个操作
+	id := findParameter(t, get, "id")
+	assert.Equal(t, "path", id.In)
+	assert.True(t, id.Required)
+	assert.Equal(t, "integer", id.Type)
+	requestID := findParameter(t, get, "X-Request-ID")
+	assert.Equal(t, "header", requestID.In)
+	assert.Equal(t, "string", requestID.Type)
+	require.NoError(t, json.Unmarshal([]byte(get.Responses), &responses))
+	assert.Contains(t, responses, "404")
+}
+
+func TestOpenAPI3Parser_ExtractAPIEndpoints(t *testing.T) {
+	p := &OpenAPI3Parser{}
+	doc, err := p.Parse("../../../test-openapi3.json")
+	require.NoError(t, err)
+	require.NoError(t, p.Validate(doc))
+
+	endpoints := p.ExtractAPIEndpoints(doc)
+	assertExtractedEndpoints(t, endpoints, "body")
+	get := findEndpoint(t, endpoints, "GET", "/users/{id}")
+	assert.Equal(t, "req-1", findParameter(t, get, "X-Request-ID").Value)
+}
+
+func TestSwaggerParser_ParsesBothVersions(t *testing.T) {
+	p := NewSwaggerParser()
+	for _, path := range []string{"../../../test-openapi3.json", "../../../test-swagger.json"} {
+		doc, err := p.Parse(path)
+		require.NoError(t, err, path)
+		require.NoError(t, p.Validate(doc), path)
+		assert.Len(t, p.ExtractAPIEndpoints(doc), 3, path)
+	}
+}
@@ -4,6 +4,7 @@ package parser
 import (
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi3"
+	"mcp-manager/internal/model"
 )
 
 // Parser defines the interface for parsing OpenAPI documents.
@@ -15,3 +16,10 @@ type Parser[T interface{ *openapi3.T } | interface{ *openapi2.T }] interface {
 	// Validate validates the structured representation of the path.
 	Validate(doc T) error
 }
+
+// SwaggerParserWithExtract is a Parser that can also extract the API endpoints of a parsed document.
+type SwaggerParserWithExtract[T interface{ *openapi3.T } | interface{ *openapi2.T }] interface {
+	Parser[T]
+	// ExtractAPIEndpoints extracts the endpoints of the document, ordered by path and method.
+	ExtractAPIEndpoints(doc T) []model.APIEndpoint
+}
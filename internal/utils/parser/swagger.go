@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"mcp-manager/internal/model"
+)
+
+// SwaggerParser 解析任意受支持版本的文档，统一返回 OpenAPI 3 结构
+// Swagger 2.0 文档通过 openapi2conv 转换，OpenAPI 3.1 文档降级为 3.0
+type SwaggerParser struct {
+	swagger2  *Swagger2Parser
+	openapi3  *OpenAPI3Parser
+	openapi31 *OpenAPI31Parser
+}
+
+// NewSwaggerParser 创建一个根据文档声明的版本自动选择解析方式的解析器
+func NewSwaggerParser() SwaggerParserWithExtract[*openapi3.T] {
+	openapi3Parser := &OpenAPI3Parser{}
+	return &SwaggerParser{
+		swagger2:  &Swagger2Parser{},
+		openapi3:  openapi3Parser,
+		openapi31: &OpenAPI31Parser{OpenAPI3Parser: openapi3Parser},
+	}
+}
+
+// Parse 解析 Swagger / OpenAPI 文档
+func (p *SwaggerParser) Parse(path string) (*openapi3.T, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read swagger file: %v", err)
+	}
+	return p.ParseFromData(data)
+}
+
+// ParseFromData 通过字节数据解析 Swagger / OpenAPI 文档
+func (p *SwaggerParser) ParseFromData(data []byte) (*openapi3.T, error) {
+	spec, err := DetectVersion(data)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case spec.Format == model.SpecFormatSwagger2:
+		doc, err := p.swagger2.ParseFromData(spec.JSON)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.swagger2.Validate(doc); err != nil {
+			return nil, err
+		}
+		v3Doc, err := openapi2conv.ToV3(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert swagger2 document: %v", err)
+		}
+		return v3Doc, nil
+	case spec.IsOpenAPI31():
+		return p.openapi31.ParseFromData(spec.JSON)
+	default:
+		return p.openapi3.ParseFromData(spec.JSON)
+	}
+}
+
+// Validate 验证解析后的 OpenAPI 3 文档
+func (p *SwaggerParser) Validate(doc *openapi3.T) error {
+	return p.openapi3.Validate(doc)
+}
+
+// ExtractAPIEndpoints 提取文档中的所有接口
+func (p *SwaggerParser) ExtractAPIEndpoints(doc *openapi3.T) []model.APIEndpoint {
+	return p.openapi3.ExtractAPIEndpoints(doc)
+}
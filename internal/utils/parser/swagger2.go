@@ -5,8 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/getkin/kin-openapi/openapi2"
-	"gopkg.in/yaml.v3"
 	"io/ioutil"
+	"mcp-manager/internal/model"
+	"mcp-manager/internal/utils/converter"
 )
 
 // Swagger2Parser 定义了 Swagger 2.0 解析器的接口实现
@@ -33,9 +34,11 @@ func (p *Swagger2Parser) ParseFromData(data []byte) (*openapi2.T, error) {
 	if err := json.Unmarshal(data, &doc); err == nil {
 		return &doc, nil
 	}
-	// 再尝试 YAML
-	if err := yaml.Unmarshal(data, &doc); err == nil {
-		return &doc, nil
+	// 再尝试 YAML，先转换为 JSON 以复用 openapi2.T 的 JSON 解码
+	if spec, err := DetectVersion(data); err == nil {
+		if err := json.Unmarshal(spec.JSON, &doc); err == nil {
+			return &doc, nil
+		}
 	}
 	return nil, fmt.Errorf("failed to parse swagger2 data as JSON or YAML")
 }
@@ -68,3 +71,8 @@ func (p *Swagger2Parser) Validate(doc *openapi2.T) error {
 	}
 	return nil
 }
+
+// ExtractAPIEndpoints 提取文档中的所有接口，包括标签、参数（含 path 级参数）、响应、请求头与请求体
+func (p *Swagger2Parser) ExtractAPIEndpoints(doc *openapi2.T) []model.APIEndpoint {
+	return converter.NewSwagger2Converter().ConvertToAPIEndpoint(doc)
+}
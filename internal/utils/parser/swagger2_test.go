@@ -0,0 +1,17 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwagger2Parser_ExtractAPIEndpoints(t *testing.T) {
+	p := &Swagger2Parser{}
+	doc, err := p.Parse("../../../test-swagger.json")
+	require.NoError(t, err)
+	require.NoError(t, p.Validate(doc))
+
+	// body 参数保留 Swagger 2.0 中声明的名称
+	assertExtractedEndpoints(t, p.ExtractAPIEndpoints(doc), "user")
+}
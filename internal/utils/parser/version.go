@@ -0,0 +1,209 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"mcp-manager/internal/model"
+)
+
+var (
+	// ErrEmptyDocument 文档内容为空
+	ErrEmptyDocument = errors.New("swagger document is empty")
+	// ErrMalformedDocument 文档既不是合法的 JSON 也不是合法的 YAML 对象
+	ErrMalformedDocument = errors.New("swagger document is neither a JSON nor a YAML object")
+	// ErrMissingVersion 文档顶层没有 openapi 或 swagger 字段
+	ErrMissingVersion = errors.New("swagger document declares neither an openapi nor a swagger version")
+)
+
+// UnsupportedVersionError 文档声明的规范版本不受支持
+type UnsupportedVersionError struct {
+	Field   string // 声明版本的顶层字段：openapi、swagger 或 swaggerVersion
+	Version string // 声明的版本号
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("unsupported %s version %q, supported versions are swagger 2.0, openapi 3.0.x and 3.1.x", e.Field, e.Version)
+}
+
+// SpecVersion 描述文档声明的规范版本
+type SpecVersion struct {
+	Format  string // model.SpecFormatSwagger2 或 model.SpecFormatOpenAPI3
+	Version string // 声明的版本号，如 2.0、3.0.3、3.1.0
+	// JSON 为文档的 JSON 编码，YAML 文档会被转换，后续解析无需再判断编码
+	JSON []byte
+}
+
+// IsOpenAPI31 判断文档是否为 OpenAPI 3.1，需要使用 OpenAPI31Parser 解析
+func (v *SpecVersion) IsOpenAPI31() bool {
+	return v.Format == model.SpecFormatOpenAPI3 && isOpenAPIVersion(v.Version, "3.1")
+}
+
+// DetectVersion 解码一次 JSON 或 YAML 文档，根据顶层的 openapi / swagger 字段判断规范版本
+// 不支持的版本返回 *UnsupportedVersionError
+func DetectVersion(data []byte) (*SpecVersion, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, ErrEmptyDocument
+	}
+
+	var (
+		fields   map[string]string
+		jsonData []byte
+		err      error
+	)
+	if data[0] == '{' {
+		fields, jsonData, err = jsonVersionFields(data)
+	}
+	if data[0] != '{' || err != nil {
+		// JSON 解析失败时按 YAML 再试一次，YAML 的 flow 风格同样以 { 开头
+		fields, jsonData, err = yamlVersionFields(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case fields["openapi"] != "":
+		v := fields["openapi"]
+		if !isOpenAPIVersion(v, "3.0") && !isOpenAPIVersion(v, "3.1") {
+			return nil, &UnsupportedVersionError{Field: "openapi", Version: v}
+		}
+		return &SpecVersion{Format: model.SpecFormatOpenAPI3, Version: v, JSON: jsonData}, nil
+	case fields["swagger"] != "":
+		v := fields["swagger"]
+		if v != "2.0" && v != "2" {
+			return nil, &UnsupportedVersionError{Field: "swagger", Version: v}
+		}
+		return &SpecVersion{Format: model.SpecFormatSwagger2, Version: "2.0", JSON: jsonData}, nil
+	case fields["swaggerVersion"] != "":
+		// Swagger 1.x 使用 swaggerVersion 字段声明版本
+		return nil, &UnsupportedVersionError{Field: "swaggerVersion", Version: fields["swaggerVersion"]}
+	}
+	return nil, ErrMissingVersion
+}
+
+// isOpenAPIVersion 判断 v 是否为 minor 版本（如 3.1）下的版本号
+func isOpenAPIVersion(v, minor string) bool {
+	return v == minor || strings.HasPrefix(v, minor+".")
+}
+
+// versionFields 为声明规范版本的顶层字段
+var versionFields = []string{"openapi", "swagger", "swaggerVersion"}
+
+// jsonVersionFields 读取 JSON 文档顶层的版本字段
+// 数字形式的版本号（如 "swagger": 2.0）保留原始写法，并在返回的文档中改写为字符串
+func jsonVersionFields(data []byte) (map[string]string, []byte, error) {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return nil, nil, ErrMalformedDocument
+	}
+	fields := make(map[string]string)
+	rewritten := false
+	for _, key := range versionFields {
+		raw, ok := top[key]
+		if !ok {
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			s = string(raw)
+			top[key], _ = json.Marshal(s)
+			rewritten = true
+		}
+		fields[key] = strings.TrimSpace(s)
+	}
+	if rewritten {
+		var err error
+		if data, err = json.Marshal(top); err != nil {
+			return nil, nil, ErrMalformedDocument
+		}
+	}
+	return fields, data, nil
+}
+
+// yamlVersionFields 读取 YAML 文档顶层的版本字段，并把文档转换为 JSON
+func yamlVersionFields(data []byte) (map[string]string, []byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, ErrMalformedDocument
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, ErrMalformedDocument
+	}
+	top := root.Content[0]
+
+	fields := make(map[string]string)
+	for i := 0; i+1 < len(top.Content); i += 2 {
+		key, value := top.Content[i], top.Content[i+1]
+		for _, name := range versionFields {
+			if key.Value == name && value.Kind == yaml.ScalarNode {
+				fields[name] = strings.TrimSpace(value.Value)
+			}
+		}
+	}
+
+	v, err := yamlNodeValue(top)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 未加引号的版本号（如 swagger: 2.0）按原始文本保留为字符串
+	doc := v.(map[string]interface{})
+	for name, version := range fields {
+		doc[name] = version
+	}
+	jsonData, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert yaml to json: %v", err)
+	}
+	return fields, jsonData, nil
+}
+
+// yamlNodeValue 将 YAML 节点转换为可 JSON 编码的值
+// 映射的键一律转为字符串（如响应码 200），时间戳保持原始文本
+func yamlNodeValue(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlNodeValue(node.Content[0])
+	case yaml.AliasNode:
+		return yamlNodeValue(node.Alias)
+	case yaml.MappingNode:
+		m := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v, err := yamlNodeValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			m[node.Content[i].Value] = v
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		s := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			v, err := yamlNodeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
+		}
+		return s, nil
+	case yaml.ScalarNode:
+		if node.ShortTag() == "!!timestamp" {
+			return node.Value, nil
+		}
+		var v interface{}
+		if err := node.Decode(&v); err != nil {
+			return nil, ErrMalformedDocument
+		}
+		return v, nil
+	}
+	return nil, ErrMalformedDocument
+}
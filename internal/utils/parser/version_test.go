@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+
+	"mcp-manager/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectVersion(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		format  string
+		version string
+	}{
+		{"openapi3 json", `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}}`, model.SpecFormatOpenAPI3, "3.0.3"},
+		{"openapi3 yaml", "openapi: 3.0.1\ninfo:\n  title: t\n  version: '1'\n", model.SpecFormatOpenAPI3, "3.0.1"},
+		{"swagger2 json", `{"swagger": "2.0", "info": {"description": "moving to openapi 3.0 soon"}}`, model.SpecFormatSwagger2, "2.0"},
+		{"swagger2 yaml unquoted", "swagger: 2.0\ninfo:\n  title: openapi 3.1 notes\n", model.SpecFormatSwagger2, "2.0"},
+		{"swagger2 json number", `{"swagger": 2.0}`, model.SpecFormatSwagger2, "2.0"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			spec, err := DetectVersion([]byte(c.content))
+			require.NoError(t, err)
+			assert.Equal(t, c.format, spec.Format)
+			assert.Equal(t, c.version, spec.Version)
+			assert.True(t, json.Valid(spec.JSON))
+		})
+	}
+}
+
+func TestDetectVersion_YAMLToJSON(t *testing.T) {
+	spec, err := DetectVersion([]byte("swagger: 2.0\ninfo:\n  version: 2021-01-01\npaths:\n  /a:\n    get:\n      responses:\n        200:\n          description: OK\n"))
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(spec.JSON, &doc))
+	assert.Equal(t, "2.0", doc["swagger"])
+	assert.Equal(t, "2021-01-01", doc["info"].(map[string]interface{})["version"])
+	responses := doc["paths"].(map[string]interface{})["/a"].(map[string]interface{})["get"].(map[string]interface{})["responses"]
+	assert.Contains(t, responses, "200")
+}
+
+func TestDetectVersion_Errors(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		err     error
+	}{
+		{"empty", "  \n", ErrEmptyDocument},
+		{"scalar", "just some text", ErrMalformedDocument},
+		{"broken json", `{"openapi": `, ErrMalformedDocument},
+		{"no version", `{"info": {"title": "t"}}`, ErrMissingVersion},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := DetectVersion([]byte(c.content))
+			assert.ErrorIs(t, err, c.err)
+		})
+	}
+}
+
+func TestDetectVersion_Unsupported(t *testing.T) {
+	cases := []struct {
+		content string
+		field   string
+		version string
+	}{
+		{`{"swaggerVersion": "1.2", "apis": []}`, "swaggerVersion", "1.2"},
+		{`{"swagger": "1.2"}`, "swagger", "1.2"},
+		{"openapi: 4.0.0\n", "openapi", "4.0.0"},
+	}
+	for _, c := range cases {
+		_, err := DetectVersion([]byte(c.content))
+		var versionErr *UnsupportedVersionError
+		require.ErrorAs(t, err, &versionErr, c.content)
+		assert.Equal(t, c.field, versionErr.Field)
+		assert.Equal(t, c.version, versionErr.Version)
+	}
+}
@@ -7,13 +7,22 @@ package main
 // @Update  socketwang  2025/5/26 13:01
 
 import (
+	"context"
 	"fmt"
+	stdlog "log"
+	"mcp-manager/internal/dao"
+	"mcp-manager/internal/mcp"
+	"mcp-manager/internal/migrate"
 	"mcp-manager/internal/model"
 	"mcp-manager/internal/router"
 	"mcp-manager/pkg/config"
 	"mcp-manager/pkg/logger"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 
@@ -21,11 +30,25 @@ import (
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
+	gormlogger "gorm.io/gorm/logger"
 	_ "mcp-manager/docs"
 )
 
+const (
+	modeHTTP  = "http"  // 启动 HTTP 服务
+	modeStdio = "stdio" // 以子进程方式通过 stdin/stdout 提供 MCP 服务
+
+	cmdMCPStdio = "mcp-stdio" // 等价于 --mode=stdio 的子命令
+	cmdMigrate  = "migrate"   // 执行表结构迁移：migrate [up | down [n] | status]
+)
+
 var (
-	cfg = pflag.StringP("cfg", "c", "./cfg/cfg.yaml", "config file path.")
+	cfg      = pflag.StringP("cfg", "c", "./cfg/cfg.yaml", "config file path.")
+	mode     = pflag.String("mode", modeHTTP, "run mode: http or stdio.")
+	serverID = pflag.Uint("server", 0, "mcp server id served in stdio mode, 0 for the endpoints of all documents.")
+
+	// stdout 在 stdio 模式下仅用于输出协议数据
+	stdout = os.Stdout
 )
 
 func onCfgChg(e fsnotify.Event) {
@@ -38,17 +61,21 @@ func onCfgChg(e fsnotify.Event) {
 func init() {
 	pflag.Parse()
 
+	if runMode() == modeStdio {
+		keepStdoutClean()
+	}
+
 	// load configs
 	err := config.Init(*cfg, onCfgChg)
 	if err != nil {
-		fmt.Println("init config failed!")
+		fmt.Fprintln(os.Stderr, "init config failed!")
 		os.Exit(-2)
 	}
 
 	// init debug logger
 	err = logger.InitDebugLogger(config.LogLevel(), config.LogPath())
 	if err != nil {
-		fmt.Println("init debug logger failed!")
+		fmt.Fprintln(os.Stderr, "init debug logger failed!")
 		os.Exit(-3)
 	}
 
@@ -60,9 +87,53 @@ func init() {
 	}
 }
 
+// runMode 返回运行模式，mcp-stdio 子命令等价于 --mode=stdio
+func runMode() string {
+	if pflag.Arg(0) == cmdMCPStdio {
+		return modeStdio
+	}
+	return *mode
+}
+
+// keepStdoutClean 将标准输出保留给 MCP 协议数据，其余输出（gin、gorm 等）全部重定向到标准错误
+func keepStdoutClean() {
+	os.Stdout = os.Stderr
+	gin.DefaultWriter = os.Stderr
+	gin.DefaultErrorWriter = os.Stderr
+	gormlogger.Default = gormlogger.New(stdlog.New(os.Stderr, "\r\n", stdlog.LstdFlags), gormlogger.Config{
+		SlowThreshold: 200 * time.Millisecond,
+		LogLevel:      gormlogger.Warn,
+	})
+}
+
 func main() {
+	if pflag.Arg(0) == cmdMigrate {
+		runMigrate(pflag.Args()[1:])
+		return
+	}
+	if config.MigrateOnStartup() {
+		migrateOnStartup()
+	}
+	switch runMode() {
+	case modeHTTP:
+		serveHTTP()
+	case modeStdio:
+		serveStdio()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown mode: %s\n", *mode)
+		os.Exit(-1)
+	}
+}
+
+// serveHTTP 启动 HTTP 服务，服务退出后停止所有后台任务
+func serveHTTP() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	r := gin.Default()
-	router.RegisterRoutes(r)
+	for _, task := range router.RegisterRoutes(r) {
+		task.Start(ctx)
+	}
 
 	iDataServer := &http.Server{
 		Addr:    config.Addr(),
@@ -74,3 +145,94 @@ func main() {
 		fmt.Println(err)
 	}
 }
+
+// serveStdio 通过 stdin/stdout 以换行分隔的 JSON-RPC 提供 MCP 服务
+func serveStdio() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	factory := mcp.NewServerFactory(dao.NewMCPServerDAO(nil), dao.NewAPIEndpointDAO(nil), dao.NewSwaggerDocumentDAO(nil), dao.NewAuthProfileDAO(nil), dao.NewSecretDAO(nil))
+	server := factory.DocumentServer(0)
+	if *serverID != 0 {
+		var err error
+		if server, err = factory.ManagedServerByID(ctx, *serverID); err != nil {
+			log.Errorf("load mcp server %d failed: %v", *serverID, err)
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(-5)
+		}
+	}
+	log.Infof("mcp stdio server started, server: %d", *serverID)
+	if err := mcp.ServeStdio(ctx, server, os.Stdin, stdout); err != nil {
+		log.Errorf("mcp stdio server stopped: %v", err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(-5)
+	}
+}
+
+// migrateOnStartup 启动服务前执行未执行的迁移
+func migrateOnStartup() {
+	conn, err := model.GetMcpManagerDB()
+	if err == nil {
+		err = migrate.Run(context.Background(), conn)
+	}
+	if err != nil {
+		log.Errorf("migrate failed: %v", err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(-6)
+	}
+}
+
+// runMigrate 执行 migrate 子命令：up 执行所有未执行的迁移（默认），down [n] 回滚最近的 n 个迁移（默认 1），status 输出迁移状态
+func runMigrate(args []string) {
+	ctx := context.Background()
+	conn, err := model.GetMcpManagerDB()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(-6)
+	}
+	migrator, err := migrate.New(conn, migrate.Migrations())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(-6)
+	}
+
+	action := "up"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	var done []migrate.Migration
+	switch action {
+	case "up":
+		done, err = migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				fmt.Fprintf(os.Stderr, "invalid steps: %s\n", args[1])
+				os.Exit(-1)
+			}
+		}
+		done, err = migrator.Down(ctx, steps)
+	case "status":
+		var statuses []migrate.Status
+		if statuses, err = migrator.Status(ctx); err == nil {
+			for _, status := range statuses {
+				applied := "pending"
+				if status.AppliedAt != nil {
+					applied = status.AppliedAt.Format(time.RFC3339)
+				}
+				fmt.Printf("%04d  %-32s  %s\n", status.Version, status.Name, applied)
+			}
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate action: %s\n", action)
+		os.Exit(-1)
+	}
+	for _, migration := range done {
+		fmt.Printf("%s %04d %s\n", action, migration.Version, migration.Name)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(-6)
+	}
+}
@@ -11,12 +11,16 @@ import (
 // message: 提示信息
 // data: 返回数据
 // trace_id: 链路追踪ID
+// total: 分页查询时满足条件的总数
+// next_cursor: 游标分页时下一页的游标，没有下一页时省略
 
 type Response struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-	TraceID string      `json:"trace_id,omitempty"`
+	Code       int         `json:"code"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data,omitempty"`
+	Total      *int64      `json:"total,omitempty"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	TraceID    string      `json:"trace_id,omitempty"`
 }
 
 // getTraceIDFromCtx 从 ginCtxMap 获取 trace_id
@@ -44,6 +48,19 @@ func Success(c *gin.Context, data interface{}) {
 	})
 }
 
+// SuccessPage 返回分页查询的成功响应，data 为当前页的数据
+func SuccessPage(c *gin.Context, data interface{}, total int64, nextCursor string) {
+	traceID := getTraceIDFromCtx()
+	c.JSON(200, Response{
+		Code:       0,
+		Message:    "success",
+		Data:       data,
+		Total:      &total,
+		NextCursor: nextCursor,
+		TraceID:    traceID,
+	})
+}
+
 // Error 返回错误响应
 func Error(c *gin.Context, code int, message string) {
 	traceID := getTraceIDFromCtx()
@@ -53,3 +70,14 @@ func Error(c *gin.Context, code int, message string) {
 		TraceID: traceID,
 	})
 }
+
+// ErrorWithData 返回携带数据的错误响应，用于需要调用方根据详情决定下一步操作的错误
+func ErrorWithData(c *gin.Context, code int, message string, data interface{}) {
+	traceID := getTraceIDFromCtx()
+	c.JSON(200, Response{
+		Code:    code,
+		Message: message,
+		Data:    data,
+		TraceID: traceID,
+	})
+}
@@ -1,6 +1,11 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 // LogLevel gets log level
 func LogLevel() string {
@@ -33,3 +38,85 @@ func DBConfigs() map[string]map[string]interface{} {
 func DBConfig(name string) map[string]interface{} {
 	return viper.GetStringMap("dbs." + name)
 }
+
+// MCPSessionIdleTimeout gets the idle timeout after which MCP sessions are closed, 30 minutes by default
+func MCPSessionIdleTimeout() time.Duration {
+	if d := viper.GetDuration("mcp.session_idle_timeout"); d > 0 {
+		return d
+	}
+	return 30 * time.Minute
+}
+
+// MCPSSEResponses reports whether Streamable HTTP requests may be answered with an event stream
+func MCPSSEResponses() bool {
+	return viper.GetBool("mcp.sse_responses")
+}
+
+// SwaggerSyncInterval gets the interval at which documents imported from a URL are re-fetched, 10 minutes by default and 0 to disable
+func SwaggerSyncInterval() time.Duration {
+	if !viper.IsSet("swagger.sync_interval") {
+		return 10 * time.Minute
+	}
+	return viper.GetDuration("swagger.sync_interval")
+}
+
+// SwaggerBreakingChangePolicy gets how re-imports with breaking changes to endpoints published as MCP tools are handled,
+// "block" (the default) rejects them unless forced and "warn" applies them and reports the changes
+func SwaggerBreakingChangePolicy() string {
+	if viper.GetString("swagger.breaking_changes") == "warn" {
+		return "warn"
+	}
+	return "block"
+}
+
+// MigrateOnStartup reports whether pending schema migrations are applied when the server starts, true by default
+func MigrateOnStartup() bool {
+	if !viper.IsSet("migrate.on_startup") {
+		return true
+	}
+	return viper.GetBool("migrate.on_startup")
+}
+
+// TrashRetention gets how long deleted documents and endpoints stay in the trash before they are purged,
+// 30 days by default and 0 to keep them until purged manually
+func TrashRetention() time.Duration {
+	if !viper.IsSet("trash.retention") {
+		return 30 * 24 * time.Hour
+	}
+	return viper.GetDuration("trash.retention")
+}
+
+// SecretKeys gets the master keys secrets are encrypted with, keyed by ID with base64 encoded 32-byte values.
+// The keys may also be given by the SECRETS_KEYS environment variable as "id:key,id:key"
+func SecretKeys() map[string]string {
+	if s := viper.GetString("secrets.keys"); s != "" {
+		keys := make(map[string]string)
+		for _, pair := range strings.Split(s, ",") {
+			if id, key, ok := strings.Cut(strings.TrimSpace(pair), ":"); ok {
+				keys[id] = key
+			}
+		}
+		return keys
+	}
+	return viper.GetStringMapString("secrets.keys")
+}
+
+// SecretTrustedBaseURLs gets the base URLs besides the servers declared by a document that endpoint tests may send secrets to.
+// The URLs may also be given by the SECRETS_TRUSTED_BASE_URLS environment variable separated by commas
+func SecretTrustedBaseURLs() []string {
+	if s := viper.GetString("secrets.trusted_base_urls"); s != "" {
+		var urls []string
+		for _, u := range strings.Split(s, ",") {
+			if u = strings.TrimSpace(u); u != "" {
+				urls = append(urls, u)
+			}
+		}
+		return urls
+	}
+	return viper.GetStringSlice("secrets.trusted_base_urls")
+}
+
+// SecretActiveKey gets the ID of the master key new secrets are encrypted with, optional when only one key is configured
+func SecretActiveKey() string {
+	return viper.GetString("secrets.active_key")
+}
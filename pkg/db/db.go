@@ -19,6 +19,10 @@ type Config struct {
 	MaxIdleConn int    `mapstructure:"max_idle_conn" json:"max_idle_conn"`
 	DebugLog    bool   `mapstructure:"debug_log" json:"debug_log"`
 	Type        string `mapstructure:"type" json:"type"`
+	Path        string `mapstructure:"path" json:"path"`                 // SQLite database file, or ":memory:"
+	BusyTimeout int    `mapstructure:"busy_timeout" json:"busy_timeout"` // SQLite lock wait in milliseconds
+	SSLMode     string `mapstructure:"ssl_mode" json:"ssl_mode"`         // PostgreSQL sslmode, e.g. disable or verify-full
+	SearchPath  string `mapstructure:"search_path" json:"search_path"`   // PostgreSQL schema search path
 }
 
 // DBManager defines the interface for database management
@@ -63,6 +67,10 @@ func DBFactory(config Config) (DBManager, error) {
 	switch config.Type {
 	case "mysql":
 		return NewMySQLManager(config)
+	case "sqlite", "sqlite3":
+		return NewSQLiteManager(config)
+	case "postgres", "postgresql":
+		return NewPostgresManager(config)
 	default:
 		return nil, fmt.Errorf("unsupported database type: %s", config.Type)
 	}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"fmt"
+	"net/url"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// PostgresManager implements DBManager for PostgreSQL
+type PostgresManager struct {
+	config Config
+	db     *gorm.DB
+}
+
+// NewPostgresManager initializes PostgresManager with connection pool
+func NewPostgresManager(config Config) (*PostgresManager, error) {
+	db, err := gorm.Open(postgres.Open(postgresDSN(config)), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	// 设置连接池参数
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(config.MaxOpenConn)
+	sqlDB.SetMaxIdleConns(config.MaxIdleConn)
+	return &PostgresManager{config: config, db: db}, nil
+}
+
+// postgresDSN builds a postgres:// URL from config. SSLMode and SearchPath are only set when configured,
+// so the driver defaults (sslmode=prefer, the server search_path) apply otherwise.
+func postgresDSN(config Config) string {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(config.User, config.Password),
+		Host:   config.Addr,
+		Path:   "/" + config.Name,
+	}
+	params := url.Values{}
+	if config.SSLMode != "" {
+		params.Set("sslmode", config.SSLMode)
+	}
+	if config.SearchPath != "" {
+		params.Set("search_path", config.SearchPath)
+	}
+	u.RawQuery = params.Encode()
+	return u.String()
+}
+
+// Connect returns the pooled *gorm.DB instance
+func (m *PostgresManager) Connect() (*gorm.DB, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return m.db, nil
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteMemory is the path of an in-memory SQLite database
+const sqliteMemory = ":memory:"
+
+// defaultSQLiteBusyTimeout is how long, in milliseconds, a connection waits for a lock held by another one
+const defaultSQLiteBusyTimeout = 5000
+
+// SQLiteManager implements DBManager for SQLite
+type SQLiteManager struct {
+	config Config
+	db     *gorm.DB
+}
+
+// NewSQLiteManager opens the SQLite database at config.Path, or config.Name when no path is set.
+// File databases are created with their parent directory and use WAL mode, so readers do not block the writer;
+// ":memory:" databases live as long as the manager and are shared by all of its queries.
+func NewSQLiteManager(config Config) (*SQLiteManager, error) {
+	path := config.Path
+	if path == "" {
+		path = config.Name
+	}
+	if path == "" {
+		return nil, fmt.Errorf("sqlite path is required")
+	}
+	busyTimeout := config.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = defaultSQLiteBusyTimeout
+	}
+
+	params := url.Values{}
+	params.Set("_busy_timeout", fmt.Sprint(busyTimeout))
+	params.Set("_foreign_keys", "on")
+	// 写事务开始时即获取写锁，避免读事务升级为写事务时因锁冲突直接失败
+	params.Set("_txlock", "immediate")
+	memory := path == sqliteMemory
+	var dsn string
+	if memory {
+		dsn = "file::memory:?" + params.Encode()
+	} else {
+		if dir := filepath.Dir(path); dir != "." {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, fmt.Errorf("create sqlite directory: %w", err)
+			}
+		}
+		params.Set("_journal_mode", "WAL")
+		dsn = "file:" + path + "?" + params.Encode()
+	}
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	// 设置连接池参数
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if memory {
+		// 每个连接各自拥有一个内存数据库，只保留一个常驻连接以共享数据
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	} else {
+		sqlDB.SetMaxOpenConns(config.MaxOpenConn)
+		sqlDB.SetMaxIdleConns(config.MaxIdleConn)
+	}
+	return &SQLiteManager{config: config, db: db}, nil
+}
+
+// Connect returns the pooled *gorm.DB instance
+func (m *SQLiteManager) Connect() (*gorm.DB, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return m.db, nil
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testItem struct {
+	ID   uint
+	Name string
+}
+
+func TestSQLiteManager_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "test.db")
+	manager, err := DBFactory(Config{Type: "sqlite", Path: path, MaxOpenConn: 4, MaxIdleConn: 2})
+	require.NoError(t, err)
+	conn, err := manager.Connect()
+	require.NoError(t, err)
+
+	var mode string
+	require.NoError(t, conn.Raw("PRAGMA journal_mode").Scan(&mode).Error)
+	assert.Equal(t, "wal", mode)
+	var timeout int
+	require.NoError(t, conn.Raw("PRAGMA busy_timeout").Scan(&timeout).Error)
+	assert.Equal(t, defaultSQLiteBusyTimeout, timeout)
+
+	require.NoError(t, conn.AutoMigrate(&testItem{}))
+	require.NoError(t, conn.Create(&testItem{Name: "a"}).Error)
+	assert.FileExists(t, path)
+}
+
+func TestSQLiteManager_Memory(t *testing.T) {
+	manager, err := NewSQLiteManager(Config{Type: "sqlite", Path: ":memory:", BusyTimeout: 1000})
+	require.NoError(t, err)
+	conn, err := manager.Connect()
+	require.NoError(t, err)
+
+	// 所有查询共享同一个内存数据库
+	require.NoError(t, conn.AutoMigrate(&testItem{}))
+	require.NoError(t, conn.Create(&testItem{Name: "a"}).Error)
+	var count int64
+	require.NoError(t, conn.Model(&testItem{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	var timeout int
+	require.NoError(t, conn.Raw("PRAGMA busy_timeout").Scan(&timeout).Error)
+	assert.Equal(t, 1000, timeout)
+}
+
+func TestSQLiteManager_RequiresPath(t *testing.T) {
+	_, err := NewSQLiteManager(Config{Type: "sqlite"})
+	assert.ErrorContains(t, err, "path is required")
+}
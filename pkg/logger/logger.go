@@ -68,6 +68,8 @@ func InitDebugLogger(level string, path string) error {
 		return errors.New("new debug log hook failed")
 	}
 
+	// 脱敏需要在写文件之前完成
+	logrus.AddHook(&RedactHook{Redactor: DefaultRedactor})
 	logrus.AddHook(&RequestIdHook{})
 	logrus.AddHook(lfHookDebug)
 	logrus.SetOutput(ioutil.Discard)
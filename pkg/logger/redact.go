@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Redacted 为脱敏后的占位内容
+const Redacted = "******"
+
+// minRedactLength 为参与脱敏的最短取值，过短的取值会误伤大量正常内容
+const minRedactLength = 4
+
+// Redactor 记录需要脱敏的敏感取值，将其从文本中替换为 Redacted
+type Redactor struct {
+	mu     sync.RWMutex
+	values map[string]struct{}
+	// named 为按名称登记的取值，同名的新取值替换旧取值，可按名称移除
+	named    map[string]string
+	replacer *strings.Replacer
+}
+
+// NewRedactor 创建一个空的 Redactor
+func NewRedactor() *Redactor {
+	return &Redactor{values: make(map[string]struct{}), named: make(map[string]string)}
+}
+
+// DefaultRedactor 为日志脱敏使用的 Redactor，secret 的取值按 secret 名称登记到这里
+var DefaultRedactor = NewRedactor()
+
+// Set 以 name 登记需要脱敏的取值，替换 name 之前登记的取值
+func (r *Redactor) Set(name, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.named[name]; ok && old == value {
+		return
+	}
+	if len(value) < minRedactLength {
+		value = ""
+	}
+	if value == "" {
+		delete(r.named, name)
+	} else {
+		r.named[name] = value
+	}
+	r.replacer = nil
+}
+
+// Remove 移除以 names 登记的取值
+func (r *Redactor) Remove(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		if _, ok := r.named[name]; ok {
+			delete(r.named, name)
+			r.replacer = nil
+		}
+	}
+}
+
+// Add 登记需要脱敏的取值，登记后不能移除
+func (r *Redactor) Add(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := false
+	for _, v := range values {
+		if len(v) < minRedactLength {
+			continue
+		}
+		if _, ok := r.values[v]; !ok {
+			r.values[v] = struct{}{}
+			changed = true
+		}
+	}
+	if changed {
+		r.replacer = nil
+	}
+}
+
+// Redact 将 s 中登记过的取值替换为 Redacted
+func (r *Redactor) Redact(s string) string {
+	if s == "" {
+		return s
+	}
+	r.mu.RLock()
+	replacer := r.replacer
+	empty := len(r.values) == 0 && len(r.named) == 0
+	r.mu.RUnlock()
+	if empty {
+		return s
+	}
+	if replacer == nil {
+		replacer = r.build()
+	}
+	return replacer.Replace(s)
+}
+
+// build 按取值从长到短构造 Replacer，避免较短的取值先替换掉较长取值的一部分
+func (r *Redactor) build() *strings.Replacer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.replacer != nil {
+		return r.replacer
+	}
+	unique := make(map[string]struct{}, len(r.values)+len(r.named))
+	for v := range r.values {
+		unique[v] = struct{}{}
+	}
+	for _, v := range r.named {
+		unique[v] = struct{}{}
+	}
+	values := make([]string, 0, len(unique))
+	for v := range unique {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	pairs := make([]string, 0, 2*len(values))
+	for _, v := range values {
+		pairs = append(pairs, v, Redacted)
+	}
+	r.replacer = strings.NewReplacer(pairs...)
+	return r.replacer
+}
+
+// RedactHook 在日志写出前脱敏消息与字段，需要在写日志的 hook 之前注册
+type RedactHook struct {
+	Redactor *Redactor
+}
+
+func (h *RedactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *RedactHook) Fire(e *logrus.Entry) error {
+	e.Message = h.Redactor.Redact(e.Message)
+	for k, v := range e.Data {
+		switch value := v.(type) {
+		case string:
+			e.Data[k] = h.Redactor.Redact(value)
+		case error:
+			if redacted := h.Redactor.Redact(value.Error()); redacted != value.Error() {
+				e.Data[k] = redacted
+			}
+		}
+	}
+	return nil
+}
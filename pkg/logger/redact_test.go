@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor_Redact(t *testing.T) {
+	r := NewRedactor()
+	assert.Equal(t, "token abcd1234", r.Redact("token abcd1234"))
+
+	r.Add("abcd", "abcd1234", "abc")
+	assert.Equal(t, "token ******, key ******", r.Redact("token abcd1234, key abcd"), "longer values are replaced first")
+	assert.Equal(t, "abc", r.Redact("abc"), "values shorter than 4 bytes are ignored")
+}
+
+func TestRedactor_Named(t *testing.T) {
+	r := NewRedactor()
+	r.Set("TOKEN", "old-token")
+	assert.Equal(t, "******", r.Redact("old-token"))
+
+	// 同名的新取值替换旧取值
+	r.Set("TOKEN", "new-token")
+	assert.Equal(t, "old-token ******", r.Redact("old-token new-token"))
+
+	r.Remove("TOKEN")
+	assert.Equal(t, "new-token", r.Redact("new-token"))
+}
+
+func TestRedactHook(t *testing.T) {
+	r := NewRedactor()
+	r.Add("s3cret-value")
+	var buf bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&buf)
+	log.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+	log.AddHook(&RedactHook{Redactor: r})
+
+	log.WithField("header", "Bearer s3cret-value").
+		WithError(errors.New("upstream rejected s3cret-value")).
+		Warnf("call with s3cret-value failed")
+
+	assert.NotContains(t, buf.String(), "s3cret-value")
+	assert.Contains(t, buf.String(), "Bearer ******")
+}
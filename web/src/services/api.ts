@@ -25,6 +25,14 @@ api.interceptors.response.use(
     // 后台API返回格式为 {code, message, data}
     if (response.data && typeof response.data === 'object' && 'code' in response.data) {
       if (response.data.code === 0) {
+        // 分页查询同时返回总数与下一页游标
+        if ('total' in response.data) {
+          return {
+            items: response.data.data || [],
+            total: response.data.total,
+            next_cursor: response.data.next_cursor,
+          };
+        }
         // 成功的情况下返回data字段
         return response.data.data || response.data;
       } else {
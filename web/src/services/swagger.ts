@@ -3,7 +3,8 @@ import type {
   SwaggerValidationResult, 
   SwaggerParseResult, 
   SwaggerTextRequest, 
-  APIEndpoint 
+  APIEndpoint,
+  Page
 } from '../types/swagger';
 
 export const swaggerService = {
@@ -69,14 +70,20 @@ export const swaggerService = {
     }
   },
 
-  // 获取API接口列表
+  // 获取API接口列表，按游标逐页读取全部接口
   async getEndpoints(swaggerId: number): Promise<APIEndpoint[]> {
     try {
-      const response = await api.get('/swagger/endpoints', {
-        params: { swagger_id: swaggerId }
-      });
-      
-      return (response as unknown as APIEndpoint[]) || [];
+      const endpoints: APIEndpoint[] = [];
+      let cursor: string | undefined;
+      do {
+        const page = (await api.get('/swagger/endpoints', {
+          params: { swagger_id: swaggerId, size: 200, cursor }
+        })) as unknown as Page<APIEndpoint>;
+        endpoints.push(...page.items);
+        cursor = page.next_cursor;
+      } while (cursor);
+
+      return endpoints;
     } catch (error: any) {
       throw new Error(error.message || '获取接口列表失败');
     }
@@ -25,6 +25,13 @@ export interface APIEndpoint {
   updated_at: string;
 }
 
+// 分页查询结果，next_cursor 为空时已是最后一页
+export interface Page<T> {
+  items: T[];
+  total: number;
+  next_cursor?: string;
+}
+
 // Swagger校验结果
 export interface SwaggerValidationResult {
   valid: boolean;